@@ -0,0 +1,72 @@
+package accounts
+
+import (
+	"RainbowRunner/internal/accounts"
+	"RainbowRunner/internal/storage"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var storageFile string
+
+var accountsCommand = &cobra.Command{
+	Use:   "accounts",
+	Short: "Manage login accounts, the server must be stopped while using these commands",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		store, err := storage.NewFileStore(storageFile)
+
+		if err != nil {
+			panic(err)
+		}
+
+		storage.Store = store
+	},
+}
+
+var createCommand = &cobra.Command{
+	Use:  "create <name> <password>",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, err := accounts.Register(args[0], args[1])
+
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("Created account %s\n", args[0])
+	},
+}
+
+var banCommand = &cobra.Command{
+	Use:  "ban <name>",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := accounts.SetBanned(args[0], true); err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("Banned account %s\n", args[0])
+	},
+}
+
+var unbanCommand = &cobra.Command{
+	Use:  "unban <name>",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := accounts.SetBanned(args[0], false); err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("Unbanned account %s\n", args[0])
+	},
+}
+
+func Init(rootCmd *cobra.Command) {
+	accountsCommand.PersistentFlags().StringVarP(&storageFile, "storage-file", "s", "./data/storage/characters.json", "-s data/storage/characters.json")
+
+	accountsCommand.AddCommand(createCommand)
+	accountsCommand.AddCommand(banCommand)
+	accountsCommand.AddCommand(unbanCommand)
+
+	rootCmd.AddCommand(accountsCommand)
+}
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"RainbowRunner/internal/client"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var loginAddress string
+var gameAddress string
+var count int
+var namePrefix string
+var password string
+var serverID uint8
+var duration time.Duration
+var moveInterval time.Duration
+var chatInterval time.Duration
+var originX float32
+var originY float32
+var radius float32
+var speed float32
+
+var botCommand = &cobra.Command{
+	Use:   "bot",
+	Short: "Run scripted bots that log in, enter the world and wander around",
+	Long: "Run scripted bots that log in, enter the world and wander around. Each bot uses the account " +
+		"<name-prefix><number>, the accounts must exist or the server must have accounts.auto_register enabled",
+	Run: func(cmd *cobra.Command, args []string) {
+		wg := sync.WaitGroup{}
+
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+
+			go func(name string) {
+				defer wg.Done()
+
+				if err := runBot(name); err != nil {
+					log.Errorf("bot %s stopped: %s", name, err.Error())
+				}
+			}(fmt.Sprintf("%s%d", namePrefix, i+1))
+		}
+
+		wg.Wait()
+	},
+}
+
+func runBot(name string) error {
+	c := client.NewClient(loginAddress, name, password)
+	c.GameAddress = gameAddress
+	c.ServerID = serverID
+
+	defer c.Close()
+
+	if err := c.Login(); err != nil {
+		return err
+	}
+
+	if err := c.ConnectGame(); err != nil {
+		return err
+	}
+
+	if err := c.EnterWorld(name); err != nil {
+		return err
+	}
+
+	log.Infof("bot %s entered the world", name)
+
+	position := [2]float32{originX, originY}
+	target := randomTarget()
+
+	moveTicker := time.NewTicker(moveInterval)
+	defer moveTicker.Stop()
+
+	var chat <-chan time.Time
+
+	if chatInterval > 0 {
+		chatTicker := time.NewTicker(chatInterval)
+		defer chatTicker.Stop()
+
+		chat = chatTicker.C
+	}
+
+	var done <-chan time.Time
+
+	if duration > 0 {
+		done = time.After(duration)
+	}
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-chat:
+			if err := c.Chat(fmt.Sprintf("%s is at %.0f, %.0f", name, position[0], position[1])); err != nil {
+				return err
+			}
+		case <-moveTicker.C:
+			dx := target[0] - position[0]
+			dy := target[1] - position[1]
+			distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+			heading := float32(math.Atan2(float64(dy), float64(dx)) * 180 / math.Pi)
+			step := speed * float32(moveInterval.Seconds())
+
+			updateType := client.MoveUpdateForward
+
+			if distance <= step {
+				position = target
+				target = randomTarget()
+				updateType = client.MoveUpdateStop
+			} else {
+				position[0] += dx / distance * step
+				position[1] += dy / distance * step
+			}
+
+			if err := c.Move(position[0], position[1], heading, updateType); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func randomTarget() [2]float32 {
+	angle := rand.Float64() * math.Pi * 2
+	distance := rand.Float64() * float64(radius)
+
+	return [2]float32{
+		originX + float32(math.Cos(angle)*distance),
+		originY + float32(math.Sin(angle)*distance),
+	}
+}
+
+func Init(rootCmd *cobra.Command) {
+	botCommand.Flags().StringVarP(&loginAddress, "login-address", "a", "127.0.0.1:2110", "-a 127.0.0.1:2110")
+	botCommand.Flags().StringVarP(&gameAddress, "game-address", "g", "", "override the game server address from the server list")
+	botCommand.Flags().IntVarP(&count, "count", "n", 1, "-n 10")
+	botCommand.Flags().StringVar(&namePrefix, "name-prefix", "bot", "account and character name prefix")
+	botCommand.Flags().StringVarP(&password, "password", "p", "bot", "password used for every bot account")
+	botCommand.Flags().Uint8Var(&serverID, "server-id", 0, "server to join from the server list")
+	botCommand.Flags().DurationVarP(&duration, "duration", "d", 0, "how long to run the bots for, 0 runs until stopped")
+	botCommand.Flags().DurationVar(&moveInterval, "move-interval", 250*time.Millisecond, "time between move updates")
+	botCommand.Flags().DurationVar(&chatInterval, "chat-interval", 0, "time between chat messages, 0 disables chat")
+	botCommand.Flags().Float32Var(&originX, "origin-x", 0, "x position the bots wander around, should be where the zone spawns players")
+	botCommand.Flags().Float32Var(&originY, "origin-y", 0, "y position the bots wander around, should be where the zone spawns players")
+	botCommand.Flags().Float32Var(&radius, "radius", 100, "distance from the origin the bots wander")
+	botCommand.Flags().Float32Var(&speed, "speed", 100, "distance moved per second")
+
+	rootCmd.AddCommand(botCommand)
+}
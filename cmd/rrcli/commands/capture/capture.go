@@ -0,0 +1,51 @@
+package capture
+
+import (
+	"RainbowRunner/internal/capture"
+	"github.com/spf13/cobra"
+)
+
+var server string
+var direction string
+var connectionID int
+var channel string
+var messageType int
+
+var captureCommand = &cobra.Command{
+	Use:   "capture",
+	Short: "Inspect and replay traffic captured by the servers when capture.enabled is set",
+}
+
+func newFilter() (capture.Filter, error) {
+	filter := capture.NewFilter()
+	filter.Server = capture.Server(server)
+	filter.Direction = capture.Direction(direction)
+	filter.ConnectionID = connectionID
+	filter.MessageType = messageType
+
+	if channel != "" {
+		parsed, err := capture.ParseChannel(channel)
+
+		if err != nil {
+			return filter, err
+		}
+
+		filter.Channel = parsed
+	}
+
+	return filter, nil
+}
+
+func Init(rootCmd *cobra.Command) {
+	captureCommand.PersistentFlags().IntVarP(&connectionID, "connection", "c", -1, "only include frames from this connection ID")
+	captureCommand.PersistentFlags().StringVar(&channel, "channel", "", "only include channel messages for this channel name or number e.g. ZoneChannel")
+	captureCommand.PersistentFlags().IntVarP(&messageType, "type", "t", -1, "only include this message type, for channel messages this is the type within the channel e.g. 0x34")
+
+	initListCommand()
+	initReplayCommand()
+
+	captureCommand.AddCommand(listCommand)
+	captureCommand.AddCommand(replayCommand)
+
+	rootCmd.AddCommand(captureCommand)
+}
@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"RainbowRunner/internal/capture"
+	"RainbowRunner/internal/message"
+	"encoding/hex"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var dumpBodies bool
+
+var listCommand = &cobra.Command{
+	Use:   "list <capture file>",
+	Short: "List the captured frames",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		frames, err := capture.ReadFile(args[0])
+
+		if err != nil {
+			panic(err)
+		}
+
+		filter, err := newFilter()
+
+		if err != nil {
+			panic(err)
+		}
+
+		if len(frames) == 0 {
+			return
+		}
+
+		start := frames[0].Time
+
+		for _, frame := range filter.Apply(frames) {
+			fmt.Printf(
+				"%10.3fs %-5s #%-4d %-3s %s (%d bytes)\n",
+				frame.Time.Sub(start).Seconds(), frame.Server, frame.ConnectionID, frame.Direction,
+				describeFrame(frame), len(frame.Body),
+			)
+
+			if dumpBodies {
+				fmt.Print(hex.Dump(frame.Body))
+			}
+		}
+	},
+}
+
+func describeFrame(frame *capture.Frame) string {
+	if frame.Server == capture.LoginServer {
+		if frame.Direction == capture.Inbound {
+			return message.AuthClientMessage(frame.MessageType).String()
+		}
+
+		return message.AuthServerMessage(frame.MessageType).String()
+	}
+
+	if frame.IsChannelMessage {
+		return fmt.Sprintf("%02x [%s-0x%x]", frame.PacketType, frame.Channel.String(), frame.ChannelMessageType)
+	}
+
+	return fmt.Sprintf("%02x dest 0x%x type 0x%x", frame.PacketType, frame.Dest, frame.MessageType)
+}
+
+func initListCommand() {
+	listCommand.Flags().StringVarP(&server, "server", "s", "", "only include frames from the login or game server")
+	listCommand.Flags().StringVarP(&direction, "direction", "d", "", "only include in or out frames")
+	listCommand.Flags().BoolVarP(&dumpBodies, "dump", "x", false, "hex dump the body of every frame")
+}
@@ -0,0 +1,195 @@
+package capture
+
+import (
+	"RainbowRunner/internal/capture"
+	"RainbowRunner/internal/client"
+	"RainbowRunner/internal/connections"
+	"RainbowRunner/internal/database"
+	"RainbowRunner/internal/game"
+	"RainbowRunner/internal/logging"
+	"RainbowRunner/internal/login"
+	"RainbowRunner/internal/lua"
+	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
+	"RainbowRunner/pkg/byter"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"net"
+	"sort"
+	"time"
+)
+
+var loginAddress string
+var gameAddress string
+var username string
+var password string
+var serverID uint8
+var inProcess bool
+var speed float64
+var replayWait time.Duration
+
+var replayCommand = &cobra.Command{
+	Use:   "replay <capture file>",
+	Short: "Replay the messages a client sent to the game server",
+	Long: "Logs in as a new client and replays the messages one captured game connection sent to the game server. " +
+		"Entity IDs are only the same as the original session when replaying against a fresh server, use --in-process " +
+		"to start one with in-memory storage from the config in the working directory.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		frames, err := capture.ReadFile(args[0])
+
+		if err != nil {
+			panic(err)
+		}
+
+		filter, err := newFilter()
+
+		if err != nil {
+			panic(err)
+		}
+
+		filter.Server = capture.GameServer
+		filter.Direction = capture.Inbound
+
+		frames = filter.Apply(frames)
+
+		if err = checkSingleConnection(frames); err != nil {
+			panic(err)
+		}
+
+		if inProcess {
+			loginAddress = startInProcessServer()
+		}
+
+		c := client.NewClient(loginAddress, username, password)
+		c.GameAddress = gameAddress
+		c.ServerID = serverID
+
+		defer c.Close()
+
+		if err = c.Login(); err != nil {
+			panic(err)
+		}
+
+		if err = c.ConnectGame(); err != nil {
+			panic(err)
+		}
+
+		if err = replay(c, frames); err != nil {
+			panic(err)
+		}
+
+		// Give the server time to handle everything before disconnecting
+		time.Sleep(replayWait)
+	},
+}
+
+func replay(c *client.Client, frames []*capture.Frame) error {
+	var previous *capture.Frame
+
+	for _, frame := range frames {
+		// The captured one time key cannot be reused, the client has already authenticated with its own
+		if frame.PacketType == 0x0a && frame.MessageType == 0x00 {
+			continue
+		}
+
+		if previous != nil && speed > 0 {
+			time.Sleep(time.Duration(float64(frame.Time.Sub(previous.Time)) / speed))
+		}
+
+		previous = frame
+
+		log.Infof("replaying %s", describeFrame(frame))
+
+		switch frame.PacketType {
+		case 0x0a:
+			connections.WriteCompressedA(c, frame.Dest, frame.MessageType, byter.NewLEByter(frame.Body))
+		case 0x0e, 0x06:
+			if err := c.SendChannelMessage(byter.NewLEByter(frame.Body)); err != nil {
+				return err
+			}
+		default:
+			log.Errorf("cannot replay packet type %x", frame.PacketType)
+		}
+	}
+
+	return nil
+}
+
+func checkSingleConnection(frames []*capture.Frame) error {
+	ids := make(map[int]bool)
+
+	for _, frame := range frames {
+		ids[frame.ConnectionID] = true
+	}
+
+	if len(ids) == 0 {
+		return errors.New("no game messages sent by a client match the filter")
+	}
+
+	if len(ids) > 1 {
+		sorted := make([]int, 0, len(ids))
+
+		for id := range ids {
+			sorted = append(sorted, id)
+		}
+
+		sort.Ints(sorted)
+
+		return errors.New(fmt.Sprintf("capture contains connections %v, choose one with --connection", sorted))
+	}
+
+	return nil
+}
+
+// startInProcessServer starts the login and game servers the same way as the server binary but with in-memory
+// storage and auto registration so the replay always starts from a clean state
+func startInProcessServer() string {
+	serverconfig.Load()
+	serverconfig.Config.Storage.Type = "memory"
+	serverconfig.Config.Accounts.AutoRegister = true
+
+	logging.Init()
+
+	if err := lua.LoadScripts("./lua"); err != nil {
+		panic(err)
+	}
+
+	database.LoadEquipmentFixtures()
+	database.LoadConfigFiles()
+	storage.Init()
+
+	go login.StartLoginServer()
+	go game.StartGameServer()
+
+	objects.Init()
+
+	address := fmt.Sprintf("127.0.0.1:%d", serverconfig.Config.Network.LoginServerPort)
+
+	for i := 0; i < 50; i++ {
+		conn, err := net.Dial("tcp", address)
+
+		if err == nil {
+			conn.Close()
+			return address
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	panic(fmt.Sprintf("in-process login server did not start on %s", address))
+}
+
+func initReplayCommand() {
+	replayCommand.Flags().StringVarP(&loginAddress, "login-address", "a", "127.0.0.1:2110", "-a 127.0.0.1:2110")
+	replayCommand.Flags().StringVarP(&gameAddress, "game-address", "g", "", "override the game server address from the server list")
+	replayCommand.Flags().StringVarP(&username, "username", "u", "replay", "account used to replay the session")
+	replayCommand.Flags().StringVarP(&password, "password", "p", "replay", "password for the replay account")
+	replayCommand.Flags().Uint8Var(&serverID, "server-id", 0, "server to join from the server list")
+	replayCommand.Flags().BoolVar(&inProcess, "in-process", false, "start the servers in this process instead of connecting to a running server")
+	replayCommand.Flags().Float64Var(&speed, "speed", 1, "replay speed multiplier, 0 sends everything without waiting")
+	replayCommand.Flags().DurationVar(&replayWait, "wait", 2*time.Second, "time to wait after the last message before disconnecting")
+}
@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"RainbowRunner/cmd/rrcli/commands/accounts"
+	"RainbowRunner/cmd/rrcli/commands/bot"
+	"RainbowRunner/cmd/rrcli/commands/capture"
 	"RainbowRunner/cmd/rrcli/commands/config"
 	"RainbowRunner/cmd/rrcli/commands/models"
+	"RainbowRunner/cmd/rrcli/commands/pathmap"
 	"fmt"
 	"github.com/spf13/cobra"
 	"os"
@@ -13,8 +17,12 @@ var rootCmd = &cobra.Command{
 }
 
 func Init() {
+	accounts.Init(rootCmd)
+	bot.Init(rootCmd)
+	capture.Init(rootCmd)
 	config.Init(rootCmd)
 	models.Init(rootCmd)
+	pathmap.Init(rootCmd)
 }
 
 func Execute() {
@@ -0,0 +1,185 @@
+package pathmap
+
+import (
+	"RainbowRunner/cmd/rrcli/configurator"
+	"RainbowRunner/cmd/rrcli/pathmapbuilder"
+	"RainbowRunner/internal/types/drconfigtypes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var outputDir string
+var overwrite bool
+
+var errPathMapExists = errors.New("pathmap already exists, use --overwrite to replace it")
+var errUnknownZone = errors.New("zone is not in the worlds config")
+
+var buildCommand = &cobra.Command{
+	Use:   "build [zone...]",
+	Short: "Generate pathmaps from the terrain placed in the world configs, builds every zone if none are given",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := configurator.LoadFromDumpedConfigFile(configFile)
+
+		if err != nil {
+			return err
+		}
+
+		worldsConfig, err := configurator.LoadFromDumpedConfigFile(worldsFile)
+
+		if err != nil {
+			return err
+		}
+
+		tilesConfig, err := configurator.LoadFromDumpedConfigFile(tilesFile)
+
+		if err != nil {
+			return err
+		}
+
+		zones := make([]string, 0)
+
+		if len(args) > 0 {
+			for _, zone := range args {
+				zones = append(zones, strings.ToLower(zone))
+			}
+		} else {
+			for zone := range worldsConfig.Classes.Children {
+				zones = append(zones, zone)
+			}
+
+			sort.Strings(zones)
+		}
+
+		if err := os.MkdirAll(filepath.Join(outputDir, "tiles"), 0755); err != nil {
+			return err
+		}
+
+		builder := pathmapbuilder.NewBuilder(config, modelSourceDir)
+		failed := make([]*pathmapbuilder.Result, 0)
+		failedTiles := make([]*pathmapbuilder.Result, 0)
+		tiles := make(map[string]bool)
+		built := 0
+
+		for _, zone := range zones {
+			result := buildZone(builder, worldsConfig.Classes.Children[zone], zone)
+
+			// Generated zones are laid out by the client from the zone seed, the server doesn't know the layout so
+			// the zone isn't built and only its tiles are written
+			if errors.Is(result.Err, pathmapbuilder.ErrGeneratedWorld) {
+				tileResults, err := builder.BuildTiles(worldsConfig.Classes.Children[zone].Entities[0], tilesConfig)
+
+				if err != nil {
+					result.Err = errors.New(fmt.Sprintf("%s: %s", result.Err.Error(), err.Error()))
+				} else {
+					failedTiles = append(failedTiles, writeTiles(tileResults, tiles)...)
+					result.Err = errors.New(fmt.Sprintf("%s, its %d tiles were built on their own", result.Err.Error(), len(tileResults)))
+				}
+			}
+
+			if result.Err != nil {
+				failed = append(failed, result)
+				continue
+			}
+
+			built++
+			fmt.Printf("Built %s from %d placements\n", zone, result.Placements)
+
+			if len(result.MissingModels) > 0 {
+				sort.Strings(result.MissingModels)
+				fmt.Printf("  missing collision models: %s\n", strings.Join(result.MissingModels, ", "))
+			}
+		}
+
+		printReport("Zones that were not built", failed)
+		printReport("Tiles that were not built", failedTiles)
+		fmt.Printf("\nBuilt %d of %d zones\n", built, len(zones))
+
+		return nil
+	},
+}
+
+func buildZone(builder *pathmapbuilder.Builder, worldGroup *drconfigtypes.DRClassChildGroup, zone string) *pathmapbuilder.Result {
+	if worldGroup == nil || len(worldGroup.Entities) == 0 {
+		return &pathmapbuilder.Result{Zone: zone, Err: errUnknownZone}
+	}
+
+	filePath := filepath.Join(outputDir, zone+"_pathmap.json")
+
+	if _, err := os.Stat(filePath); err == nil && !overwrite {
+		return &pathmapbuilder.Result{Zone: zone, Err: errPathMapExists}
+	}
+
+	result := builder.Build(zone, worldGroup.Entities[0])
+
+	if result.Err == nil {
+		result.Err = writePathMap(filePath, result)
+	}
+
+	return result
+}
+
+// writeTiles writes the tiles that haven't been written for an earlier zone and returns the ones that failed, tiles
+// are shared between zones so each is only written once
+func writeTiles(results []*pathmapbuilder.Result, written map[string]bool) []*pathmapbuilder.Result {
+	failed := make([]*pathmapbuilder.Result, 0)
+
+	for _, result := range results {
+		if written[result.Zone] {
+			continue
+		}
+
+		written[result.Zone] = true
+
+		if result.Err == nil {
+			result.Err = writePathMap(filepath.Join(outputDir, "tiles", result.Zone+"_pathmap.json"), result)
+		}
+
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+func writePathMap(filePath string, result *pathmapbuilder.Result) error {
+	if _, err := os.Stat(filePath); err == nil && !overwrite {
+		return errPathMapExists
+	}
+
+	data, err := json.Marshal(result.PathMap)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0644)
+}
+
+func printReport(heading string, failed []*pathmapbuilder.Result) {
+	if len(failed) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", heading)
+
+	for _, result := range failed {
+		fmt.Printf("  %-40s %s\n", result.Zone, result.Err.Error())
+
+		if len(result.MissingModels) > 0 {
+			sort.Strings(result.MissingModels)
+			fmt.Printf("  %-40s missing collision models: %s\n", "", strings.Join(result.MissingModels, ", "))
+		}
+	}
+}
+
+func initBuildCommand() {
+	buildCommand.Flags().StringVarP(&outputDir, "output-dir", "o", filepath.Join("data", "pathmaps"), "-o data\\pathmaps")
+	buildCommand.Flags().BoolVar(&overwrite, "overwrite", false, "--overwrite")
+}
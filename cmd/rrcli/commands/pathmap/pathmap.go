@@ -0,0 +1,31 @@
+package pathmap
+
+import "github.com/spf13/cobra"
+
+var configFile string
+var worldsFile string
+var tilesFile string
+var modelSourceDir string
+
+var pathMapCommand = &cobra.Command{
+	Use:   "pathmap",
+	Short: "Pathmap tools",
+}
+
+func Init(rootCmd *cobra.Command) {
+	pathMapCommand.PersistentFlags().StringVarP(&configFile, "input-config-file", "f", "resources/Dumps/generated/finalconf.json", "-f config\\finalconf.json")
+	pathMapCommand.PersistentFlags().StringVarP(&worldsFile, "worlds-config-file", "w", "resources/Dumps/generated/worlds.json", "-w config\\worlds.json")
+	pathMapCommand.PersistentFlags().StringVarP(&tilesFile, "tiles-config-file", "t", "resources/Dumps/generated/tiles.json", "-t config\\tiles.json")
+	pathMapCommand.PersistentFlags().StringVarP(&modelSourceDir, "models-source-dir", "d", "", "-d C:\\DRExtracted3DNodes")
+
+	err := cobra.MarkFlagRequired(pathMapCommand.PersistentFlags(), "models-source-dir")
+
+	if err != nil {
+		panic(err)
+	}
+
+	rootCmd.AddCommand(pathMapCommand)
+
+	initBuildCommand()
+	pathMapCommand.AddCommand(buildCommand)
+}
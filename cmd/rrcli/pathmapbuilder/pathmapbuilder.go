@@ -0,0 +1,332 @@
+package pathmapbuilder
+
+import (
+	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/types"
+	"RainbowRunner/internal/types/drconfigtypes"
+	"RainbowRunner/internal/types/drobjecttypes"
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrGeneratedWorld = errors.New("generated maze world, the client lays out its tiles from the zone seed")
+var ErrNoTerrain = errors.New("world map has no terrain placements")
+var ErrNoCollision = errors.New("none of the terrain collision models could be loaded")
+var ErrNoWalkableSurface = errors.New("collision models have no walkable surfaces")
+
+// Placement is a single terrain object from a world's map, Position is the centre of the collision model
+type Placement struct {
+	GCType   string
+	Position datatypes.Vector3Float32
+	Heading  float32
+}
+
+// Result is the outcome of building a single zone, PathMap is nil when Err is set
+type Result struct {
+	Zone          string
+	PathMap       *types.PathMap
+	Placements    int
+	MissingModels []string
+	Err           error
+}
+
+// Builder turns the terrain placed in the extracted world configs into pathmaps, the collision models are read from
+// the same .3dnode files `rrcli models` uses
+type Builder struct {
+	Config    *drconfigtypes.DRConfig
+	ModelsDir string
+
+	modelFiles map[string]string
+	models     map[string][]triangle
+}
+
+func (b *Builder) Build(zone string, world *drconfigtypes.DRClass) *Result {
+	result := &Result{Zone: zone}
+
+	if world.Properties != nil && strings.ToLower(world.Properties["Generated"]) == "true" {
+		result.Err = ErrGeneratedWorld
+		return result
+	}
+
+	placements := WorldPlacements(world)
+	result.Placements = len(placements)
+
+	if len(placements) == 0 {
+		result.Err = ErrNoTerrain
+		return result
+	}
+
+	triangles := make([]triangle, 0)
+	missing := make(map[string]bool)
+
+	for _, placement := range placements {
+		collisionObject, err := b.collisionObject(placement.GCType)
+
+		if err != nil {
+			missing[placement.GCType] = true
+			continue
+		}
+
+		// Lights, sounds and other markers don't have any collision
+		if collisionObject == "" {
+			continue
+		}
+
+		model, err := b.loadModel(collisionObject)
+
+		if err != nil {
+			missing[collisionObject] = true
+			continue
+		}
+
+		for _, tri := range model {
+			triangles = append(triangles, tri.place(placement))
+		}
+	}
+
+	for name := range missing {
+		result.MissingModels = append(result.MissingModels, name)
+	}
+
+	if len(triangles) == 0 {
+		result.Err = ErrNoCollision
+		return result
+	}
+
+	result.PathMap = rasterise(triangles)
+
+	if result.PathMap == nil {
+		result.Err = ErrNoWalkableSurface
+	}
+
+	return result
+}
+
+// WorldPlacements finds every object placed in the world object groups of the world's map
+func WorldPlacements(world *drconfigtypes.DRClass) []Placement {
+	placements := make([]Placement, 0)
+
+	if world.Children == nil || world.Children["map"] == nil {
+		return placements
+	}
+
+	for _, mapEntity := range world.Children["map"].Entities {
+		for _, group := range mapEntity.Children {
+			for _, groupEntity := range group.Entities {
+				placements = append(placements, groupPlacements(groupEntity)...)
+			}
+		}
+	}
+
+	return placements
+}
+
+func groupPlacements(group *drconfigtypes.DRClass) []Placement {
+	placements := make([]Placement, 0)
+
+	for _, child := range group.Children {
+		for _, entity := range child.Entities {
+			if _, ok := entity.Properties["Position"]; !ok || entity.Extends == "" {
+				continue
+			}
+
+			placement := Placement{
+				GCType:   entity.Extends,
+				Position: entity.Properties.Vector3Val("Position"),
+			}
+
+			if heading, err := strconv.ParseFloat(entity.Properties["Heading"], 32); err == nil {
+				placement.Heading = float32(heading)
+			}
+
+			placements = append(placements, placement)
+		}
+	}
+
+	return placements
+}
+
+func (b *Builder) collisionObject(gcType string) (string, error) {
+	groups, err := b.Config.Get(gcType)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(groups) == 0 || len(groups[0].Entities) == 0 {
+		return "", errors.New(fmt.Sprintf("%s has no entities", gcType))
+	}
+
+	entity := groups[0].Entities[0]
+
+	if entity.Children == nil || entity.Children["description"] == nil {
+		return "", nil
+	}
+
+	if len(entity.Children["description"].Entities) == 0 {
+		return "", errors.New(fmt.Sprintf("%s has an empty description", gcType))
+	}
+
+	props := entity.Children["description"].Entities[0].Properties
+
+	return strings.Trim(props["CollisionObject"], "\"'"), nil
+}
+
+func (b *Builder) loadModel(name string) (model []triangle, err error) {
+	if model, ok := b.models[strings.ToLower(name)]; ok {
+		return model, nil
+	}
+
+	if b.modelFiles == nil {
+		if err := b.indexModels(); err != nil {
+			return nil, err
+		}
+	}
+
+	filePath, ok := b.modelFiles[strings.ToLower(name)]
+
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("could not find %s.3dnode", name))
+	}
+
+	data, err := os.ReadFile(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The .3dnode reader panics on data it doesn't understand yet
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(fmt.Sprintf("could not read %s: %v", filePath, r))
+		}
+	}()
+
+	node := objects.ReadData(byter.NewLEByter(data))
+
+	if node == nil {
+		return nil, errors.New(fmt.Sprintf("could not read %s", filePath))
+	}
+
+	model = make([]triangle, 0)
+	addMeshTriangles(node, datatypes.Vector3Float32{}, &model)
+
+	b.models[strings.ToLower(name)] = model
+
+	return model, nil
+}
+
+func (b *Builder) indexModels() error {
+	entries, err := os.ReadDir(b.ModelsDir)
+
+	if err != nil {
+		return err
+	}
+
+	b.modelFiles = make(map[string]string)
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(name), ".3dnode") {
+			continue
+		}
+
+		b.modelFiles[strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))] = filepath.Join(b.ModelsDir, name)
+	}
+
+	return nil
+}
+
+// addMeshTriangles collects the triangles of every mesh, node translations are applied the same way as the model
+// extractor so the pathmaps line up with the exported models
+func addMeshTriangles(node drobjecttypes.DRObject, offset datatypes.Vector3Float32, model *[]triangle) {
+	if d3Node, ok := node.(*objects.DFC3DNode); ok {
+		offset = offset.Add(datatypes.Vector3Float32{
+			X: d3Node.Matrix.Values[0],
+			Y: d3Node.Matrix.Values[1],
+			Z: d3Node.Matrix.Values[2],
+		})
+	}
+
+	for _, object := range node.Children() {
+		if mesh, ok := object.(*objects.DFC3DStaticMeshNode); ok {
+			addMesh(mesh, offset, model)
+		} else if d3Node, ok := object.(*objects.DFC3DNode); ok {
+			addMeshTriangles(d3Node, offset, model)
+		}
+	}
+}
+
+func addMesh(mesh *objects.DFC3DStaticMeshNode, offset datatypes.Vector3Float32, model *[]triangle) {
+	for i := 0; i+2 < len(mesh.Triangles); i += 3 {
+		tri := triangle{}
+
+		for j := 0; j < 3; j++ {
+			index := int(mesh.Triangles[i+j])
+
+			if index >= len(mesh.Verts) {
+				return
+			}
+
+			tri[j] = mesh.Verts[index].Add(offset)
+		}
+
+		*model = append(*model, tri)
+	}
+}
+
+type triangle [3]datatypes.Vector3Float32
+
+// place rotates the triangle by the placement heading around the model centre and moves it into the world
+func (t triangle) place(placement Placement) triangle {
+	radians := float64(placement.Heading) * math.Pi / 180
+	sin := float32(math.Sin(radians))
+	cos := float32(math.Cos(radians))
+
+	placed := triangle{}
+
+	for i, vert := range t {
+		placed[i] = datatypes.Vector3Float32{
+			X: vert.X*cos - vert.Y*sin,
+			Y: vert.X*sin + vert.Y*cos,
+			Z: vert.Z,
+		}.Add(placement.Position)
+	}
+
+	return placed
+}
+
+func (t triangle) normal() datatypes.Vector3Float32 {
+	a := t[1].Sub(t[0])
+	b := t[2].Sub(t[0])
+
+	normal := datatypes.Vector3Float32{
+		X: a.Y*b.Z - a.Z*b.Y,
+		Y: a.Z*b.X - a.X*b.Z,
+		Z: a.X*b.Y - a.Y*b.X,
+	}
+
+	length := float32(math.Sqrt(float64(normal.X*normal.X + normal.Y*normal.Y + normal.Z*normal.Z)))
+
+	if length == 0 {
+		return normal
+	}
+
+	return normal.DivideByFloat32(length)
+}
+
+func NewBuilder(config *drconfigtypes.DRConfig, modelsDir string) *Builder {
+	return &Builder{
+		Config:    config,
+		ModelsDir: modelsDir,
+		models:    make(map[string][]triangle),
+	}
+}
@@ -0,0 +1,269 @@
+package pathmapbuilder
+
+import (
+	"RainbowRunner/internal/types"
+	"RainbowRunner/pkg/datatypes"
+	"math"
+)
+
+const (
+	// Pathmap cells are 10 world units wide, nodes are stored in chunks of 16x16 cells
+	cellSize  = 10
+	chunkSize = 16
+
+	// Points sampled along each axis of a cell when projecting triangles onto the grid
+	cellSamples = 4
+
+	// Surfaces steeper than ~50 degrees are walls
+	minWalkableNormalZ = 0.64
+
+	// Walls lower than this above the floor can be stepped over, anything up to unitHeight blocks the cell
+	maxStepHeight = 20
+	unitHeight    = 60
+)
+
+type cell struct {
+	hasFloor bool
+	floor    float32
+	blocked  bool
+}
+
+type grid struct {
+	minX, minY    float32
+	width, height int
+	cells         []cell
+}
+
+func (g *grid) at(x, y int) *cell {
+	if x < 0 || y < 0 || x >= g.width || y >= g.height {
+		return nil
+	}
+
+	return &g.cells[x+y*g.width]
+}
+
+// rasterise projects the triangles onto a grid of cells, a cell is walkable when it has a floor that isn't blocked by
+// a wall and doesn't drop or climb more than a step to any of its neighbours
+func rasterise(triangles []triangle) *types.PathMap {
+	walkable := make([]bool, len(triangles))
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX, maxY := float32(-math.MaxFloat32), float32(-math.MaxFloat32)
+	hasWalkable := false
+
+	for i, tri := range triangles {
+		walkable[i] = math.Abs(float64(tri.normal().Z)) >= minWalkableNormalZ
+
+		if !walkable[i] {
+			continue
+		}
+
+		hasWalkable = true
+
+		for _, vert := range tri {
+			minX = float32(math.Min(float64(minX), float64(vert.X)))
+			minY = float32(math.Min(float64(minY), float64(vert.Y)))
+			maxX = float32(math.Max(float64(maxX), float64(vert.X)))
+			maxY = float32(math.Max(float64(maxY), float64(vert.Y)))
+		}
+	}
+
+	if !hasWalkable {
+		return nil
+	}
+
+	// Rounded up to whole chunks so the grid lines up with the stored pathmap
+	width := int(math.Ceil(float64(maxX-minX)/(cellSize*chunkSize))) * chunkSize
+	height := int(math.Ceil(float64(maxY-minY)/(cellSize*chunkSize))) * chunkSize
+
+	g := &grid{
+		minX:   float32(math.Floor(float64(minX)/cellSize) * cellSize),
+		minY:   float32(math.Floor(float64(minY)/cellSize) * cellSize),
+		width:  int(math.Max(float64(width), chunkSize)) + chunkSize,
+		height: int(math.Max(float64(height), chunkSize)) + chunkSize,
+	}
+
+	g.cells = make([]cell, g.width*g.height)
+
+	for i, tri := range triangles {
+		if walkable[i] {
+			g.sample(tri, func(c *cell, z float32) {
+				if !c.hasFloor || z > c.floor {
+					c.floor = z
+					c.hasFloor = true
+				}
+			})
+		}
+	}
+
+	// Walls are only checked once every floor is known
+	for i, tri := range triangles {
+		if !walkable[i] {
+			g.sampleSurface(tri, func(c *cell, z float32) {
+				if c.hasFloor && z > c.floor+maxStepHeight && z < c.floor+unitHeight {
+					c.blocked = true
+				}
+			})
+		}
+	}
+
+	g.blockLedges()
+
+	return g.toPathMap()
+}
+
+// sample calls f for every sample point of the cells the triangle covers with the height of the triangle at that point
+func (g *grid) sample(tri triangle, f func(c *cell, z float32)) {
+	triMinX := math.Min(float64(tri[0].X), math.Min(float64(tri[1].X), float64(tri[2].X)))
+	triMinY := math.Min(float64(tri[0].Y), math.Min(float64(tri[1].Y), float64(tri[2].Y)))
+	triMaxX := math.Max(float64(tri[0].X), math.Max(float64(tri[1].X), float64(tri[2].X)))
+	triMaxY := math.Max(float64(tri[0].Y), math.Max(float64(tri[1].Y), float64(tri[2].Y)))
+
+	step := float64(cellSize) / cellSamples
+	startX := int(math.Floor((triMinX - float64(g.minX)) / step))
+	startY := int(math.Floor((triMinY - float64(g.minY)) / step))
+	endX := int(math.Ceil((triMaxX - float64(g.minX)) / step))
+	endY := int(math.Ceil((triMaxY - float64(g.minY)) / step))
+
+	for sy := startY; sy <= endY; sy++ {
+		for sx := startX; sx <= endX; sx++ {
+			px := float64(g.minX) + (float64(sx)+0.5)*step
+			py := float64(g.minY) + (float64(sy)+0.5)*step
+
+			z, ok := tri.heightAt(float32(px), float32(py))
+
+			if !ok || sx < 0 || sy < 0 {
+				continue
+			}
+
+			if c := g.at(sx/cellSamples, sy/cellSamples); c != nil {
+				f(c, z)
+			}
+		}
+	}
+}
+
+// sampleSurface walks the triangle in 3D, walls are close to vertical so they have no area when seen from above
+func (g *grid) sampleSurface(tri triangle, f func(c *cell, z float32)) {
+	edgeA := tri[1].Sub(tri[0])
+	edgeB := tri[2].Sub(tri[0])
+	longest := math.Max(vectorLength(edgeA), math.Max(vectorLength(edgeB), vectorLength(tri[2].Sub(tri[1]))))
+	steps := int(math.Ceil(longest/(cellSize/cellSamples))) + 1
+
+	for i := 0; i <= steps; i++ {
+		for j := 0; i+j <= steps; j++ {
+			point := tri[0].
+				Add(edgeA.MultiplyByFloat32(float32(i) / float32(steps))).
+				Add(edgeB.MultiplyByFloat32(float32(j) / float32(steps)))
+
+			x := int(math.Floor(float64(point.X-g.minX) / cellSize))
+			y := int(math.Floor(float64(point.Y-g.minY) / cellSize))
+
+			if c := g.at(x, y); c != nil {
+				f(c, point.Z)
+			}
+		}
+	}
+}
+
+// blockLedges stops cliff edges and roofs being joined to the ground below them
+func (g *grid) blockLedges() {
+	ledges := make([]bool, len(g.cells))
+
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			c := g.at(x, y)
+
+			if !c.hasFloor || c.blocked {
+				continue
+			}
+
+			for _, neighbour := range []*cell{g.at(x+1, y), g.at(x-1, y), g.at(x, y+1), g.at(x, y-1)} {
+				if neighbour != nil && neighbour.hasFloor && math.Abs(float64(neighbour.floor-c.floor)) > maxStepHeight {
+					ledges[x+y*g.width] = true
+					break
+				}
+			}
+		}
+	}
+
+	for i, ledge := range ledges {
+		if ledge {
+			g.cells[i].blocked = true
+		}
+	}
+}
+
+func (g *grid) toPathMap() *types.PathMap {
+	chunkWidth := g.width / chunkSize
+	chunkHeight := g.height / chunkSize
+
+	pathMap := &types.PathMap{
+		WorldOffsetX: g.minX,
+		WorldOffsetY: g.minY,
+		CoordLimitX:  g.width,
+		CoordLimitY:  g.height,
+		ChunkWidth:   chunkWidth,
+		ChunkHeight:  chunkHeight,
+		Nodes:        make([][]types.PathNode, chunkWidth*chunkHeight),
+	}
+
+	for chunkX := 0; chunkX < chunkWidth; chunkX++ {
+		for chunkY := 0; chunkY < chunkHeight; chunkY++ {
+			nodes := make([]types.PathNode, chunkSize*chunkSize)
+			hasFloor := false
+
+			for y := 0; y < chunkSize; y++ {
+				for x := 0; x < chunkSize; x++ {
+					gridX := chunkX*chunkSize + x
+					gridY := chunkY*chunkSize + y
+					c := g.at(gridX, gridY)
+
+					hasFloor = hasFloor || c.hasFloor
+
+					nodes[x+y*chunkSize] = types.PathNode{
+						Solid:      c.hasFloor && !c.blocked,
+						Height:     c.floor,
+						WorldPosX:  g.minX + float32(gridX*cellSize),
+						WorldPosY:  g.minY + float32(gridY*cellSize),
+						GridCoordX: gridX,
+						GridCoordY: gridY,
+					}
+				}
+			}
+
+			// Chunks without any ground are left empty the same as the chunks the client skips
+			if hasFloor {
+				pathMap.Nodes[chunkY+chunkX*chunkHeight] = nodes
+			}
+		}
+	}
+
+	return pathMap
+}
+
+func vectorLength(v datatypes.Vector3Float32) float64 {
+	return math.Sqrt(float64(v.X*v.X + v.Y*v.Y + v.Z*v.Z))
+}
+
+// heightAt returns the height of the triangle at x, y if the point is inside the triangle
+func (t triangle) heightAt(x, y float32) (float32, bool) {
+	x0, y0 := float64(t[0].X), float64(t[0].Y)
+	x1, y1 := float64(t[1].X), float64(t[1].Y)
+	x2, y2 := float64(t[2].X), float64(t[2].Y)
+
+	denominator := (y1-y2)*(x0-x2) + (x2-x1)*(y0-y2)
+
+	if math.Abs(denominator) < 1e-6 {
+		return 0, false
+	}
+
+	a := ((y1-y2)*(float64(x)-x2) + (x2-x1)*(float64(y)-y2)) / denominator
+	b := ((y2-y0)*(float64(x)-x2) + (x0-x2)*(float64(y)-y2)) / denominator
+	c := 1 - a - b
+
+	if a < 0 || b < 0 || c < 0 {
+		return 0, false
+	}
+
+	return float32(a*float64(t[0].Z) + b*float64(t[1].Z) + c*float64(t[2].Z)), true
+}
@@ -0,0 +1,147 @@
+package pathmapbuilder
+
+import (
+	"RainbowRunner/internal/types"
+	"RainbowRunner/internal/types/drconfigtypes"
+	"RainbowRunner/pkg/datatypes"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+var ErrNoTiles = errors.New("none of the world's tilesets are in the tiles config")
+
+// TilePlacement is a tile pathmap placed in a generated zone, Offset is added to every node of the tile
+type TilePlacement struct {
+	PathMap *types.PathMap
+	Offset  datatypes.Vector2Float32
+}
+
+// TileSets returns the tile name prefixes the rooms of a generated world are picked from, e.g. crypt_corner_
+func TileSets(world *drconfigtypes.DRClass) []string {
+	tileSets := make([]string, 0)
+	seen := make(map[string]bool)
+
+	add := func(tileSet string) {
+		tileSet = strings.ToLower(strings.Trim(tileSet, "\"'"))
+
+		if tileSet != "" && !seen[tileSet] {
+			seen[tileSet] = true
+			tileSets = append(tileSets, tileSet)
+		}
+	}
+
+	add(world.Properties["TileSet"])
+
+	for _, group := range world.Children {
+		for _, entity := range group.Entities {
+			add(entity.Properties["TileSet"])
+		}
+	}
+
+	sort.Strings(tileSets)
+
+	return tileSets
+}
+
+// BuildTiles builds a pathmap for every tile a generated world can be made from. Each tile keeps its own coordinates
+// so the pathmaps can be joined with Combine once the maze layout is known.
+func (b *Builder) BuildTiles(world *drconfigtypes.DRClass, tiles *drconfigtypes.DRConfig) ([]*Result, error) {
+	tileSets := TileSets(world)
+	names := make([]string, 0)
+
+	if tiles.Classes != nil {
+		for name := range tiles.Classes.Children {
+			for _, tileSet := range tileSets {
+				if strings.HasPrefix(name, tileSet) {
+					names = append(names, name)
+					break
+				}
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, ErrNoTiles
+	}
+
+	sort.Strings(names)
+
+	results := make([]*Result, 0, len(names))
+
+	for _, name := range names {
+		group := tiles.Classes.Children[name]
+
+		if len(group.Entities) == 0 {
+			results = append(results, &Result{Zone: name, Err: ErrNoTerrain})
+			continue
+		}
+
+		results = append(results, b.Build(name, group.Entities[0]))
+	}
+
+	return results, nil
+}
+
+// Combine joins tile pathmaps into a single pathmap, where two tiles overlap a walkable cell is kept over a blocked one.
+// The server doesn't lay out generated zones itself so the offsets have to come from the layout the client generates.
+func Combine(tiles []TilePlacement) *types.PathMap {
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX, maxY := float32(-math.MaxFloat32), float32(-math.MaxFloat32)
+	hasTile := false
+
+	for _, tile := range tiles {
+		if tile.PathMap == nil {
+			continue
+		}
+
+		hasTile = true
+
+		x := tile.PathMap.WorldOffsetX + tile.Offset.X
+		y := tile.PathMap.WorldOffsetY + tile.Offset.Y
+
+		minX = float32(math.Min(float64(minX), float64(x)))
+		minY = float32(math.Min(float64(minY), float64(y)))
+		maxX = float32(math.Max(float64(maxX), float64(x+float32(tile.PathMap.CoordLimitX*cellSize))))
+		maxY = float32(math.Max(float64(maxY), float64(y+float32(tile.PathMap.CoordLimitY*cellSize))))
+	}
+
+	if !hasTile {
+		return nil
+	}
+
+	g := &grid{
+		minX: float32(math.Floor(float64(minX)/cellSize) * cellSize),
+		minY: float32(math.Floor(float64(minY)/cellSize) * cellSize),
+	}
+
+	g.width = int(math.Ceil(float64(maxX-g.minX)/(cellSize*chunkSize))) * chunkSize
+	g.height = int(math.Ceil(float64(maxY-g.minY)/(cellSize*chunkSize))) * chunkSize
+	g.cells = make([]cell, g.width*g.height)
+
+	for _, tile := range tiles {
+		if tile.PathMap == nil {
+			continue
+		}
+
+		// Empty chunks are nil and are left empty
+		for _, chunk := range tile.PathMap.Nodes {
+			for _, node := range chunk {
+				x := int(math.Floor(float64(node.WorldPosX+tile.Offset.X-g.minX) / cellSize))
+				y := int(math.Floor(float64(node.WorldPosY+tile.Offset.Y-g.minY) / cellSize))
+				c := g.at(x, y)
+
+				if c == nil || (c.hasFloor && !c.blocked && !node.Solid) {
+					continue
+				}
+
+				c.hasFloor = true
+				c.floor = node.Height
+				c.blocked = !node.Solid
+			}
+		}
+	}
+
+	return g.toPathMap()
+}
@@ -0,0 +1,123 @@
+package accounts
+
+import (
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCredentials = errors.New("invalid username or password")
+var ErrAccountBanned = errors.New("account is banned")
+
+// The client only has room for a 14 byte name and 16 byte password in the login packet
+const MaxNameLength = 14
+const MaxPasswordLength = 16
+
+// hashCost is the bcrypt cost new passwords are hashed with
+var hashCost = bcrypt.DefaultCost
+
+// Authenticate checks the password against the stored hash, when auto registration is enabled unknown accounts are
+// created with the given password
+func Authenticate(name string, password string) (*storage.Account, error) {
+	if name == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	account, err := storage.Store.GetAccount(name)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		if !serverconfig.Config.Accounts.AutoRegister {
+			return nil, ErrInvalidCredentials
+		}
+
+		log.Infof("auto registering account %s", name)
+
+		account, err = Register(name, password)
+
+		// Another login registered the name first so the password is checked against that account instead
+		if !errors.Is(err, storage.ErrAlreadyExists) {
+			return account, err
+		}
+
+		account, err = storage.Store.GetAccount(name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if account.Banned {
+		return nil, ErrAccountBanned
+	}
+
+	account.LastLogin = time.Now()
+
+	if err = storage.Store.SaveAccount(account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Register returns storage.ErrAlreadyExists if the name is taken, names are not case-sensitive
+func Register(name string, password string) (*storage.Account, error) {
+	if err := validateCredentials(name, password); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), hashCost)
+
+	if err != nil {
+		return nil, err
+	}
+
+	account := &storage.Account{
+		Name:         name,
+		PasswordHash: string(hash),
+		CreatedAt:    time.Now(),
+		LastLogin:    time.Now(),
+	}
+
+	if err = storage.Store.CreateAccount(account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func SetBanned(name string, banned bool) error {
+	account, err := storage.Store.GetAccount(name)
+
+	if err != nil {
+		return err
+	}
+
+	account.Banned = banned
+
+	return storage.Store.SaveAccount(account)
+}
+
+func validateCredentials(name string, password string) error {
+	if name == "" || len(name) > MaxNameLength {
+		return errors.New(fmt.Sprintf("account name must be between 1 and %d characters", MaxNameLength))
+	}
+
+	if strings.TrimSpace(name) != name {
+		return errors.New("account name cannot start or end with whitespace")
+	}
+
+	if password == "" || len(password) > MaxPasswordLength {
+		return errors.New(fmt.Sprintf("password must be between 1 and %d characters", MaxPasswordLength))
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package accounts
+
+import (
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"sync"
+	"testing"
+)
+
+// useMemoryStore gives the test an empty store and sets if unknown accounts are registered on login, passwords are
+// hashed with the lowest cost to keep the tests fast
+func useMemoryStore(t *testing.T, autoRegister bool) *storage.MemoryStore {
+	store := storage.NewMemoryStore()
+
+	oldStore, oldAutoRegister, oldCost := storage.Store, serverconfig.Config.Accounts.AutoRegister, hashCost
+	storage.Store, serverconfig.Config.Accounts.AutoRegister, hashCost = store, autoRegister, bcrypt.MinCost
+
+	t.Cleanup(func() {
+		storage.Store, serverconfig.Config.Accounts.AutoRegister, hashCost = oldStore, oldAutoRegister, oldCost
+	})
+
+	return store
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		account  string
+		password string
+		invalid  bool
+		expected error
+	}{
+		{name: "new", account: "tester", password: "secret"},
+		{name: "taken", existing: "tester", account: "tester", password: "secret", expected: storage.ErrAlreadyExists},
+		{name: "taken other case", existing: "Tester", account: "TESTER", password: "secret", expected: storage.ErrAlreadyExists},
+		{name: "longest name", account: "abcdefghijklmn", password: "secret"},
+		{name: "name too long", account: "abcdefghijklmno", password: "secret", invalid: true},
+		{name: "no name", account: "", password: "secret", invalid: true},
+		{name: "whitespace", account: " tester", password: "secret", invalid: true},
+		{name: "no password", account: "tester", password: "", invalid: true},
+		{name: "password too long", account: "tester", password: "abcdefghijklmnopq", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStore(t, false)
+
+			if test.existing != "" {
+				if _, err := Register(test.existing, "other"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			account, err := Register(test.account, test.password)
+
+			// Invalid names and passwords don't have an error to compare against
+			if test.invalid {
+				if err == nil {
+					t.Error("expected the credentials to be rejected")
+				}
+
+				return
+			}
+
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("got %v, expected %v", err, test.expected)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if account.PasswordHash == test.password || account.CreatedAt.IsZero() {
+				t.Error("account was not set up")
+			}
+
+			if _, err = storage.Store.GetAccount(test.account); err != nil {
+				t.Errorf("account was not saved: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	useMemoryStore(t, false)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := Register("tester", "secret")
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	created := 0
+
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, storage.ErrAlreadyExists):
+			t.Errorf("unexpected error %s", err.Error())
+		}
+	}
+
+	if created != 1 {
+		t.Errorf("account was created %d times", created)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name         string
+		autoRegister bool
+		banned       bool
+		account      string
+		password     string
+		expected     error
+	}{
+		{name: "correct", account: "tester", password: "secret"},
+		{name: "name other case", account: "TESTER", password: "secret"},
+		{name: "wrong password", account: "tester", password: "wrong", expected: ErrInvalidCredentials},
+		{name: "password other case", account: "tester", password: "SECRET", expected: ErrInvalidCredentials},
+		{name: "no password", account: "tester", password: "", expected: ErrInvalidCredentials},
+		{name: "unknown", account: "other", password: "secret", expected: ErrInvalidCredentials},
+		{name: "auto registered", autoRegister: true, account: "other", password: "secret"},
+		{name: "auto register wrong password", autoRegister: true, account: "tester", password: "wrong", expected: ErrInvalidCredentials},
+		{name: "banned", banned: true, account: "tester", password: "secret", expected: ErrAccountBanned},
+		{name: "banned wrong password", banned: true, account: "tester", password: "wrong", expected: ErrInvalidCredentials},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useMemoryStore(t, test.autoRegister)
+
+			if _, err := Register("tester", "secret"); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := SetBanned("tester", test.banned); err != nil {
+				t.Fatal(err)
+			}
+
+			account, err := Authenticate(test.account, test.password)
+
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("got %v, expected %v", err, test.expected)
+			}
+
+			if err != nil {
+				return
+			}
+
+			stored, err := storage.Store.GetAccount(test.account)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !stored.LastLogin.Equal(account.LastLogin) {
+				t.Error("last login was not saved")
+			}
+		})
+	}
+}
+
+func TestAuthenticateAutoRegistersOnce(t *testing.T) {
+	useMemoryStore(t, true)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+
+	// Logins racing to register the same name all get the account the first one created
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := Authenticate("tester", "secret")
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("login failed: %s", err.Error())
+		}
+	}
+
+	if _, err := Authenticate("tester", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("got %v for a wrong password after registering", err)
+	}
+}
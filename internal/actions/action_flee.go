@@ -4,6 +4,8 @@ import "RainbowRunner/pkg/byter"
 
 //go:generate go run ../../scripts/generatelua -type=ActionFlee
 type ActionFlee struct {
+	// The unit being fled from
+	TargetID uint16
 }
 
 func (a ActionFlee) OpCode() BehaviourAction {
@@ -11,7 +13,7 @@ func (a ActionFlee) OpCode() BehaviourAction {
 }
 
 func (a ActionFlee) Init(body *byter.Byter) {
-	panic("implement me")
+	body.WriteUInt16(a.TargetID)
 }
 
 func NewActionFlee() *ActionFlee {
@@ -4,14 +4,16 @@ import "RainbowRunner/pkg/byter"
 
 //go:generate go run ../../scripts/generatelua -type=ActionSearchForAttack
 type ActionSearchForAttack struct {
+	TargetID uint16
 }
 
 func (a ActionSearchForAttack) OpCode() BehaviourAction {
 	return BehaviourActionSearchForAttack
 }
 
+// Init matches SearchForAttack::SearchForAttack(Unit *), the unit is sent as its entity ID
 func (a ActionSearchForAttack) Init(body *byter.Byter) {
-	panic("implement me")
+	body.WriteUInt16(a.TargetID)
 }
 
 func NewActionSearchForAttack() *ActionSearchForAttack {
@@ -4,6 +4,8 @@ import "RainbowRunner/pkg/byter"
 
 //go:generate go run ../../scripts/generatelua -type=ActionUseTarget
 type ActionUseTarget struct {
+	ActionID byte
+	TargetID uint16
 }
 
 func (a ActionUseTarget) OpCode() BehaviourAction {
@@ -11,7 +13,8 @@ func (a ActionUseTarget) OpCode() BehaviourAction {
 }
 
 func (a ActionUseTarget) Init(body *byter.Byter) {
-	panic("implement me")
+	body.WriteByte(a.ActionID)
+	body.WriteUInt16(a.TargetID)
 }
 
 func NewActionUseTarget() *ActionUseTarget {
@@ -4,14 +4,18 @@ import "RainbowRunner/pkg/byter"
 
 //go:generate go run ../../scripts/generatelua -type=ActionWander
 type ActionWander struct {
+	Radius uint16
+	Unk0   bool
 }
 
 func (a ActionWander) OpCode() BehaviourAction {
 	return BehaviourActionWander
 }
 
+// Init matches Wander::Wander(ushort, bool)
 func (a ActionWander) Init(body *byter.Byter) {
-	panic("implement me")
+	body.WriteUInt16(a.Radius)
+	body.WriteBool(a.Unk0)
 }
 
 func NewActionWander() *ActionWander {
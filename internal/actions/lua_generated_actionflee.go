@@ -29,6 +29,7 @@ func registerLuaActionFlee(state *lua2.LState) {
 
 func luaMethodsActionFlee() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
+		"targetID": lua.LuaGenericGetSetNumber[IActionFlee](func(v IActionFlee) *uint16 { return &v.GetActionFlee().TargetID }),
 
 		"opCode": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IActionFlee](l, 1)
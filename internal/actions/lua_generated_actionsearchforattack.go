@@ -29,6 +29,7 @@ func registerLuaActionSearchForAttack(state *lua2.LState) {
 
 func luaMethodsActionSearchForAttack() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
+		"targetID": lua.LuaGenericGetSetNumber[IActionSearchForAttack](func(v IActionSearchForAttack) *uint16 { return &v.GetActionSearchForAttack().TargetID }),
 
 		"opCode": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IActionSearchForAttack](l, 1)
@@ -29,6 +29,8 @@ func registerLuaActionUseTarget(state *lua2.LState) {
 
 func luaMethodsActionUseTarget() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
+		"actionID": lua.LuaGenericGetSetNumber[IActionUseTarget](func(v IActionUseTarget) *byte { return &v.GetActionUseTarget().ActionID }),
+		"targetID": lua.LuaGenericGetSetNumber[IActionUseTarget](func(v IActionUseTarget) *uint16 { return &v.GetActionUseTarget().TargetID }),
 
 		"opCode": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IActionUseTarget](l, 1)
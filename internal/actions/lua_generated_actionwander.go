@@ -29,6 +29,8 @@ func registerLuaActionWander(state *lua2.LState) {
 
 func luaMethodsActionWander() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
+		"radius": lua.LuaGenericGetSetNumber[IActionWander](func(v IActionWander) *uint16 { return &v.GetActionWander().Radius }),
+		"unk0":   lua.LuaGenericGetSetBool[IActionWander](func(v IActionWander) *bool { return &v.GetActionWander().Unk0 }),
 
 		"opCode": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IActionWander](l, 1)
@@ -3,6 +3,7 @@ package admin
 import (
 	"RainbowRunner/internal/connections"
 	"RainbowRunner/internal/game"
+	"RainbowRunner/internal/metrics"
 	byter "RainbowRunner/pkg/byter"
 	"errors"
 	"fmt"
@@ -99,6 +100,8 @@ func SendCommand(command string) {
 
 func StartAdminServer() {
 	http.HandleFunc("/command", HandleRequest)
+	http.HandleFunc("/ticks", metrics.HandleTicks)
+	http.HandleFunc("/metrics", metrics.HandleMetrics)
 
 	http.ListenAndServe(":8090", nil)
 }
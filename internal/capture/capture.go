@@ -0,0 +1,156 @@
+package capture
+
+import (
+	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/serverconfig"
+	"fmt"
+	"github.com/goccy/go-json"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// recorder is loaded once by every call, the connection goroutines record while the server starts and stops
+var recorder atomic.Pointer[Recorder]
+
+// Recorder writes frames to a capture file, one JSON encoded frame per line
+type Recorder struct {
+	file    *os.File
+	encoder *json.Encoder
+	lock    sync.Mutex
+	closed  bool
+}
+
+// Record writes the frame, frames recorded after Close are dropped
+func (r *Recorder) Record(frame *Frame) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	if err := r.encoder.Encode(frame); err != nil {
+		log.Errorf("failed to write capture frame: %s", err.Error())
+	}
+}
+
+func (r *Recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	return r.file.Close()
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// Init starts a new capture file for this server run when captures are enabled
+func Init() {
+	if !serverconfig.Config.Capture.Enabled {
+		return
+	}
+
+	path := filepath.Join(
+		serverconfig.Config.Capture.Path,
+		fmt.Sprintf("%s.rrcap", time.Now().Format("2006-01-02T15_04_05")),
+	)
+
+	r, err := NewRecorder(path)
+
+	if err != nil {
+		panic(err)
+	}
+
+	log.Infof("capturing all traffic to %s", path)
+
+	recorder.Store(r)
+}
+
+// Close finishes the capture file of this server run, login connections and game handlers that are still running
+// may already have the recorder and have their frames dropped
+func Close() {
+	r := recorder.Swap(nil)
+
+	if r == nil {
+		return
+	}
+
+	if err := r.Close(); err != nil {
+		log.Errorf("failed to close capture file: %s", err.Error())
+	}
+}
+
+func Enabled() bool {
+	return recorder.Load() != nil
+}
+
+// RecordGame records a decompressed game message, it does nothing when captures are disabled
+func RecordGame(connectionID int, direction Direction, packetType byte, dest byte, messageType byte, body []byte) {
+	r := recorder.Load()
+
+	if r == nil {
+		return
+	}
+
+	frame := &Frame{
+		Time:         time.Now(),
+		Server:       GameServer,
+		ConnectionID: connectionID,
+		Direction:    direction,
+		PacketType:   packetType,
+		Dest:         dest,
+		MessageType:  messageType,
+		Body:         body,
+	}
+
+	if isChannelMessage(packetType, dest, messageType, body) {
+		frame.IsChannelMessage = true
+		frame.Channel = messages.Channel(body[0])
+		frame.ChannelMessageType = body[1]
+	}
+
+	r.Record(frame)
+}
+
+// RecordLogin records a decrypted auth message, the body must not contain the login credentials
+func RecordLogin(connectionID int, direction Direction, messageType byte, body []byte) {
+	r := recorder.Load()
+
+	if r == nil {
+		return
+	}
+
+	r.Record(&Frame{
+		Time:         time.Now(),
+		Server:       LoginServer,
+		ConnectionID: connectionID,
+		Direction:    direction,
+		PacketType:   messageType,
+		MessageType:  messageType,
+		Body:         body,
+	})
+}
@@ -0,0 +1,86 @@
+package capture
+
+import (
+	"RainbowRunner/internal/game/messages"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter selects frames from a capture, empty strings and negative numbers match everything
+type Filter struct {
+	Server       Server
+	Direction    Direction
+	ConnectionID int
+	Channel      int
+	// MessageType is the channel message type for channel messages and the packet message type for everything else
+	MessageType int
+}
+
+func (f Filter) Match(frame *Frame) bool {
+	if f.Server != "" && f.Server != frame.Server {
+		return false
+	}
+
+	if f.Direction != "" && f.Direction != frame.Direction {
+		return false
+	}
+
+	if f.ConnectionID >= 0 && f.ConnectionID != frame.ConnectionID {
+		return false
+	}
+
+	if f.Channel >= 0 && (!frame.IsChannelMessage || f.Channel != int(frame.Channel)) {
+		return false
+	}
+
+	if f.MessageType >= 0 {
+		messageType := frame.MessageType
+
+		if frame.IsChannelMessage {
+			messageType = frame.ChannelMessageType
+		}
+
+		if f.MessageType != int(messageType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f Filter) Apply(frames []*Frame) []*Frame {
+	filtered := make([]*Frame, 0)
+
+	for _, frame := range frames {
+		if f.Match(frame) {
+			filtered = append(filtered, frame)
+		}
+	}
+
+	return filtered
+}
+
+func NewFilter() Filter {
+	return Filter{
+		ConnectionID: -1,
+		Channel:      -1,
+		MessageType:  -1,
+	}
+}
+
+// ParseChannel accepts a channel number or name, names are matched without case e.g. "zonechannel"
+func ParseChannel(value string) (int, error) {
+	if number, err := strconv.ParseInt(value, 0, 16); err == nil {
+		return int(number), nil
+	}
+
+	for i := 0; i < 0x100; i++ {
+		if strings.EqualFold(messages.Channel(i).String(), value) {
+			return i, nil
+		}
+	}
+
+	return 0, errors.New(fmt.Sprintf("unknown channel %s", value))
+}
@@ -0,0 +1,56 @@
+package capture
+
+import (
+	"RainbowRunner/internal/game/messages"
+	"time"
+)
+
+type Direction string
+
+const (
+	Inbound  Direction = "in"
+	Outbound Direction = "out"
+)
+
+type Server string
+
+const (
+	LoginServer Server = "login"
+	GameServer  Server = "game"
+)
+
+// Frame is a single message sent or received by a server, bodies are stored decrypted and decompressed
+type Frame struct {
+	Time         time.Time `json:"time"`
+	Server       Server    `json:"server"`
+	ConnectionID int       `json:"connection_id"`
+	Direction    Direction `json:"direction"`
+	// PacketType is the game packet type (0x0a, 0x0e, 0x06, 0x10) or the auth message type for login frames
+	PacketType  byte `json:"packet_type"`
+	Dest        byte `json:"dest,omitempty"`
+	MessageType byte `json:"message_type,omitempty"`
+
+	// Channel and ChannelMessageType are only set for game channel messages
+	IsChannelMessage   bool             `json:"is_channel_message,omitempty"`
+	Channel            messages.Channel `json:"channel,omitempty"`
+	ChannelMessageType byte             `json:"channel_message_type,omitempty"`
+
+	Body []byte `json:"body"`
+}
+
+// isChannelMessage checks if the game packet body starts with a channel and message type, 0x0e and 0x06 are only
+// used for channel messages and 0x0a carries them when sent to dest 0x01 with type 0x0f
+func isChannelMessage(packetType byte, dest byte, messageType byte, body []byte) bool {
+	if len(body) < 2 {
+		return false
+	}
+
+	switch packetType {
+	case 0x0e, 0x06:
+		return true
+	case 0x0a:
+		return dest == 0x01 && messageType == 0x0f
+	}
+
+	return false
+}
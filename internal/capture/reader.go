@@ -0,0 +1,42 @@
+package capture
+
+import (
+	"bufio"
+	"errors"
+	"github.com/goccy/go-json"
+	"io"
+	"os"
+)
+
+// ReadFile reads every frame in a capture file
+func ReadFile(path string) ([]*Frame, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return Read(file)
+}
+
+func Read(r io.Reader) ([]*Frame, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	frames := make([]*Frame, 0)
+
+	for {
+		frame := &Frame{}
+		err := decoder.Decode(frame)
+
+		if errors.Is(err, io.EOF) {
+			return frames, nil
+		}
+
+		if err != nil {
+			return frames, err
+		}
+
+		frames = append(frames, frame)
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/pkg/byter"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+var ErrTimeout = errors.New("timed out waiting for server")
+var ErrClosed = errors.New("client closed")
+
+const defaultTimeout = 10 * time.Second
+
+// Client is a headless client that speaks the same protocol as the game client, it is used to drive the server
+// from bots and integration tests without needing the real client
+type Client struct {
+	LoginAddress string
+	// GameAddress overrides the game server address sent in the server list
+	GameAddress string
+	Username    string
+	Password    string
+	ServerID    byte
+	Timeout     time.Duration
+
+	sessionID1 uint32
+	sessionID2 uint32
+	oneTimeKey uint32
+	servers    []Server
+
+	gameConn  net.Conn
+	writeLock sync.Mutex
+	incoming  chan *Message
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	unitBehaviorID uint16
+	moveSessionID  byte
+}
+
+type Server struct {
+	ID           byte
+	Address      string
+	CurrentUsers uint16
+	MaxUsers     uint16
+	Status       byte
+}
+
+// Message is a decompressed message received from the game server
+type Message struct {
+	Dest byte
+	Type byte
+	Body *byter.Byter
+}
+
+// IsChannelMessage checks if the message is for the channel and message type, channel messages start with the
+// channel followed by the message type
+func (m *Message) IsChannelMessage(channel messages.Channel, msgType byte) bool {
+	data := m.Body.Data()
+
+	return m.Dest == 0x01 && m.Type == 0x0f && len(data) >= 2 && data[0] == byte(channel) && data[1] == msgType
+}
+
+func (c *Client) Servers() []Server {
+	return c.servers
+}
+
+// Close disconnects from the game server, it is safe to call multiple times
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		if c.gameConn != nil {
+			_ = c.gameConn.Close()
+		}
+	})
+}
+
+// waitFor discards received messages until one matches or the timeout is reached
+func (c *Client) waitFor(match func(message *Message) bool) (*Message, error) {
+	timeout := time.After(c.Timeout)
+
+	for {
+		select {
+		case message := <-c.incoming:
+			if match(message) {
+				return message, nil
+			}
+		case <-c.closed:
+			return nil, ErrClosed
+		case <-timeout:
+			return nil, ErrTimeout
+		}
+	}
+}
+
+func NewClient(loginAddress string, username string, password string) *Client {
+	return &Client{
+		LoginAddress: loginAddress,
+		Username:     username,
+		Password:     password,
+		Timeout:      defaultTimeout,
+		incoming:     make(chan *Message, 256),
+		closed:       make(chan struct{}),
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"RainbowRunner/internal/connections"
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes/marshal"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"time"
+)
+
+// ConnectGame connects to the game server and authenticates with the one time key given by the login server
+func (c *Client) ConnectGame() error {
+	address, err := c.gameServerAddress()
+
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", address, c.Timeout)
+
+	if err != nil {
+		return err
+	}
+
+	c.gameConn = conn
+
+	go c.readGame()
+
+	body := byter.NewLEByter(make([]byte, 0, 6))
+	body.WriteByte(0x00) // Unk
+	body.WriteUInt32(c.oneTimeKey)
+	body.WriteByte(0x00)
+
+	connections.WriteCompressedA(c, 0x00, 0x00, body)
+
+	_, err = c.waitFor(func(message *Message) bool {
+		return message.Dest == 0x00 && message.Type == 0x03
+	})
+
+	return err
+}
+
+// Send implements connections.Connection so the server writers can be reused to send messages
+func (c *Client) Send(b *byter.Byter) error {
+	select {
+	case <-c.closed:
+		return ErrClosed
+	default:
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_ = c.gameConn.SetWriteDeadline(time.Now().Add(c.Timeout))
+
+	_, err := c.gameConn.Write(b.Data())
+
+	return err
+}
+
+// GetID is the client ID written in packet headers, the server does not read it from clients
+func (c *Client) GetID() int {
+	return 0
+}
+
+// SendChannelMessage sends a channel message body, the body must start with the channel and message type
+func (c *Client) SendChannelMessage(body *byter.Byter) error {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(body.Data())
+	w.Close()
+
+	response := byter.NewLEByter(make([]byte, 0, 20+b.Len()))
+	response.WriteByte(0x0e)              // Packet Type
+	response.WriteUInt24(uint(c.GetID())) // Client ID
+	response.WriteUInt32(uint32(b.Len()) + 12)
+	response.WriteUInt24(0x00) // Unk
+	response.WriteUInt16(0x00) // Unk
+	response.WriteUInt24(0x00) // Unk
+	response.WriteUInt32(uint32(len(body.Data())))
+	response.WriteBuffer(b)
+
+	return c.Send(response)
+}
+
+// SendMessage encodes a message described with dr tags and sends it as a channel message
+func (c *Client) SendMessage(message interface{}) error {
+	body := byter.NewLEByter(make([]byte, 0, 64))
+
+	if err := marshal.Encode(body, message); err != nil {
+		return err
+	}
+
+	return c.SendChannelMessage(body)
+}
+
+func (c *Client) gameServerAddress() (string, error) {
+	if c.GameAddress != "" {
+		return c.GameAddress, nil
+	}
+
+	for _, server := range c.servers {
+		if server.ID == c.ServerID {
+			return server.Address, nil
+		}
+	}
+
+	return "", errors.New(fmt.Sprintf("server %d is not in the server list", c.ServerID))
+}
+
+func (c *Client) readGame() {
+	defer c.Close()
+
+	buf := make([]byte, 1024*10)
+	framer := &connections.PacketFramer{}
+
+	for {
+		read, err := c.gameConn.Read(buf)
+
+		if err != nil {
+			return
+		}
+
+		framer.Write(buf[0:read])
+
+		for {
+			frame, err := framer.Next()
+
+			if err != nil {
+				log.Errorf("%s: invalid packet stream from game server: %s", c.Username, err.Error())
+				return
+			}
+
+			if frame == nil {
+				break
+			}
+
+			if err = c.handleFrame(frame); err != nil {
+				log.Errorf("%s: %s", c.Username, err.Error())
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) handleFrame(frame []byte) error {
+	// The only other packet sent is the 0x10 handshake response which is followed by a compressed A message
+	if frame[0] != 0x0a {
+		return nil
+	}
+
+	if len(frame) < 15 {
+		return errors.New("compressed A packet is too short")
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(frame[15:]))
+
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r)
+
+	if err != nil {
+		return err
+	}
+
+	message := &Message{
+		Dest: frame[8],
+		Type: frame[9],
+		Body: byter.NewLEByter(body),
+	}
+
+	// Nothing reads the messages once the bot is in the world, dropping them keeps the reader from blocking
+	select {
+	case c.incoming <- message:
+	default:
+	}
+
+	return nil
+}
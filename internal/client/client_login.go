@@ -0,0 +1,199 @@
+package client
+
+import (
+	"RainbowRunner/internal/accounts"
+	"RainbowRunner/internal/message"
+	"RainbowRunner/pkg"
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/crypt"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Login authenticates with the login server, requests the server list and asks to play on ServerID which gives
+// the one time key used to connect to the game server
+func (c *Client) Login() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(fmt.Sprintf("invalid response from login server: %v", r))
+		}
+	}()
+
+	if len(c.Username) > accounts.MaxNameLength || len(c.Password) > accounts.MaxPasswordLength {
+		return errors.New(fmt.Sprintf(
+			"username and password must be at most %d and %d characters",
+			accounts.MaxNameLength, accounts.MaxPasswordLength,
+		))
+	}
+
+	conn, err := net.DialTimeout("tcp", c.LoginAddress, c.Timeout)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(c.Timeout))
+
+	if err != nil {
+		return err
+	}
+
+	// The server starts by sending the unencrypted protocol version
+	if _, _, err = readAuthMessage(conn); err != nil {
+		return err
+	}
+
+	authConn := pkg.NewConnection(conn)
+
+	if err = c.sendLogin(authConn); err != nil {
+		return err
+	}
+
+	if err = c.requestServerList(authConn); err != nil {
+		return err
+	}
+
+	return c.requestPlay(authConn)
+}
+
+func (c *Client) sendLogin(conn *pkg.Connection) error {
+	// The username and password are sent in fixed size fields, the first 24 bytes are DES encrypted
+	credentials := make([]byte, accounts.MaxNameLength+accounts.MaxPasswordLength)
+	copy(credentials, c.Username)
+	copy(credentials[accounts.MaxNameLength:], c.Password)
+
+	request := []byte{byte(message.AuthClientLoginPacket)}
+	request = append(request, crypt.EncryptDES(credentials, 0x18)...)
+	request = append(request, credentials[0x18:]...)
+
+	if _, err := conn.WriteMessageBytes(request); err != nil {
+		return err
+	}
+
+	messageType, reader, err := readAuthMessage(conn.Conn)
+
+	if err != nil {
+		return err
+	}
+
+	switch messageType {
+	case message.AuthServerLoginOkPacket:
+		c.sessionID1 = reader.UInt32()
+		c.sessionID2 = reader.UInt32()
+	case message.AuthServerLoginFailPacket, message.AuthServerBlockedAccountPacket:
+		return errors.New(fmt.Sprintf("login failed with %s reason 0x%x", messageType.String(), reader.UInt32()))
+	default:
+		return errors.New(fmt.Sprintf("unexpected login response %s", messageType.String()))
+	}
+
+	return nil
+}
+
+func (c *Client) requestServerList(conn *pkg.Connection) error {
+	request := byter.NewLEByter(make([]byte, 0, 16))
+	request.WriteByte(byte(message.AuthClientServerListExtPacket))
+	request.WriteUInt32(c.sessionID1)
+	request.WriteUInt32(c.sessionID2)
+
+	if _, err := conn.WriteMessageByter(request); err != nil {
+		return err
+	}
+
+	messageType, reader, err := readAuthMessage(conn.Conn)
+
+	if err != nil {
+		return err
+	}
+
+	if messageType != message.AuthServerSendServerListExPacket {
+		return errors.New(fmt.Sprintf("unexpected server list response %s", messageType.String()))
+	}
+
+	count := int(reader.UInt8())
+	reader.UInt8() // Last Server ID
+
+	c.servers = make([]Server, 0, count)
+
+	for i := 0; i < count; i++ {
+		server := Server{}
+		server.ID = reader.UInt8()
+		ip := reader.Bytes(4)
+		port := reader.UInt32()
+		reader.UInt8() // Age limit
+		reader.UInt8() // PKFlag
+		server.CurrentUsers = reader.UInt16()
+		server.MaxUsers = reader.UInt16()
+		server.Status = reader.UInt8()
+
+		server.Address = fmt.Sprintf("%d.%d.%d.%d:%d", ip[0], ip[1], ip[2], ip[3], port)
+
+		c.servers = append(c.servers, server)
+	}
+
+	return nil
+}
+
+func (c *Client) requestPlay(conn *pkg.Connection) error {
+	request := byter.NewLEByter(make([]byte, 0, 16))
+	request.WriteByte(byte(message.AuthClientAboutToPlayPacket))
+	request.WriteUInt32(c.sessionID1)
+	request.WriteUInt32(c.sessionID2)
+	request.WriteByte(c.ServerID)
+
+	if _, err := conn.WriteMessageByter(request); err != nil {
+		return err
+	}
+
+	messageType, reader, err := readAuthMessage(conn.Conn)
+
+	if err != nil {
+		return err
+	}
+
+	switch messageType {
+	case message.AuthServerPlayOkPacket:
+		c.oneTimeKey = reader.UInt32()
+	case message.AuthServerPlayFailPacket:
+		return errors.New(fmt.Sprintf("play failed with reason 0x%x", reader.UInt32()))
+	default:
+		return errors.New(fmt.Sprintf("unexpected play response %s", messageType.String()))
+	}
+
+	return nil
+}
+
+// readAuthMessage reads a whole auth packet, everything except the initial protocol version packet is blowfish
+// encrypted with the checksum block on the end
+func readAuthMessage(conn net.Conn) (message.AuthServerMessage, *byter.Byter, error) {
+	header := make([]byte, 2)
+
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := int(binary.LittleEndian.Uint16(header)) - 2
+
+	if length < 1 {
+		return 0, nil, errors.New(fmt.Sprintf("invalid auth packet length %d", length))
+	}
+
+	payload := make([]byte, length)
+
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return 0, nil, err
+	}
+
+	if length%8 == 0 {
+		payload = crypt.DecryptBlowfish(payload, length)
+	}
+
+	reader := byter.NewLEByter(payload)
+
+	return message.AuthServerMessage(reader.UInt8()), reader, nil
+}
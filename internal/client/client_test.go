@@ -0,0 +1,155 @@
+package client
+
+import (
+	"RainbowRunner/internal/database"
+	"RainbowRunner/internal/game"
+	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/login"
+	"RainbowRunner/internal/lua"
+	"RainbowRunner/internal/names"
+	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes/marshal"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func freePort(t *testing.T) int {
+	listen, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listen.Close()
+
+	return listen.Addr().(*net.TCPAddr).Port
+}
+
+// startServers runs the login and game servers the same way main does with an empty in memory store, the config
+// files and scripts are loaded relative to the repository root
+func startServers(t *testing.T) string {
+	wd, err := os.Getwd()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+
+	network := &serverconfig.Config.Network
+	network.LoginServerPort = freePort(t)
+	network.GameServerPort = freePort(t)
+	network.GameServerIP = "127.0.0.1"
+	network.OutboundQueueSize = 1024
+	network.WriteTimeout = 10 * time.Second
+
+	serverconfig.Config.Accounts.AutoRegister = true
+	serverconfig.Config.DefaultZone = "town"
+	serverconfig.Config.Characters.StartingLevel = 1
+	serverconfig.Config.Characters.MaxPerAccount = 4
+
+	if err = lua.LoadScripts("./lua"); err != nil {
+		t.Fatal(err)
+	}
+
+	database.LoadEquipmentFixtures()
+	database.LoadConfigFiles()
+	storage.Store = storage.NewMemoryStore()
+	names.Init()
+	objects.Init()
+
+	go login.StartLoginServer()
+	go game.StartGameServer()
+
+	t.Cleanup(func() {
+		login.StopLoginServer()
+		game.StopAccepting()
+	})
+
+	loginAddress := fmt.Sprintf("127.0.0.1:%d", network.LoginServerPort)
+
+	// Wait for both servers to be listening
+	for _, address := range []string{loginAddress, fmt.Sprintf("127.0.0.1:%d", network.GameServerPort)} {
+		deadline := time.Now().Add(10 * time.Second)
+
+		for {
+			conn, err := net.Dial("tcp", address)
+
+			if err == nil {
+				conn.Close()
+				break
+			}
+
+			if time.Now().After(deadline) {
+				t.Fatalf("server at %s did not start: %s", address, err.Error())
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	return loginAddress
+}
+
+func TestLoginEnterWorldAndChat(t *testing.T) {
+	c := NewClient(startServers(t), "tester", "secret")
+	defer c.Close()
+
+	if err := c.Login(); err != nil {
+		t.Fatalf("login failed: %s", err.Error())
+	}
+
+	if len(c.Servers()) == 0 {
+		t.Fatal("the login server sent no servers")
+	}
+
+	if err := c.ConnectGame(); err != nil {
+		t.Fatalf("could not connect to the game server: %s", err.Error())
+	}
+
+	// The character list is empty so the character is created before entering the zone
+	if err := c.EnterWorld("Tester"); err != nil {
+		t.Fatalf("could not enter the world: %s", err.Error())
+	}
+
+	characters, err := storage.Store.GetCharacters("tester")
+
+	if err != nil || len(characters) != 1 || characters[0].Name != "Tester" {
+		t.Fatalf("account has characters %v, %v", characters, err)
+	}
+
+	if err = c.Chat("hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.waitFor(func(message *Message) bool {
+		if !message.IsChannelMessage(messages.ChatChannel, 0x00) {
+			return false
+		}
+
+		chat := messages.ChatMessage{}
+
+		if err := marshal.Decode(byter.NewLEByter(message.Body.Data()), &chat); err != nil {
+			t.Errorf("could not decode chat message: %s", err.Error())
+			return false
+		}
+
+		return chat.Sender == "Tester" && chat.Message == "hello"
+	})
+
+	if err != nil {
+		t.Errorf("chat message was not sent back to the zone: %s", err.Error())
+	}
+}
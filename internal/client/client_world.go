@@ -0,0 +1,192 @@
+package client
+
+import (
+	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/pkg/byter"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// These match the channel message types handled by the game server
+const (
+	characterConnected byte = 0x00
+	characterCreate    byte = 0x02
+	characterGetList   byte = 0x03
+	characterPlay      byte = 0x05
+	groupConnected     byte = 0x00
+	zoneJoin           byte = 0x06
+	unitBehaviorMove   byte = 0x65
+)
+
+// Move update types, the client sends forward moves while walking and a stop move when it arrives
+const (
+	MoveUpdateStop    byte = 0x01
+	MoveUpdateForward byte = 0x02
+)
+
+const unitBehaviorGCType = "avatar.base.unitbehavior"
+
+// EnterWorld selects the first character on the account, creating it with the given name if the account has no
+// characters, then joins the zone the server sends the character to
+func (c *Client) EnterWorld(characterName string) error {
+	if err := c.sendCharacterMessage(characterConnected); err != nil {
+		return err
+	}
+
+	if _, err := c.waitForChannelMessage(messages.CharacterChannel, characterConnected); err != nil {
+		return err
+	}
+
+	if err := c.sendCharacterMessage(characterGetList); err != nil {
+		return err
+	}
+
+	list, err := c.waitForChannelMessage(messages.CharacterChannel, characterGetList)
+
+	if err != nil {
+		return err
+	}
+
+	// 04 03 [Count] then the characters
+	if len(list.Body.Data()) < 3 || list.Body.Data()[2] == 0 {
+		if err = c.createCharacter(characterName); err != nil {
+			return err
+		}
+	}
+
+	if err = c.SendMessage(messages.CharacterPlayRequest{Slot: 0}); err != nil {
+		return err
+	}
+
+	if _, err = c.waitForChannelMessage(messages.CharacterChannel, characterPlay); err != nil {
+		return err
+	}
+
+	body := byter.NewLEByter(make([]byte, 0, 2))
+	body.WriteByte(byte(messages.GroupChannel))
+	body.WriteByte(groupConnected)
+
+	if err = c.SendChannelMessage(body); err != nil {
+		return err
+	}
+
+	if _, err = c.waitForChannelMessage(messages.ZoneChannel, byte(messages.ZoneMessageConnected)); err != nil {
+		return err
+	}
+
+	body = byter.NewLEByter(make([]byte, 0, 2))
+	body.WriteByte(byte(messages.ZoneChannel))
+	body.WriteByte(zoneJoin)
+
+	if err = c.SendChannelMessage(body); err != nil {
+		return err
+	}
+
+	// The first entity stream after joining creates our own avatar, its UnitBehavior is needed to send moves
+	_, err = c.waitFor(func(message *Message) bool {
+		data := message.Body.Data()
+
+		if message.Dest != 0x01 || len(data) == 0 || data[0] != byte(messages.ClientEntityChannel) {
+			return false
+		}
+
+		c.unitBehaviorID = findCreatedComponent(data, unitBehaviorGCType)
+
+		return c.unitBehaviorID != 0
+	})
+
+	return err
+}
+
+// Move sends a single move update for the avatar, heading is in degrees
+func (c *Client) Move(x float32, y float32, heading float32, updateType byte) error {
+	if c.unitBehaviorID == 0 {
+		return errors.New("cannot move before entering the world")
+	}
+
+	c.moveSessionID++
+
+	body := byter.NewLEByter(make([]byte, 0, 24))
+	body.WriteByte(byte(messages.ClientEntityChannel))
+	body.WriteByte(byte(messages.ClientEntityComponentUpdate))
+	body.WriteUInt16(c.unitBehaviorID)
+	body.WriteByte(unitBehaviorMove)
+	body.WriteByte(c.moveSessionID)
+	body.WriteByte(0x01) // Move count
+	body.WriteByte(updateType)
+	body.WriteInt32(int32(heading * 256))
+	body.WriteInt32(int32(x * 256))
+	body.WriteInt32(int32(y * 256))
+
+	return c.SendChannelMessage(body)
+}
+
+// Chat sends a message to everyone in the zone
+func (c *Client) Chat(msg string) error {
+	body := byter.NewLEByter(make([]byte, 0, len(msg)+3))
+	body.WriteByte(byte(messages.ChatChannel))
+	body.WriteByte(byte(messages.ClientMessageChannelSourceZone))
+	body.WriteCString(msg)
+
+	return c.SendChannelMessage(body)
+}
+
+func (c *Client) createCharacter(name string) error {
+	err := c.SendMessage(messages.CharacterCreateRequest{
+		Name:  name,
+		Class: "FighterFemale",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = c.waitForChannelMessage(messages.CharacterChannel, characterCreate)
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("failed to create character %s: %s", name, err.Error()))
+	}
+
+	return nil
+}
+
+func (c *Client) sendCharacterMessage(msgType byte) error {
+	body := byter.NewLEByter(make([]byte, 0, 2))
+	body.WriteByte(byte(messages.CharacterChannel))
+	body.WriteByte(msgType)
+
+	return c.SendChannelMessage(body)
+}
+
+func (c *Client) waitForChannelMessage(channel messages.Channel, msgType byte) (*Message, error) {
+	return c.waitFor(func(message *Message) bool {
+		return message.IsChannelMessage(channel, msgType)
+	})
+}
+
+// findCreatedComponent looks for a create component of the GCType in an entity stream, GCTypes are not case-sensitive
+// 32 [Parent ID 2] [Component ID 2] FF [GCType CString] 01
+func findCreatedComponent(data []byte, gcType string) uint16 {
+	needle := append([]byte{0xFF}, []byte(strings.ToLower(gcType)+"\x00")...)
+	// bytes.ToLower would replace invalid UTF-8 and move the indexes so only ASCII is lowered
+	lower := make([]byte, len(data))
+
+	for i, b := range data {
+		if b >= 'A' && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+
+		lower[i] = b
+	}
+
+	index := bytes.Index(lower, needle)
+
+	if index < 5 || data[index-5] != 0x32 {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint16(data[index-2:])
+}
@@ -0,0 +1,111 @@
+package combat
+
+import (
+	"RainbowRunner/internal/actions"
+	"RainbowRunner/internal/global"
+	"RainbowRunner/internal/message"
+	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/types"
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes/drfloat"
+	"RainbowRunner/pkg/events"
+	log "github.com/sirupsen/logrus"
+)
+
+// How long a successful stun stops the target from moving and attacking
+const stunTicks = 1000 / global.TickInterval
+
+// Animation selection type for the impact animation, see actions.ActionPlayAnimation
+const impactAnimation = 0x07
+
+func Init() {
+	events.RegisterHandler[objects.AttackEvent](onAttack)
+}
+
+func onAttack(event objects.AttackEvent) {
+	attacker := event.UnitBehavior
+	attackerUnit, ok := attacker.GCParent.(objects.IUnit)
+
+	if !ok {
+		log.Errorf("attacker %s is not a unit", attacker.String())
+		return
+	}
+
+	target := event.Target.GetUnit()
+
+	if attackerUnit.GetUnit().IsDead() || target.IsDead() {
+		return
+	}
+
+	targetBehavior, ok := target.GetChildByGCNativeType("UnitBehavior").(objects.IUnitBehavior)
+
+	if !ok {
+		log.Errorf("attack target %s does not have a unit behavior", target.String())
+		return
+	}
+
+	sendAttack(attacker, target)
+
+	if monster, ok := targetBehavior.(objects.IMonsterBehavior2); ok {
+		monster.GetMonsterBehavior2().OnAttacked(attacker)
+	}
+
+	result := Resolve(statsFor(attackerUnit), statsFor(event.Target))
+
+	if result.Damage <= 0 {
+		return
+	}
+
+	applyDamage(target, result.Damage)
+
+	if target.IsDead() {
+		targetBehavior.GetUnitBehavior().ExecuteAction(actions.NewActionDie())
+		return
+	}
+
+	if result.Stunned {
+		targetBehavior.GetUnitBehavior().Stun(stunTicks)
+	}
+
+	targetBehavior.GetUnitBehavior().ExecuteAction(&actions.ActionPlayAnimation{
+		AnimationIDSelectionType: impactAnimation,
+	})
+}
+
+func applyDamage(target *objects.Unit, damage float64) {
+	hp := target.HP.Sub(drfloat.FromFloat32(float32(damage)))
+
+	if hp < 0 {
+		hp = 0
+	}
+
+	target.HP = hp
+}
+
+// sendAttack shows the attack to everyone that can see the attacker, the client of a player that attacked has already started the
+// attack from the use target response so it is only sent to the other players
+func sendAttack(attacker *objects.UnitBehavior, target *objects.Unit) {
+	attackAction := &actions.ActionAttackTarget2{
+		TargetID: uint16(target.RREntityProperties().ID),
+	}
+
+	if attacker.OwnerID() == 0 {
+		attacker.ExecuteAction(attackAction)
+		return
+	}
+
+	zone := attacker.RREntityProperties().Zone
+
+	if zone == nil {
+		return
+	}
+
+	zone.NotifyNearbyPlayers(attacker, types.Pointer(attacker.OwnerID()), message.OpTypeBehaviourAction, func() *byter.Byter {
+		CEWriter := objects.NewClientEntityWriterWithByter()
+		CEWriter.BeginComponentUpdate(attacker)
+		CEWriter.CreateActionComplete(attackAction)
+		CEWriter.WriteSynch(attacker)
+
+		return CEWriter.Body
+	})
+}
@@ -0,0 +1,115 @@
+package combat
+
+import (
+	"RainbowRunner/internal/global"
+	"math/rand"
+)
+
+const (
+	minHitChance       = 0.05
+	maxHitChance       = 0.95
+	criticalMultiplier = 2
+	// Weapon damage rolls between these fractions of the level based weapon damage
+	minWeaponDamage = 0.8
+	maxWeaponDamage = 1.2
+)
+
+// roll returns a number in [0, 1), the tests replace it to fix the outcome of an attack
+var roll = rand.Float64
+
+type Stats struct {
+	IsHero         bool
+	Level          int
+	AttackRating   float64
+	DefenseRating  float64
+	MinDamage      float64
+	MaxDamage      float64
+	CriticalChance float64 // Percent
+	StunResist     float64
+	MaxHP          float64
+}
+
+type Result struct {
+	Hit      bool
+	Critical bool
+	Stunned  bool
+	Damage   float64
+}
+
+// Resolve rolls a single attack, the attack rating is compared against the defense rating to decide if the attack
+// lands, a failed comparison only misses if the miss chance roll succeeds, otherwise the miss damage modifier is used
+func Resolve(attacker Stats, defender Stats) Result {
+	knobs := global.Knobs()
+	result := Result{}
+	damage := attacker.MinDamage + roll()*(attacker.MaxDamage-attacker.MinDamage)
+	damage *= float64(knobs.DPSModifier)
+
+	if roll() >= hitChance(attacker, defender) {
+		missChance, missDamageMod := float64(knobs.MonsterMissChance), float64(knobs.MonsterMissDamageMod)
+
+		if attacker.IsHero {
+			missChance, missDamageMod = float64(knobs.HeroMissChance), float64(knobs.HeroMissDamageMod)
+		}
+
+		if roll()*100 < missChance {
+			return result
+		}
+
+		result.Damage = damage * missDamageMod / 100
+
+		return result
+	}
+
+	result.Hit = true
+
+	if roll()*100 < attacker.CriticalChance {
+		result.Critical = true
+		damage *= criticalMultiplier
+	}
+
+	result.Damage = damage
+	result.Stunned = rollStun(attacker, defender, damage)
+
+	return result
+}
+
+func hitChance(attacker Stats, defender Stats) float64 {
+	if attacker.AttackRating+defender.DefenseRating <= 0 {
+		return maxHitChance
+	}
+
+	chance := attacker.AttackRating / (attacker.AttackRating + defender.DefenseRating)
+
+	if chance < minHitChance {
+		return minHitChance
+	}
+
+	if chance > maxHitChance {
+		return maxHitChance
+	}
+
+	return chance
+}
+
+// rollStun first rolls if a stun check is made at all, the stun check is more likely the larger the hit is compared
+// to the defenders health
+func rollStun(attacker Stats, defender Stats, damage float64) bool {
+	knobs := global.Knobs()
+	checkChance, stunMod := float64(knobs.MonsterStunChance), float64(knobs.MonsterStunMod)
+
+	if attacker.IsHero {
+		checkChance, stunMod = float64(knobs.HeroStunChance), float64(knobs.HeroStunMod)
+	}
+
+	if roll()*100 >= checkChance || defender.MaxHP <= 0 {
+		return false
+	}
+
+	resist := defender.StunResist
+
+	if resist < 1 {
+		resist = 1
+	}
+
+	return roll() < damage/defender.MaxHP*stunMod/100/resist
+}
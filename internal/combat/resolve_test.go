@@ -0,0 +1,145 @@
+package combat
+
+import (
+	"RainbowRunner/internal/global"
+	"testing"
+)
+
+// fixRolls makes roll return the given values in order, the test fails if the attack rolls more or less often
+func fixRolls(t *testing.T, rolls ...float64) {
+	next := 0
+	old := roll
+
+	roll = func() float64 {
+		if next >= len(rolls) {
+			t.Fatalf("rolled %d times, expected %d", next+1, len(rolls))
+		}
+
+		next++
+
+		return rolls[next-1]
+	}
+
+	t.Cleanup(func() {
+		roll = old
+
+		if next != len(rolls) {
+			t.Errorf("rolled %d times, expected %d", next, len(rolls))
+		}
+	})
+}
+
+func useKnobs(t *testing.T) {
+	old := global.Knobs()
+
+	global.SetKnobs(&global.GlobalKnobs{
+		DPSModifier:          1,
+		HeroMissChance:       50,
+		HeroMissDamageMod:    25,
+		MonsterMissChance:    100,
+		MonsterMissDamageMod: 0,
+		HeroStunChance:       100,
+		HeroStunMod:          100,
+		MonsterStunChance:    0,
+		MonsterStunMod:       100,
+	})
+
+	t.Cleanup(func() {
+		global.SetKnobs(old)
+	})
+}
+
+func TestHitChance(t *testing.T) {
+	tests := []struct {
+		name          string
+		attackRating  float64
+		defenseRating float64
+		expected      float64
+	}{
+		{"no ratings", 0, 0, maxHitChance},
+		{"equal", 10, 10, 0.5},
+		{"attack higher", 30, 10, 0.75},
+		{"clamped low", 1, 100, minHitChance},
+		{"clamped high", 100, 1, maxHitChance},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chance := hitChance(Stats{AttackRating: test.attackRating}, Stats{DefenseRating: test.defenseRating})
+
+			if chance != test.expected {
+				t.Errorf("hit chance is %v, expected %v", chance, test.expected)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	hero := Stats{IsHero: true, AttackRating: 10, MinDamage: 10, MaxDamage: 20, CriticalChance: 10}
+	monster := Stats{AttackRating: 10, MinDamage: 10, MaxDamage: 20}
+	defender := Stats{DefenseRating: 10, MaxHP: 100, StunResist: 1}
+	resistant := Stats{DefenseRating: 10, MaxHP: 100, StunResist: 2}
+
+	// The hit chance is 0.5 for all of them, the rolls are damage, hit, then either miss or critical, stun check and stun
+	tests := []struct {
+		name     string
+		attacker Stats
+		defender Stats
+		rolls    []float64
+		expected Result
+	}{
+		{
+			name:     "hit",
+			attacker: hero, defender: defender,
+			rolls:    []float64{0.5, 0.4, 0.5, 0, 0.9},
+			expected: Result{Hit: true, Damage: 15},
+		},
+		{
+			name:     "critical stun",
+			attacker: hero, defender: defender,
+			rolls:    []float64{0, 0.1, 0.05, 0, 0.1},
+			expected: Result{Hit: true, Critical: true, Stunned: true, Damage: 20},
+		},
+		{
+			name:     "stun resisted",
+			attacker: hero, defender: resistant,
+			rolls:    []float64{0, 0.1, 0.5, 0, 0.1},
+			expected: Result{Hit: true, Damage: 10},
+		},
+		{
+			name:     "miss",
+			attacker: hero, defender: defender,
+			rolls:    []float64{0.5, 0.6, 0.2},
+			expected: Result{},
+		},
+		{
+			name:     "glancing blow",
+			attacker: hero, defender: defender,
+			rolls:    []float64{0.5, 0.6, 0.7},
+			expected: Result{Damage: 3.75},
+		},
+		{
+			name:     "monster always misses",
+			attacker: monster, defender: defender,
+			rolls:    []float64{0.5, 0.6, 0.99},
+			expected: Result{},
+		},
+		{
+			name:     "monster never stuns",
+			attacker: monster, defender: defender,
+			rolls:    []float64{0.5, 0, 0.99, 0},
+			expected: Result{Hit: true, Damage: 15},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useKnobs(t)
+			fixRolls(t, test.rolls...)
+
+			if result := Resolve(test.attacker, test.defender); result != test.expected {
+				t.Errorf("got %+v, expected %+v", result, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package combat
+
+import (
+	"RainbowRunner/internal/global"
+	"RainbowRunner/internal/objects"
+)
+
+// statsFor builds the combat stats for a unit, heroes use their attributes and monsters use their entity description
+// with level based defaults when the description leaves a value out
+func statsFor(unit objects.IUnit) Stats {
+	u := unit.GetUnit()
+	knobs := global.Knobs()
+
+	stats := Stats{
+		Level: int(u.Level),
+		MaxHP: float64(u.MaxHP.ToFloat32()),
+	}
+
+	switch entity := unit.(type) {
+	case objects.IHero:
+		hero := entity.GetHero()
+		// The level counts as base attribute points so new characters can still hit things
+		strength := float64(hero.Strength) + float64(stats.Level)
+		agility := float64(hero.Agility) + float64(stats.Level)
+		weaponDamage := float64(stats.Level)*float64(knobs.WeaponDamagePerLevel) + strength*float64(knobs.MeleeDamagePerStrength)
+
+		stats.IsHero = true
+		stats.AttackRating = agility * float64(knobs.AttackRatingPerAgility)
+		stats.DefenseRating = strength*float64(knobs.DefenseRatingPerStrength) + float64(stats.Level)*float64(knobs.ItemDefenseRatingPerLevel)
+		stats.MinDamage = weaponDamage * minWeaponDamage
+		stats.MaxDamage = weaponDamage * maxWeaponDamage
+		stats.CriticalChance = float64(knobs.HeroCriticalChance)
+		stats.StunResist = float64(knobs.HeroStunResist)
+	case objects.INPC:
+		npc := entity.GetNPC()
+
+		if npc.Level > 0 {
+			stats.Level = int(npc.Level)
+		}
+
+		weaponDamage := float64(stats.Level) * float64(knobs.WeaponDamagePerLevel) * (1 + float64(knobs.MonsterDamageMod)/100.0)
+
+		if npc.DamageMod > 0 {
+			weaponDamage *= float64(npc.DamageMod)
+		}
+
+		stats.AttackRating = float64(stats.Level) * float64(knobs.AttackRatingPerAgility)
+		stats.DefenseRating = float64(stats.Level) * float64(knobs.DefenseRatingPerStrength)
+		stats.MinDamage = weaponDamage * minWeaponDamage
+		stats.MaxDamage = weaponDamage * maxWeaponDamage
+		stats.CriticalChance = float64(knobs.MonsterCriticalChance)
+		stats.StunResist = float64(knobs.MonsterStunResist) + float64(npc.StunResist)
+
+		if npc.AttackRating > 0 {
+			stats.AttackRating = float64(npc.AttackRating)
+		}
+
+		if npc.DefenseRating > 0 {
+			stats.DefenseRating = float64(npc.DefenseRating)
+		}
+
+		if npc.CriticalChance > 0 {
+			stats.CriticalChance = float64(npc.CriticalChance)
+		}
+	default:
+		weaponDamage := float64(stats.Level) * float64(knobs.WeaponDamagePerLevel)
+
+		stats.AttackRating = float64(stats.Level) * float64(knobs.AttackRatingPerAgility)
+		stats.DefenseRating = float64(stats.Level) * float64(knobs.DefenseRatingPerStrength)
+		stats.MinDamage = weaponDamage * minWeaponDamage
+		stats.MaxDamage = weaponDamage * maxWeaponDamage
+		stats.CriticalChance = float64(knobs.MonsterCriticalChance)
+		stats.StunResist = float64(knobs.MonsterStunResist)
+	}
+
+	return stats
+}
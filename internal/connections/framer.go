@@ -0,0 +1,94 @@
+package connections
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// MaxFrameSize is the largest packet accepted from a connection, anything bigger is treated as a corrupt stream
+const MaxFrameSize = 1024 * 1024
+
+// Every game packet starts with a type byte followed by a 3 byte client ID and a length field
+const frameHeaderSize = 8
+
+var ErrFrameTooLarge = errors.New("frame exceeds maximum size")
+
+// PacketFramer reassembles the game TCP stream into whole packets, reads can split packets at any byte
+// so data is buffered until the full length described by the packet header has arrived
+type PacketFramer struct {
+	buffer []byte
+}
+
+// Write appends data read from the connection
+func (f *PacketFramer) Write(data []byte) {
+	f.buffer = append(f.buffer, data...)
+}
+
+// Next returns the next complete packet or nil if more data is needed, an error means the stream is unrecoverable
+func (f *PacketFramer) Next() ([]byte, error) {
+	if len(f.buffer) == 0 {
+		return nil, nil
+	}
+
+	size, err := frameSize(f.buffer)
+
+	if err != nil || size == 0 {
+		return nil, err
+	}
+
+	if size > MaxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+
+	if len(f.buffer) < size {
+		return nil, nil
+	}
+
+	frame := make([]byte, size)
+	copy(frame, f.buffer)
+
+	remaining := copy(f.buffer, f.buffer[size:])
+	f.buffer = f.buffer[:remaining]
+
+	return frame, nil
+}
+
+// Buffered is the number of bytes received that are not part of a complete packet yet
+func (f *PacketFramer) Buffered() int {
+	return len(f.buffer)
+}
+
+// frameSize returns the total size of the packet at the start of data or 0 if the header is incomplete
+func frameSize(data []byte) (int, error) {
+	switch data[0] {
+	// 0a [ClientID 3] [Length 4] then Length bytes
+	// 0e [ClientID 3] [Length 4] then Length bytes
+	case 0x0a, 0x0e:
+		if len(data) < frameHeaderSize {
+			return 0, nil
+		}
+
+		return frameHeaderSize + int(binary.LittleEndian.Uint32(data[4:])), nil
+	// 06 [ClientID 3] [Size 3] then Size bytes
+	case 0x06:
+		if len(data) < 7 {
+			return 0, nil
+		}
+
+		size := int(data[4]) | int(data[5])<<8 | int(data[6])<<16
+
+		return 7 + size, nil
+	// 10 [ClientID 3] [Size 3] [Channel] then Size bytes, only sent by the server during the handshake
+	case 0x10:
+		if len(data) < frameHeaderSize {
+			return 0, nil
+		}
+
+		size := int(data[4]) | int(data[5])<<8 | int(data[6])<<16
+
+		return frameHeaderSize + size, nil
+	}
+
+	return 0, errors.New(fmt.Sprintf("unknown packet type %x", data[0]))
+}
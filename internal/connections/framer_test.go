@@ -0,0 +1,204 @@
+package connections
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// frame builds a packet of the given type from the client with a body of n bytes
+func frame(packetType byte, n int) []byte {
+	body := make([]byte, n)
+
+	for i := range body {
+		body[i] = byte(i)
+	}
+
+	header := []byte{packetType, 0x01, 0x02, 0x03}
+
+	switch packetType {
+	case 0x0a, 0x0e:
+		header = binary.LittleEndian.AppendUint32(header, uint32(n))
+	case 0x06:
+		header = append(header, byte(n), byte(n>>8), byte(n>>16))
+	case 0x10:
+		header = append(header, byte(n), byte(n>>8), byte(n>>16), 0x04)
+	}
+
+	return append(header, body...)
+}
+
+// feed writes the chunks one at a time and collects every packet that is complete after each write
+func feed(t *testing.T, chunks [][]byte) ([][]byte, error) {
+	t.Helper()
+
+	framer := &PacketFramer{}
+	frames := make([][]byte, 0)
+
+	for _, chunk := range chunks {
+		framer.Write(chunk)
+
+		for {
+			next, err := framer.Next()
+
+			if err != nil {
+				return frames, err
+			}
+
+			if next == nil {
+				break
+			}
+
+			frames = append(frames, next)
+		}
+	}
+
+	if framer.Buffered() != 0 {
+		t.Errorf("%d bytes left in the framer", framer.Buffered())
+	}
+
+	return frames, nil
+}
+
+func bytesOneAtATime(data []byte) [][]byte {
+	chunks := make([][]byte, len(data))
+
+	for i := range data {
+		chunks[i] = data[i : i+1]
+	}
+
+	return chunks
+}
+
+func splitAt(data []byte, at int) [][]byte {
+	return [][]byte{data[:at], data[at:]}
+}
+
+func TestPacketFramer(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected [][]byte
+		chunks   func(stream []byte) [][]byte
+	}{
+		{"0x0a whole", [][]byte{frame(0x0a, 20)}, nil},
+		{"0x0e whole", [][]byte{frame(0x0e, 300)}, nil},
+		{"0x06 whole", [][]byte{frame(0x06, 12)}, nil},
+		{"0x10 whole", [][]byte{frame(0x10, 5)}, nil},
+		{"0x0a empty body", [][]byte{frame(0x0a, 0)}, nil},
+
+		{"0x0a byte at a time", [][]byte{frame(0x0a, 20)}, bytesOneAtATime},
+		{"0x0e byte at a time", [][]byte{frame(0x0e, 300)}, bytesOneAtATime},
+		{"0x06 byte at a time", [][]byte{frame(0x06, 12)}, bytesOneAtATime},
+		{"0x10 byte at a time", [][]byte{frame(0x10, 5)}, bytesOneAtATime},
+
+		// Every header has the length starting at byte 4
+		{"0x0a split in length", [][]byte{frame(0x0a, 20)}, func(s []byte) [][]byte { return splitAt(s, 6) }},
+		{"0x0e split in length", [][]byte{frame(0x0e, 0x1234)}, func(s []byte) [][]byte { return splitAt(s, 5) }},
+		{"0x06 split in length", [][]byte{frame(0x06, 0x0201)}, func(s []byte) [][]byte { return splitAt(s, 5) }},
+		{"0x10 split in length", [][]byte{frame(0x10, 5)}, func(s []byte) [][]byte { return splitAt(s, 6) }},
+
+		{
+			"several in one read",
+			[][]byte{frame(0x0a, 3), frame(0x0e, 10), frame(0x06, 1), frame(0x10, 2), frame(0x0e, 0)},
+			nil,
+		},
+		{
+			"several byte at a time",
+			[][]byte{frame(0x0a, 3), frame(0x0e, 10), frame(0x06, 1), frame(0x10, 2)},
+			bytesOneAtATime,
+		},
+		{
+			"several split across packets",
+			[][]byte{frame(0x0e, 10), frame(0x0e, 10)},
+			func(s []byte) [][]byte { return [][]byte{s[:15], s[15:22], s[22:]} },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stream := bytes.Join(test.expected, nil)
+			chunks := [][]byte{stream}
+
+			if test.chunks != nil {
+				chunks = test.chunks(stream)
+			}
+
+			frames, err := feed(t, chunks)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if len(frames) != len(test.expected) {
+				t.Fatalf("got %d frames, expected %d", len(frames), len(test.expected))
+			}
+
+			for i := range frames {
+				if !bytes.Equal(frames[i], test.expected[i]) {
+					t.Errorf("frame %d is\n%x\nexpected\n%x", i, frames[i], test.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPacketFramerInvalid(t *testing.T) {
+	tooLarge := binary.LittleEndian.AppendUint32([]byte{0x0e, 0x01, 0x02, 0x03}, MaxFrameSize)
+	tooLarge06 := []byte{0x06, 0x01, 0x02, 0x03, 0xff, 0xff, 0xff}
+	hugeLength := binary.LittleEndian.AppendUint32([]byte{0x0a, 0x01, 0x02, 0x03}, 0xffffffff)
+
+	tests := []struct {
+		name   string
+		stream []byte
+		tooBig bool
+	}{
+		{"0x0e oversized", tooLarge, true},
+		{"0x06 oversized", tooLarge06, true},
+		{"0x0a length overflows", hugeLength, true},
+		{"unknown type", []byte{0x42, 0x00, 0x00, 0x00}, false},
+		{"unknown type after a valid frame", append(frame(0x0a, 2), 0xff), false},
+	}
+
+	for _, test := range tests {
+		for _, oneAtATime := range []bool{false, true} {
+			name := test.name
+
+			if oneAtATime {
+				name += " byte at a time"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				chunks := [][]byte{test.stream}
+
+				if oneAtATime {
+					chunks = bytesOneAtATime(test.stream)
+				}
+
+				framer := &PacketFramer{}
+				var err error
+
+				for _, chunk := range chunks {
+					framer.Write(chunk)
+
+					for err == nil {
+						var next []byte
+						next, err = framer.Next()
+
+						if next == nil {
+							break
+						}
+					}
+				}
+
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				if errors.Is(err, ErrFrameTooLarge) != test.tooBig {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+			})
+		}
+	}
+}
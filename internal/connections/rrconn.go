@@ -2,25 +2,164 @@ package connections
 
 import (
 	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/metrics"
+	"RainbowRunner/internal/serverconfig"
 	"RainbowRunner/pkg/byter"
+	"errors"
+	log "github.com/sirupsen/logrus"
 	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+var ErrConnectionClosed = errors.New("connection closed")
+var ErrOutboundQueueFull = errors.New("outbound queue full")
+
 type RRConn struct {
 	NetConn       net.Conn
 	Client        *RRConnClient
 	IsConnected   bool
 	MessageBuffer *byter.Byter
 	LoginName     string
+
+	outbound     chan []byte
+	closed       chan struct{}
+	closeOnce    sync.Once
+	writeTimeout time.Duration
+
+	maxQueueDepth int64
+	messagesSent  uint64
+	bytesSent     uint64
+}
+
+type OutboundStats struct {
+	QueueDepth    int
+	QueueCapacity int
+	MaxQueueDepth int
+	MessagesSent  uint64
+	BytesSent     uint64
 }
 
+// Send queues the data to be written by the connection writer, the data is copied so the caller can reuse the byter
 func (R *RRConn) Send(b *byter.Byter) error {
-	_, err := R.NetConn.Write(b.Data())
+	data := make([]byte, len(b.Data()))
+	copy(data, b.Data())
+
+	select {
+	case <-R.closed:
+		metrics.OutboundDropped.Inc()
+		return ErrConnectionClosed
+	default:
+	}
+
+	select {
+	case R.outbound <- data:
+		depth := int64(len(R.outbound))
+
+		for {
+			current := atomic.LoadInt64(&R.maxQueueDepth)
+
+			if depth <= current || atomic.CompareAndSwapInt64(&R.maxQueueDepth, current, depth) {
+				break
+			}
+		}
 
-	return err
+		return nil
+	default:
+		// A client that cannot keep up would otherwise hold an ever growing backlog, it is better to drop them
+		log.Errorf("outbound queue full for connection %d (%d messages), disconnecting", R.GetID(), cap(R.outbound))
+		metrics.OutboundDropped.Inc()
+		metrics.OutboundOverflowDisconnects.Inc()
+		R.Close()
+		return ErrOutboundQueueFull
+	}
+}
+
+// StartWriter writes queued messages to the network until the connection is closed
+func (R *RRConn) StartWriter() {
+	for {
+		select {
+		case <-R.closed:
+			return
+		case data := <-R.outbound:
+			if R.writeTimeout > 0 {
+				_ = R.NetConn.SetWriteDeadline(time.Now().Add(R.writeTimeout))
+			}
+
+			_, err := R.NetConn.Write(data)
+
+			if err != nil {
+				log.Errorf("failed to write to connection %d: %s", R.GetID(), err.Error())
+				R.Close()
+				return
+			}
+
+			atomic.AddUint64(&R.messagesSent, 1)
+			atomic.AddUint64(&R.bytesSent, uint64(len(data)))
+		}
+	}
+}
+
+// Drain waits for the writer to empty the outbound queue, returns false if it is still not empty after the timeout
+func (R *RRConn) Drain(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for len(R.outbound) > 0 {
+		select {
+		case <-R.closed:
+			return false
+		default:
+		}
+
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return true
+}
+
+// Close stops the writer and closes the network connection, it is safe to call multiple times
+func (R *RRConn) Close() {
+	R.closeOnce.Do(func() {
+		R.IsConnected = false
+		close(R.closed)
+
+		err := R.NetConn.Close()
+
+		if err != nil {
+			log.Errorf("failed to close connection %d: %s", R.GetID(), err.Error())
+		}
+	})
+}
+
+func (R *RRConn) IsClosed() bool {
+	select {
+	case <-R.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (R *RRConn) OutboundStats() OutboundStats {
+	return OutboundStats{
+		QueueDepth:    len(R.outbound),
+		QueueCapacity: cap(R.outbound),
+		MaxQueueDepth: int(atomic.LoadInt64(&R.maxQueueDepth)),
+		MessagesSent:  atomic.LoadUint64(&R.messagesSent),
+		BytesSent:     atomic.LoadUint64(&R.bytesSent),
+	}
 }
 
 func (R *RRConn) GetID() int {
+	if R.Client == nil {
+		return 0
+	}
+
 	return int(R.Client.ID)
 }
 
@@ -32,9 +171,18 @@ func (R *RRConn) SendMessage(message messages.DRMessage) {
 }
 
 func NewRRConn(conn net.Conn) *RRConn {
+	queueSize := serverconfig.Config.Network.OutboundQueueSize
+
+	if queueSize <= 0 {
+		queueSize = 1024
+	}
+
 	return &RRConn{
 		NetConn:       conn,
 		IsConnected:   true,
 		MessageBuffer: byter.NewLEByter(make([]byte, 1024*1000)),
+		outbound:      make(chan []byte, queueSize),
+		closed:        make(chan struct{}),
+		writeTimeout:  serverconfig.Config.Network.WriteTimeout,
 	}
 }
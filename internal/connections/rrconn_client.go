@@ -19,9 +19,7 @@ func (p *RRConnClient) GetID() int {
 }
 
 func (p *RRConnClient) Send(b *byter.Byter) error {
-	_, err := p.Conn.NetConn.Write(b.Data())
-
-	return err
+	return p.Conn.Send(b)
 }
 
 func NewRRConnClient(
@@ -1,6 +1,9 @@
 package connections
 
 import (
+	"RainbowRunner/internal/capture"
+	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/serverconfig"
 	"RainbowRunner/pkg/byter"
 	"bytes"
@@ -59,6 +62,10 @@ func WriteCompressedA(conn Connection, dest uint8, messageType uint8, body *byte
 		return
 	}
 
+	recordSent(dest, messageType, body.Data(), b.Len())
+
+	capture.RecordGame(conn.GetID(), capture.Outbound, 0x0a, dest, messageType, body.Data())
+
 	if serverconfig.Config.Logging.LogGenericSent {
 		pc, file, line, ok := runtime.Caller(1)
 		callerInfo := "unk"
@@ -71,3 +78,16 @@ func WriteCompressedA(conn Connection, dest uint8, messageType uint8, body *byte
 		logrus.Info(fmt.Sprintf("Sent: %s\n%s", callerInfo, hex.Dump(body.Data())))
 	}
 }
+
+func recordSent(dest uint8, messageType uint8, data []byte, compressedLength int) {
+	channel := "none"
+
+	// Only 0x01 0x0f messages are channel messages, the rest are part of the login handshake
+	if dest == 0x01 && messageType == 0x0f && len(data) > 0 {
+		channel = messages.Channel(data[0]).String()
+	}
+
+	metrics.BytesSent.Add(channel, uint64(len(data)))
+	metrics.UncompressedBytes.Add(uint64(len(data)))
+	metrics.CompressedBytes.Add(uint64(compressedLength))
+}
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"RainbowRunner/internal/types"
+	drconfigtypes2 "RainbowRunner/internal/types/drconfigtypes"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const avatarClassPrefix = "avatar.classes."
+
+// AvatarClass is a class that can be picked on the character creation screen, e.g. avatar.classes.FighterFemale
+type AvatarClass struct {
+	GCType string
+	Name   string
+	// Level and Skin are 0 when the class description doesn't set them, none of the extracted classes do
+	Level             int
+	Skin              byte
+	StartingEquipment []*StartingEquipment
+	StartingSkills    []*StartingSkill
+}
+
+type StartingEquipment struct {
+	GCType    string
+	ModGCType string
+	Slot      types.EquipmentSlot
+}
+
+type StartingSkill struct {
+	GCType string
+	Slot   int
+	Level  byte
+}
+
+var avatarClasses map[string]*AvatarClass
+
+// GetAvatarClass finds a playable class by its name or full GCType, names are not case-sensitive
+func GetAvatarClass(name string) *AvatarClass {
+	name = strings.ToLower(name)
+	name = strings.TrimPrefix(name, avatarClassPrefix)
+
+	return avatarClasses[name]
+}
+
+// LoadAvatarClasses reads every playable class from avatar.classes, a class is playable when the base it extends has
+// starting lists in its description
+func LoadAvatarClasses() map[string]*AvatarClass {
+	log.Info("loading avatar classes")
+
+	classes := make(map[string]*AvatarClass)
+
+	// Get merges the parents of everything under avatar.classes which takes far too long
+	groups, err := config.GetSimple("avatar.classes")
+
+	if err != nil || len(groups) == 0 || len(groups[0].Entities) == 0 {
+		log.Errorf("could not find avatar.classes in the extracted config")
+		return classes
+	}
+
+	children := groups[0].Entities[0].Children
+
+	for key, group := range children {
+		if len(group.Entities) == 0 {
+			continue
+		}
+
+		entity := group.Entities[0]
+		description := findAvatarClassDescription(children, entity.Extends)
+
+		if description == nil || description.Properties["StartingEquipmentList"] == "" {
+			continue
+		}
+
+		level, _ := strconv.Atoi(description.Properties["Level"])
+		skin, _ := strconv.Atoi(description.Properties["Skin"])
+
+		classes[key] = &AvatarClass{
+			GCType:            group.GCType,
+			Name:              strings.Trim(description.Properties["Name"], "\""),
+			Level:             level,
+			Skin:              byte(skin),
+			StartingEquipment: loadStartingEquipment(children, description.Properties["StartingEquipmentList"]),
+			StartingSkills:    loadStartingSkills(children, description.Properties["StartingSkillsList"]),
+		}
+	}
+
+	return classes
+}
+
+func findAvatarClassDescription(children map[string]*drconfigtypes2.DRClassChildGroup, gcType string) *drconfigtypes2.DRClass {
+	base := findAvatarClassChild(children, gcType)
+
+	if base == nil {
+		return nil
+	}
+
+	return base.Find([]string{"description"})
+}
+
+// findAvatarClassChild finds one of the siblings in avatar.classes, the starting lists are all referenced by full GCType
+func findAvatarClassChild(children map[string]*drconfigtypes2.DRClassChildGroup, gcType string) *drconfigtypes2.DRClass {
+	gcType = strings.ToLower(gcType)
+
+	if !strings.HasPrefix(gcType, avatarClassPrefix) {
+		return nil
+	}
+
+	group, ok := children[strings.TrimPrefix(gcType, avatarClassPrefix)]
+
+	if !ok || len(group.Entities) == 0 {
+		return nil
+	}
+
+	return group.Entities[0]
+}
+
+func loadStartingEquipment(children map[string]*drconfigtypes2.DRClassChildGroup, gcType string) []*StartingEquipment {
+	equipment := make([]*StartingEquipment, 0)
+	list := findAvatarClassChild(children, gcType)
+
+	if list == nil {
+		log.Errorf("could not find starting equipment list %s", gcType)
+		return equipment
+	}
+
+	for _, group := range list.Children {
+		if len(group.Entities) == 0 {
+			continue
+		}
+
+		entity := group.Entities[0]
+
+		slot, err := strconv.Atoi(entity.Properties["ID"])
+
+		if err != nil {
+			log.Errorf("could not parse the slot of %s in %s", entity.Extends, gcType)
+			continue
+		}
+
+		item := &StartingEquipment{
+			GCType: entity.Extends,
+			Slot:   types.EquipmentSlot(slot),
+		}
+
+		// The only child of an item is the mod it starts with
+		for _, mod := range entity.Children {
+			if len(mod.Entities) > 0 {
+				item.ModGCType = mod.Entities[0].Extends
+			}
+		}
+
+		equipment = append(equipment, item)
+	}
+
+	sort.Slice(equipment, func(i, j int) bool {
+		return equipment[i].Slot < equipment[j].Slot
+	})
+
+	return equipment
+}
+
+func loadStartingSkills(children map[string]*drconfigtypes2.DRClassChildGroup, gcType string) []*StartingSkill {
+	skills := make([]*StartingSkill, 0)
+	list := findAvatarClassChild(children, gcType)
+
+	if list == nil {
+		log.Errorf("could not find starting skills list %s", gcType)
+		return skills
+	}
+
+	for _, group := range list.Children {
+		if len(group.Entities) == 0 {
+			continue
+		}
+
+		entity := group.Entities[0]
+
+		// Professions are listed with the skills but do not go in a skill slot
+		slot, err := strconv.Atoi(entity.Properties["ID"])
+
+		if err != nil {
+			continue
+		}
+
+		skills = append(skills, &StartingSkill{
+			GCType: entity.Extends,
+			Slot:   slot,
+			Level:  byte(startingLevel(entity)),
+		})
+	}
+
+	sort.Slice(skills, func(i, j int) bool {
+		return skills[i].Slot < skills[j].Slot
+	})
+
+	return skills
+}
+
+func startingLevel(entity *drconfigtypes2.DRClass) int {
+	level, err := strconv.Atoi(entity.Properties["Level"])
+
+	if err != nil || level < 1 {
+		return 1
+	}
+
+	return level
+}
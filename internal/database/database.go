@@ -69,6 +69,9 @@ func LoadConfigFiles() {
 
 	worlds = LoadWorldConfigs()
 	zones = LoadZoneConfigs()
+	avatarClasses = LoadAvatarClasses()
+
+	LoadGlobalKnobs()
 
 	log.Info("config files loaded")
 }
@@ -150,7 +153,7 @@ func AddWeapons() {
 			continue
 		}
 
-		if strings.HasSuffix(desc.Properties["WeaponClass"], "MELEE") {
+		if IsMeleeWeapon(subType) {
 			if MeleeWeapons == nil {
 				MeleeWeapons = make(EquipmentMap)
 			}
@@ -166,6 +169,13 @@ func AddWeapons() {
 	}
 }
 
+// IsMeleeWeapon checks the weapon class in the description, anything else is a ranged weapon
+func IsMeleeWeapon(weapon *drconfigtypes2.DRClass) bool {
+	desc := weapon.Find([]string{"description"})
+
+	return desc != nil && strings.HasSuffix(desc.Properties["WeaponClass"], "MELEE")
+}
+
 var armourTypeMap = map[types.EquipmentSlot]*EquipmentMap{
 	types.EquipmentSlotHead:  &Helmets,
 	types.EquipmentSlotTorso: &Armours,
@@ -0,0 +1,60 @@
+package database
+
+import (
+	drconfigtypes2 "RainbowRunner/internal/types/drconfigtypes"
+	"strconv"
+	"strings"
+)
+
+// GetInventorySize reads the grid size from the description of an inventory class e.g. avatar.base.Inventory
+func GetInventorySize(gcType string) (width int, height int, ok bool) {
+	if config == nil {
+		return 0, 0, false
+	}
+
+	groups, err := config.GetSimple(strings.ToLower(gcType))
+
+	if err != nil || len(groups) == 0 || len(groups[0].Entities) == 0 {
+		return 0, 0, false
+	}
+
+	desc := groups[0].Entities[0].Find([]string{"description"})
+
+	if desc == nil {
+		return 0, 0, false
+	}
+
+	width, err = strconv.Atoi(desc.Properties["Width"])
+
+	if err != nil {
+		return 0, 0, false
+	}
+
+	height, err = strconv.Atoi(desc.Properties["Height"])
+
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return width, height, true
+}
+
+// GetItemSize is the number of inventory cells the item covers, items without a size in their description take one
+func GetItemSize(item *drconfigtypes2.DRClass) (width int, height int) {
+	width, height = 1, 1
+	desc := item.Find([]string{"description"})
+
+	if desc == nil {
+		return width, height
+	}
+
+	if w, err := strconv.Atoi(desc.Properties["InventoryWidth"]); err == nil && w > 0 {
+		width = w
+	}
+
+	if h, err := strconv.Atoi(desc.Properties["InventoryHeight"]); err == nil && h > 0 {
+		height = h
+	}
+
+	return width, height
+}
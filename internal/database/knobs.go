@@ -0,0 +1,74 @@
+package database
+
+import (
+	"RainbowRunner/internal/global"
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/types/configtypes"
+	drconfigtypes2 "RainbowRunner/internal/types/drconfigtypes"
+	"reflect"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LoadGlobalKnobs reads GlobalKnobs from the extracted config then applies the `knobs` overrides from config.yaml,
+// anything missing from both keeps the retail value
+func LoadGlobalKnobs() {
+	log.Info("loading global knobs")
+
+	knobs := global.NewGlobalKnobs()
+
+	classes, err := config.Get("globalknobs")
+
+	if err != nil || len(classes) == 0 || len(classes[0].Entities) == 0 {
+		log.Errorf("could not find globalknobs in the extracted config, using the default knobs")
+	} else {
+		configtypes.SetPropertiesOnStruct(knobs, classes[0].Entities[0].Properties)
+	}
+
+	overrides := knobOverrides(serverconfig.Config.Knobs)
+
+	if len(overrides) > 0 {
+		configtypes.SetPropertiesOnStruct(knobs, overrides)
+	}
+
+	global.SetKnobs(knobs)
+}
+
+// knobOverrides maps the config.yaml keys onto the GlobalKnobs field names, viper lowercases every key so they are
+// matched without case, values that would not parse are left out so the server can still start
+func knobOverrides(values map[string]string) drconfigtypes2.DRClassProperties {
+	overrides := make(drconfigtypes2.DRClassProperties)
+	knobsType := reflect.TypeOf(global.GlobalKnobs{})
+
+	for key, val := range values {
+		field, ok := knobsType.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, key)
+		})
+
+		if !ok {
+			log.Errorf("unknown knob %s in config", key)
+			continue
+		}
+
+		var err error
+
+		switch field.Type.Kind() {
+		case reflect.Int:
+			_, err = strconv.Atoi(val)
+		case reflect.Float32:
+			_, err = strconv.ParseFloat(val, 32)
+		}
+
+		if err != nil {
+			log.Errorf("invalid value %s for knob %s: %s", val, field.Name, err.Error())
+			continue
+		}
+
+		log.Infof("knob %s overridden to %s", field.Name, val)
+		overrides[field.Name] = val
+	}
+
+	return overrides
+}
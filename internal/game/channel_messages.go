@@ -3,6 +3,7 @@ package game
 import (
 	"RainbowRunner/internal/connections"
 	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/metrics"
 	byter "RainbowRunner/pkg/byter"
 	"encoding/hex"
 	"errors"
@@ -36,9 +37,27 @@ func handleUnk2ChannelMessages(conn *connections.RRConn, msgType byte, reader *b
 }
 
 func handleChannelMessage(conn *connections.RRConn, reader *byter.Byter) {
+	length := len(reader.Data()) - reader.I
+
+	// The channel and message type are read before the handler's panics are recovered
+	if length < 2 {
+		log.Errorf("channel message from connection %d is too short (%d bytes), disconnecting", conn.GetID(), length)
+		conn.Close()
+		return
+	}
+
 	msgChan := reader.UInt8()   // Channel
 	msgSubType := reader.Byte() // Message Type
 
+	metrics.BytesReceived.Add(messages.Channel(msgChan).String(), uint64(length))
+
+	defer recoverClient(
+		conn,
+		messages.Channel(msgChan).String(),
+		fmt.Sprintf("%s handler for message type 0x%x", messages.Channel(msgChan).String(), msgSubType),
+		reader.Data(),
+	)
+
 	handler, ok := channelMessageHandlers[messages.Channel(msgChan)]
 
 	if !ok {
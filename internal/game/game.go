@@ -1,17 +1,22 @@
 package game
 
 import (
+	"RainbowRunner/internal/capture"
+	"RainbowRunner/internal/combat"
 	"RainbowRunner/internal/connections"
 	"RainbowRunner/internal/game/chatcommander"
 	"RainbowRunner/internal/global"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/objects"
 	"RainbowRunner/internal/serverconfig"
 	"RainbowRunner/internal/synchronisation"
 	"RainbowRunner/pkg/byter"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"net"
+	"sync"
 	"time"
 )
 
@@ -20,6 +25,7 @@ var Connections = make(map[int]*connections.RRConn)
 
 func StartGameServer() {
 	synchronisation.Init()
+	combat.Init()
 	global.ServerStartTime = time.Now()
 
 	objects.Entities = objects.NewEntityManager()
@@ -30,12 +36,9 @@ func StartGameServer() {
 		panic(err)
 	}
 
-	defer func() {
-		err := listen.Close()
-		if err != nil {
-			panic(err)
-		}
-	}()
+	listenerLock.Lock()
+	listener = listen
+	listenerLock.Unlock()
 
 	go StartGameLoop()
 
@@ -43,18 +46,31 @@ func StartGameServer() {
 		conn, err := listen.Accept()
 
 		if err != nil {
-			panic(err)
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			log.Errorf("failed to accept game connection: %s", err.Error())
+			continue
 		}
 
+		connectionHandlers.Add(1)
 		go handleConnection(conn)
 	}
 }
 
+var listener net.Listener
+var listenerLock sync.Mutex
+
+// Tracks every handleConnection so shutdown can wait for disconnected players to be cleaned up
+var connectionHandlers sync.WaitGroup
+
 var connID int = 1
 
 func handleConnection(conn net.Conn) {
 	//parser := message.NewParser(conn)
 	buf := make([]byte, 1024*10)
+	framer := &connections.PacketFramer{}
 
 	fmt.Println("Client connected to gameserver")
 
@@ -71,14 +87,18 @@ func handleConnection(conn net.Conn) {
 
 	Connections[rrconn.Client.ID] = rrconn
 
-	defer func(conn net.Conn) {
-		rrconn.IsConnected = false
-		err := conn.Close()
+	go rrconn.StartWriter()
+
+	metrics.ConnectedClients.Inc()
+
+	defer func() {
+		metrics.ConnectedClients.Dec()
+		rrconn.Close()
 		objects.Players.OnDisconnect(rrconn.Client.ID)
-		if err != nil {
-			panic(err)
-		}
-	}(conn)
+		connectionHandlers.Done()
+	}()
+
+	defer recoverClient(rrconn, "connection", "connection", nil)
 
 	for {
 		read, err := conn.Read(buf)
@@ -90,14 +110,54 @@ func handleConnection(conn net.Conn) {
 
 		//log.Info(fmt.Sprintf("(GameServer)Received: \n%s\n", hex.Dump(buf[0:read])))
 
-		reader := byter.NewLEByter(buf[0:read])
+		framer.Write(buf[0:read])
 
-		for reader.HasRemainingData() {
-			readPacket(rrconn, reader)
+		if !readFrames(rrconn, framer) {
+			break
 		}
 	}
 }
 
+// readFrames dispatches every complete packet received so far, returns false if the connection should be closed
+func readFrames(conn *connections.RRConn, framer *connections.PacketFramer) bool {
+	for {
+		frame, err := framer.Next()
+
+		if err != nil {
+			log.Errorf("closing connection %d, invalid packet stream: %s", conn.GetID(), err.Error())
+			return false
+		}
+
+		if frame == nil {
+			return true
+		}
+
+		readFrame(conn, frame)
+
+		if conn.IsClosed() {
+			return false
+		}
+	}
+}
+
+func readFrame(conn *connections.RRConn, frame []byte) {
+	defer recoverClient(conn, "packet", "packet", frame)
+
+	readPacket(conn, byter.NewLEByter(frame))
+}
+
+// runForPlayer handles the message on the goroutine of the zone the player is in so handlers never race the zone tick
+func runForPlayer(conn *connections.RRConn, job func()) {
+	player := objects.Players.GetPlayerOrNil(uint16(conn.GetID()))
+
+	if player == nil {
+		job()
+		return
+	}
+
+	player.Run(job)
+}
+
 func readPacket(conn *connections.RRConn, reader *byter.Byter) {
 	msgType := reader.UInt8() // Message Type?
 
@@ -109,7 +169,7 @@ func readPacket(conn *connections.RRConn, reader *byter.Byter) {
 	if msgType == 0x0a {
 		reader.UInt24()                 // Unk
 		packetLength := reader.UInt32() // Packet Length
-		reader.UInt8()
+		dest := reader.UInt8()
 		msgTypeA := reader.UInt8()
 		reader.UInt8()
 
@@ -119,6 +179,8 @@ func readPacket(conn *connections.RRConn, reader *byter.Byter) {
 
 		reader = ReadCompressedA(reader, packetLength)
 
+		capture.RecordGame(conn.GetID(), capture.Inbound, msgType, dest, msgTypeA, reader.Data())
+
 		if msgTypeA != 0x00 && conn.LoginName == "" {
 			log.Errorf("Received invalid message before login")
 			return
@@ -158,11 +220,15 @@ func readPacket(conn *connections.RRConn, reader *byter.Byter) {
 	} else if msgType == 0x0e {
 		msgReader := ReadCompressedE(reader)
 
+		capture.RecordGame(conn.GetID(), capture.Inbound, msgType, 0x00, 0x00, msgReader.Data())
+
 		if serverconfig.Config.Logging.LogEMessages {
 			log.Infof("Received E:\n%s", hex.Dump(msgReader.Buffer))
 		}
 
-		handleChannelMessage(conn, msgReader)
+		runForPlayer(conn, func() {
+			handleChannelMessage(conn, msgReader)
+		})
 	} else if msgType == 0x06 {
 		reader.UInt24() // Unk
 		reader.UInt24() // Size
@@ -172,8 +238,12 @@ func readPacket(conn *connections.RRConn, reader *byter.Byter) {
 		reader.UInt8()  // Sub type?
 		reader.UInt24() // Unk
 
-		handleChannelMessage(conn, reader)
+		capture.RecordGame(conn.GetID(), capture.Inbound, msgType, 0x00, 0x00, reader.Data()[reader.I:])
+
+		runForPlayer(conn, func() {
+			handleChannelMessage(conn, reader)
+		})
 	} else {
-		fmt.Errorf("Unhandled message type %x\n", msgType)
+		log.Errorf("unhandled message type %x", msgType)
 	}
 }
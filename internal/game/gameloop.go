@@ -2,6 +2,7 @@ package game
 
 import (
 	"RainbowRunner/internal/global"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/objects"
 	"RainbowRunner/internal/synchronisation"
 	"time"
@@ -16,25 +17,31 @@ func StartGameLoop() {
 	for {
 		select {
 		case <-ticker.C:
+			timer := metrics.StartTick("game")
+
 			for !global.JobQueue.IsEmpty() {
 				job := global.JobQueue.Dequeue()
 				job()
 			}
 
-			objects.Players.RLock()
-			objects.Players.BeforeTick()
+			timer.Phase("jobs")
 
-			for _, player := range objects.Players.Players {
+			for _, player := range objects.Players.GetPlayers() {
 				player.Conn.Client.Tick()
 			}
 
-			objects.Players.AfterTick()
-			objects.Players.RUnlock()
+			timer.Phase("players")
+
+			objects.Players.ClearUnzonedMessages()
 
-			objects.Zones.Tick()
+			timer.Phase("after_tick")
+
+			// Zones tick on their own goroutines, see Zone.Start
 
 			synchronisation.Tick()
 
+			timer.Phase("synchronisation")
+
 			//if conn.Player.IsMoving {
 			//	conn.Player.SendPosition()
 			//}else
@@ -49,7 +56,8 @@ func StartGameLoop() {
 			//mov := conn.Player.LastMovementRequest
 			//SendMoveTo(conn, 0x05, mov.X, mov.Y)
 
-			global.Tick++
+			global.AdvanceTick()
+			timer.Finish()
 		}
 	}
 }
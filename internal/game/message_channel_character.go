@@ -2,10 +2,16 @@ package game
 
 import (
 	"RainbowRunner/internal/connections"
+	"RainbowRunner/internal/database"
 	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/names"
 	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
 	"RainbowRunner/internal/types/drobjecttypes"
 	byter "RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes/marshal"
+	"errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -20,6 +26,11 @@ const (
 	CharacterPlay
 )
 
+const (
+	characterCreateFailed    uint32 = 0x00
+	characterCreateSucceeded uint32 = 0x01
+)
+
 func handleCharacterChannelMessages(conn *connections.RRConn, msgType byte, reader *byter.Byter) error {
 	switch CharacterMessage(msgType) {
 	case CharacterConnected:
@@ -30,6 +41,8 @@ func handleCharacterChannelMessages(conn *connections.RRConn, msgType byte, read
 		handleCharacterList(conn)
 	case CharacterCreate:
 		handleCharacterCreate(conn, reader)
+	case CharacterDelete:
+		handleCharacterDelete(conn, reader)
 	default:
 		return UnhandledChannelMessageError
 	}
@@ -42,11 +55,16 @@ func handleCharacterList(conn *connections.RRConn) {
 	body.WriteByte(byte(messages.CharacterChannel)) // Character channel
 	body.WriteByte(byte(CharacterGetList))          // Get character list (GotCharacter)
 
-	count := len(objects.Players.Players[conn.GetID()].Characters)
+	rrPlayer := objects.Players.GetPlayer(uint16(conn.GetID()))
+
+	if rrPlayer == nil {
+		log.Errorf("no player for connection %d", conn.GetID())
+		return
+	}
 
-	body.WriteByte(byte(count))
+	body.WriteByte(byte(len(rrPlayer.Characters)))
 
-	for _, character := range objects.Players.Players[conn.GetID()].Characters {
+	for _, character := range rrPlayer.Characters {
 		body.WriteUInt32(character.EntityProperties.ID) // ID?
 		sendPlayer(character, conn.Client, body)
 	}
@@ -55,34 +73,106 @@ func handleCharacterList(conn *connections.RRConn) {
 }
 
 func handleCharacterCreate(conn *connections.RRConn, reader *byter.Byter) {
-	name := reader.String()
-	class := reader.String()
-	reader.UInt8() // Unk
-	reader.UInt8() // Face
-	reader.UInt8() // Hair
-	reader.UInt8() // Hair Colour
+	request := messages.CharacterCreateRequest{}
 
-	log.Infof("New character created %s (%s)", name, class)
+	if err := marshal.DecodeFields(reader, &request); err != nil {
+		log.Errorf("invalid character create request from %s: %s", conn.LoginName, err.Error())
+		return
+	}
+
+	name := request.Name
+	rrPlayer := objects.Players.GetPlayer(uint16(conn.GetID()))
+
+	if rrPlayer == nil {
+		log.Errorf("no player for connection %d", conn.GetID())
+		return
+	}
+
+	if len(rrPlayer.Characters) >= serverconfig.Config.Characters.MaxPerAccount {
+		log.Infof("%s tried to create character %s but already has %d characters", conn.LoginName, name, len(rrPlayer.Characters))
+		sendCharacterCreateFailed(conn)
+		return
+	}
+
+	class := database.GetAvatarClass(request.Class)
+
+	if class == nil {
+		log.Errorf("%s tried to create character %s with unknown class '%s'", conn.LoginName, name, request.Class)
+		sendCharacterCreateFailed(conn)
+		return
+	}
+
+	if err := names.Reserve(name); err != nil {
+		log.Infof("%s tried to create character '%s': %s", conn.LoginName, name, err.Error())
+		sendCharacterCreateFailed(conn)
+		return
+	}
+
+	character := objects.NewCharacter(conn.LoginName, name, class, storage.Appearance{
+		Face:       request.Face,
+		Hair:       request.Hair,
+		HairColour: request.HairColour,
+	})
+
+	err := storage.Store.CreateCharacter(character)
+
+	if err != nil {
+		log.Errorf("failed to create character %s for %s: %s", name, conn.LoginName, err.Error())
+		names.Release(name)
+		sendCharacterCreateFailed(conn)
+		return
+	}
+
+	log.Infof("New character created %s (%s)", name, class.GCType)
+
+	player := newPlayerFromCharacter(conn, character)
+	rrPlayer.Characters = append(rrPlayer.Characters, player)
 
 	body := byter.NewLEByter(make([]byte, 0, 1024))
 	body.WriteByte(byte(messages.CharacterChannel)) // Character channel
 	body.WriteByte(byte(CharacterCreate))
-	body.WriteUInt32(0x01)
+	body.WriteUInt32(characterCreateSucceeded)
 
 	body.WriteCString(conn.LoginName)
 
-	sendPlayer(loadPlayer(conn.LoginName), conn.Client, body)
+	sendPlayer(player, conn.Client, body)
+
+	connections.WriteCompressedA(conn, 0x01, 0x0f, body)
+}
+
+// sendCharacterCreateFailed tells the client the character was not created. 0x00 is the only failure code known so
+// every reason is sent the same, see CharacterCreated in resources/Docs/v2/Channels/CharacterChannel.md
+func sendCharacterCreateFailed(conn *connections.RRConn) {
+	body := byter.NewLEByter(make([]byte, 0, 6))
+	body.WriteByte(byte(messages.CharacterChannel))
+	body.WriteByte(byte(CharacterCreate))
+	body.WriteUInt32(characterCreateFailed)
 
 	connections.WriteCompressedA(conn, 0x01, 0x0f, body)
 }
 
 func handleCharacterPlay(conn *connections.RRConn, reader *byter.Byter) {
-	reader.UInt8()
-	reader.UInt8()
-	slot := reader.UInt8()
+	request := messages.CharacterPlayRequest{}
+
+	if err := marshal.DecodeFields(reader, &request); err != nil {
+		log.Errorf("invalid character play request from %s: %s", conn.LoginName, err.Error())
+		return
+	}
 
-	character := objects.Players.Players[conn.GetID()].Characters[slot]
-	objects.Players.Players[conn.GetID()].CurrentCharacter = character
+	rrPlayer := objects.Players.GetPlayer(uint16(conn.GetID()))
+
+	if rrPlayer == nil {
+		log.Errorf("no player for connection %d", conn.GetID())
+		return
+	}
+	character := rrPlayer.GetCharacter(int(request.Slot))
+
+	if character == nil {
+		log.Errorf("%s tried to play character slot %d but only has %d characters", conn.LoginName, request.Slot, len(rrPlayer.Characters))
+		return
+	}
+
+	rrPlayer.CurrentCharacter = character
 
 	character.WalkChildren(func(object drobjecttypes.DRObject) {
 		props := object.(objects.IRREntityPropertiesHaver).GetRREntityProperties()
@@ -97,17 +187,77 @@ func handleCharacterPlay(conn *connections.RRConn, reader *byter.Byter) {
 	connections.WriteCompressedA(conn, 0x01, 0x0f, body)
 }
 
+// handleCharacterDelete removes the character from storage, the client has already asked the player to type the name
+func handleCharacterDelete(conn *connections.RRConn, reader *byter.Byter) {
+	request := messages.CharacterDeleteRequest{}
+
+	if err := marshal.DecodeFields(reader, &request); err != nil {
+		log.Errorf("invalid character delete request from %s: %s", conn.LoginName, err.Error())
+		return
+	}
+
+	rrPlayer := objects.Players.GetPlayer(uint16(conn.GetID()))
+
+	if rrPlayer == nil {
+		log.Errorf("no player for connection %d", conn.GetID())
+		return
+	}
+	character := rrPlayer.GetCharacterByName(request.Name)
+
+	if character == nil {
+		log.Errorf("%s tried to delete character '%s' which is not on their account", conn.LoginName, request.Name)
+		// The list is sent again so the client doesn't keep showing a character that isn't there
+		handleCharacterList(conn)
+		return
+	}
+
+	if character == rrPlayer.CurrentCharacter {
+		log.Errorf("%s tried to delete character %s while playing it", conn.LoginName, character.Name)
+		handleCharacterList(conn)
+		return
+	}
+
+	if err := storage.Store.DeleteCharacter(character.CharacterID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Errorf("failed to delete character %s for %s: %s", character.Name, conn.LoginName, err.Error())
+		handleCharacterList(conn)
+		return
+	}
+
+	rrPlayer.RemoveCharacter(character)
+	names.Release(character.Name)
+
+	log.Infof("Character %s deleted by %s", character.Name, conn.LoginName)
+
+	// Unverified against the client, the ID is the per session one the character was given in the list, see
+	// DeleteCharacter in CharacterChannel.md
+	body := byter.NewLEByter(make([]byte, 0, 6))
+	body.WriteByte(byte(messages.CharacterChannel))
+	body.WriteByte(byte(CharacterDelete))
+	body.WriteUInt32(character.EntityProperties.ID)
+
+	connections.WriteCompressedA(conn, 0x01, 0x0f, body)
+}
+
 func handleCharacterConnected(conn *connections.RRConn) {
-	count := 2
-	objects.Players.Players[conn.GetID()].Characters = make([]*objects.Player, 0, count)
+	rrPlayer := objects.Players.GetPlayer(uint16(conn.GetID()))
+
+	if rrPlayer == nil {
+		log.Errorf("no player for connection %d", conn.GetID())
+		return
+	}
+
+	characters, err := storage.Store.GetCharacters(conn.LoginName)
+
+	if err != nil {
+		log.Errorf("failed to load characters for %s: %s", conn.LoginName, err.Error())
+		characters = nil
+	}
+
+	rrPlayer.Characters = make([]*objects.Player, 0, len(characters))
 
-	for i := 0; i < count; i++ {
-		player := loadPlayer(conn.LoginName)
-		player.EntityProperties.Conn = conn
-		player.EntityProperties.ID = uint32(objects.NewID())
-		//player.EntityProperties.ID = uint32(i + 1)
-		player.EntityProperties.OwnerID = uint16(conn.GetID())
-		objects.Players.Players[conn.GetID()].Characters = append(objects.Players.Players[conn.GetID()].Characters, player)
+	for _, character := range characters {
+		player := newPlayerFromCharacter(conn, character)
+		rrPlayer.Characters = append(rrPlayer.Characters, player)
 	}
 
 	body := byter.NewLEByter(make([]byte, 0, 1024))
@@ -167,8 +317,7 @@ func sendPlayer(character *objects.Player, client *connections.RRConnClient, bod
 	//player.AddChild(slot6)
 
 	//player := loadPlayer(conn.Client)
-	avatar := objects.LoadAvatar()
-	character.AddChild(avatar)
+	avatar := character.GetAvatar()
 
 	//avatar2 := loadAvatar(character)
 	//player.AddChild(avatar)
@@ -188,8 +337,11 @@ func sendPlayer(character *objects.Player, client *connections.RRConnClient, bod
 	body.WriteUInt32(0x01)
 }
 
-func loadPlayer(name string) *objects.Player {
-	player := objects.NewPlayer(name)
+func newPlayerFromCharacter(conn *connections.RRConn, character *storage.Character) *objects.Player {
+	player := objects.LoadPlayerFromCharacter(character)
+	player.EntityProperties.Conn = conn
+	player.EntityProperties.ID = uint32(objects.NewID())
+	player.EntityProperties.OwnerID = uint16(conn.GetID())
 	//objects.Entities.RegisterAll(client, player.Children()...)
 	return player
 }
@@ -5,6 +5,7 @@ import (
 	"RainbowRunner/internal/game/messages"
 	"RainbowRunner/internal/global"
 	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/pathfinding"
 	"RainbowRunner/internal/serverconfig"
 	"RainbowRunner/pkg/byter"
 	"fmt"
@@ -189,7 +190,7 @@ func SendInterval(conn *connections.RRConn) {
 	// ClientEntityManager::processInterval
 	// Current Server Tick
 	// Just a guess, I think this is probably meant to be separated by zone
-	body.WriteInt32(int32(global.Tick)) // Unk - Stored in ClientEntityManager::vftable + 0xa94
+	body.WriteInt32(int32(global.GetTick())) // Unk - Stored in ClientEntityManager::vftable + 0xa94
 
 	// Zones have their own tick intervals, I assume this is meant to be for the current zone
 	body.WriteInt32(global.TickInterval) // TickInterval - Stored in ClientEntityManager::vftable + 0xa80
@@ -205,9 +206,9 @@ func SendInterval(conn *connections.RRConn) {
 	body.WriteInt32(0) // Unk - Stored in ClientEntityManager::vftable + 0xa84 - Movement prediction buffer/ticks ahead of server?/max ticks behind server
 
 	// PathManager::readBudget
-	body.WriteInt32(0)    // Unk
-	body.WriteUInt16(100) // Budget Per Update
-	body.WriteUInt16(20)  // Budget Per Path
+	body.WriteInt32(0)                                    // Unk
+	body.WriteUInt16(pathfinding.DefaultBudget.PerUpdate) // Budget Per Update
+	body.WriteUInt16(pathfinding.DefaultBudget.PerPath)   // Budget Per Path
 
 	AddEntityUpdateStreamEnd(body)
 
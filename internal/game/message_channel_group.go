@@ -3,6 +3,7 @@ package game
 import (
 	"RainbowRunner/internal/connections"
 	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/objects"
 	"RainbowRunner/internal/serverconfig"
 	byter "RainbowRunner/pkg/byter"
 )
@@ -52,6 +53,14 @@ func handleGroupConnected(conn *connections.RRConn) {
 	//sendGoToZone(conn, "dungeon16_level00")//The Mutantmania Training Center (2)
 	//sendGoToZone(conn, "town") //townston
 
-	sendGoToZone(conn, serverconfig.Config.DefaultZone)
+	zoneName := serverconfig.Config.DefaultZone
+
+	if rrPlayer := objects.Players.GetPlayer(uint16(conn.GetID())); rrPlayer != nil && rrPlayer.CurrentCharacter != nil {
+		if savedZone := rrPlayer.CurrentCharacter.SavedZoneName(); savedZone != "" {
+			zoneName = savedZone
+		}
+	}
+
+	sendGoToZone(conn, zoneName)
 
 }
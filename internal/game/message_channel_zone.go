@@ -41,32 +41,24 @@ func handleZoneChannelMessages(conn *connections.RRConn, msgSubType uint8, reade
 func handleZoneJoin(conn *connections.RRConn) {
 	player := objects.Players.GetPlayer(uint16(conn.GetID()))
 
-	body := byter.NewLEByter(make([]byte, 0, 1024))
-	body.WriteByte(byte(messages.ZoneChannel))
-	body.WriteByte(byte(messages.ZoneMessageReady))
-	//body.WriteByte(0x02) // Other acceptable values
-	//body.WriteByte(0x05) // Other acceptable values
-	body.WriteUInt32(player.Zone().ID) // World ID
-
-	// MiniMapExplored::ReadExploredBits
 	// dungeon00_level01 - 0x12
 	exploredBitCount := uint16(0x12)
-	body.WriteUInt16(exploredBitCount)
+	exploredBits := make([]uint32, exploredBitCount)
 
-	for i := 0; i < int(exploredBitCount); i++ {
-		body.WriteUInt32(0xFFFFFFFF)
+	for i := range exploredBits {
+		exploredBits[i] = 0xFFFFFFFF
 	}
 
-	connections.WriteCompressedA(conn, 0x01, 0x0f, body)
-
-	body = byter.NewLEByter(make([]byte, 0, 1024))
-	body.WriteByte(byte(messages.ZoneChannel))
-	body.WriteByte(byte(messages.ZoneMessageInstanceCount))
+	conn.SendMessage(messages.ZoneReadyMessage{
+		WorldID:          player.Zone().ID,
+		ExploredBitCount: exploredBitCount,
+		ExploredBits:     exploredBits,
+	})
 
-	// Adds two separate values into the ZoneClient
-	body.WriteUInt32(0x01)
-	body.WriteUInt32(0x01)
-	connections.WriteCompressedA(conn, 0x01, 0x0f, body)
+	conn.SendMessage(messages.ZoneInstanceCountMessage{
+		Unk0: 0x01,
+		Unk1: 0x01,
+	})
 
 	SendInterval(conn)
 
@@ -106,5 +98,5 @@ func sendGoToZone(conn *connections.RRConn, zoneName string) {
 		return
 	}
 
-	rrPlayer.CurrentCharacter.JoinZone(tZone)
+	rrPlayer.CurrentCharacter.EnterZone(tZone, nil)
 }
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"RainbowRunner/internal/capture"
 	"RainbowRunner/internal/connections"
 	byter "RainbowRunner/pkg/byter"
 	"bytes"
@@ -116,11 +117,14 @@ func WriteMessage(conn *connections.RRConn, msgType uint8, channel uint8, body *
 	response.WriteByte(channel)                  // Unk, Channel?
 	response.Write(body)
 
-	written, err := conn.NetConn.Write(response.Data())
+	err := conn.Send(response)
 
-	if err != nil || written == 0 {
-		panic(err)
+	if err != nil {
+		log.Errorf("failed to send message to %d: %s", conn.GetID(), err.Error())
+		return
 	}
 
+	capture.RecordGame(conn.GetID(), capture.Outbound, msgType, 0x00, channel, body.Data())
+
 	log.Info(fmt.Sprintf("Sent: \n%s", hex.Dump(response.Data())))
 }
@@ -1,7 +1,17 @@
 package messages
 
-import "RainbowRunner/pkg/byter"
+import (
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes/marshal"
+)
 
 type DRMessage interface {
 	Write(b *byter.Byter)
 }
+
+// encode writes a message described with dr tags, the tags are fixed so any error is a mistake in the definition
+func encode(b *byter.Byter, message interface{}) {
+	if err := marshal.Encode(b, message); err != nil {
+		panic(err)
+	}
+}
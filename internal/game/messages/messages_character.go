@@ -0,0 +1,27 @@
+package messages
+
+// CharacterCreateRequest is sent by the client when a new character is created
+type CharacterCreateRequest struct {
+	_          struct{} `dr:"channel=4,type=2"`
+	Name       string
+	Class      string
+	Unk0       byte
+	Face       byte
+	Hair       byte
+	HairColour byte
+}
+
+// CharacterDeleteRequest is assumed to carry the name the player typed to confirm deleting the character, the format
+// has not been checked against the client, see DeleteCharacter in CharacterChannel.md
+type CharacterDeleteRequest struct {
+	_    struct{} `dr:"channel=4,type=4"`
+	Name string
+}
+
+// CharacterPlayRequest is sent by the client when a character is selected
+type CharacterPlayRequest struct {
+	_    struct{} `dr:"channel=4,type=5"`
+	Unk0 byte
+	Unk1 byte
+	Slot byte
+}
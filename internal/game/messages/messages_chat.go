@@ -4,24 +4,15 @@ import (
 	"RainbowRunner/pkg/byter"
 )
 
+// ChatMessage is sent on the ChatChannel, global announcements (13) do not have a sender
 type ChatMessage struct {
-	Channel MessageChannelSource
-	Unk0    int
-	Message string
-	Sender  string
+	_       struct{}             `dr:"channel=6,type=0"`
+	Channel MessageChannelSource `dr:"u8"`
+	Unk0    byte                 `dr:"if=Channel!=13"` // Unk, if not 0 then text colour is white
+	Sender  string               `dr:"cstring,if=Channel!=13"`
+	Message string               `dr:"cstring"`
 }
 
 func (c ChatMessage) Write(b *byter.Byter) {
-	b.WriteByte(byte(ChatChannel))
-	b.WriteByte(0x00) // Chat Message
-
-	b.WriteByte(byte(c.Channel))
-
-	if c.Channel != MessageChannelSourceGlobalAnnouncement {
-		b.WriteByte(0x00) // Unk, if not 0 then text colour is white
-		// Sender name
-		b.WriteCString(c.Sender)
-	}
-
-	b.WriteCString(c.Message)
+	encode(b, c)
 }
@@ -1,5 +1,7 @@
 package messages
 
+import "RainbowRunner/pkg/byter"
+
 type ZoneMessage byte
 
 const (
@@ -8,3 +10,27 @@ const (
 	ZoneMessageDisconnected
 	ZoneMessageInstanceCount ZoneMessage = 5
 )
+
+// ZoneReadyMessage tells the client the zone has finished loading
+type ZoneReadyMessage struct {
+	_       struct{} `dr:"channel=13,type=1"`
+	WorldID uint32
+	// MiniMapExplored::ReadExploredBits
+	ExploredBitCount uint16
+	ExploredBits     []uint32 `dr:"len=ExploredBitCount"`
+}
+
+func (m ZoneReadyMessage) Write(b *byter.Byter) {
+	encode(b, m)
+}
+
+// ZoneInstanceCountMessage adds two separate values into the ZoneClient
+type ZoneInstanceCountMessage struct {
+	_    struct{} `dr:"channel=13,type=5"`
+	Unk0 uint32
+	Unk1 uint32
+}
+
+func (m ZoneInstanceCountMessage) Write(b *byter.Byter) {
+	encode(b, m)
+}
@@ -0,0 +1,32 @@
+package game
+
+import (
+	"RainbowRunner/internal/connections"
+	"RainbowRunner/internal/metrics"
+	"encoding/hex"
+	log "github.com/sirupsen/logrus"
+	"runtime/debug"
+)
+
+// recoverClient stops a panic while handling a client from taking the whole server down, only the client that caused
+// it is disconnected. It must be deferred directly so recover can see the panic.
+func recoverClient(conn *connections.RRConn, source string, description string, data []byte) {
+	r := recover()
+
+	if r == nil {
+		return
+	}
+
+	metrics.RecoveredPanics.Inc(source)
+
+	log.Errorf(
+		"recovered panic in %s for connection %d, disconnecting: %v\n%s\ndata:\n%s",
+		description,
+		conn.GetID(),
+		r,
+		debug.Stack(),
+		hex.Dump(data),
+	)
+
+	conn.Close()
+}
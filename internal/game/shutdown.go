@@ -0,0 +1,164 @@
+package game
+
+import (
+	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/serverconfig"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"sort"
+	"time"
+)
+
+// Players are warned at each of these times before the server stops, as well as when the countdown starts
+var shutdownAnnouncements = []time.Duration{
+	5 * time.Minute,
+	2 * time.Minute,
+	time.Minute,
+	30 * time.Second,
+	10 * time.Second,
+	5 * time.Second,
+	3 * time.Second,
+	2 * time.Second,
+	time.Second,
+}
+
+// Shutdown warns everyone online, saves every character and disconnects them, the zones can't be used afterwards
+func Shutdown() {
+	options := serverconfig.Config.Shutdown
+
+	StopAccepting()
+
+	shutdownCountdown(options.GracePeriod)
+
+	log.Info("saving characters")
+
+	if !objects.Players.SaveAll(options.DrainTimeout) {
+		log.Warn("timed out waiting for characters to save")
+	}
+
+	objects.Zones.Stop()
+
+	log.Info("disconnecting players")
+
+	for _, player := range objects.Players.GetPlayers() {
+		if !player.Conn.Drain(options.DrainTimeout) {
+			log.Warnf("connection %d still had queued messages when it was closed", player.Conn.GetID())
+		}
+
+		player.Conn.Close()
+	}
+
+	if !waitTimeout(connectionHandlers.Wait, options.DrainTimeout) {
+		log.Warn("timed out waiting for connections to close")
+	}
+
+	objects.Zones.Close()
+}
+
+// StopAccepting closes the game server listener, players that are already connected are unaffected
+func StopAccepting() {
+	listenerLock.Lock()
+	defer listenerLock.Unlock()
+
+	if listener == nil {
+		return
+	}
+
+	if err := listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.Errorf("failed to close game server: %s", err.Error())
+	}
+}
+
+// shutdownCountdown announces the shutdown until the grace period is over, it returns straight away once nobody is
+// online
+func shutdownCountdown(gracePeriod time.Duration) {
+	deadline := time.Now().Add(gracePeriod)
+
+	for _, remaining := range shutdownAnnouncementTimes(gracePeriod) {
+		if !waitForPlayers(time.Until(deadline.Add(-remaining))) {
+			return
+		}
+
+		log.Infof("shutting down in %s", remaining)
+		objects.Players.Announce(fmt.Sprintf("The server is shutting down in %s.", formatCountdown(remaining)))
+	}
+
+	waitForPlayers(time.Until(deadline))
+}
+
+// waitForPlayers sleeps for up to d, returns false as soon as there are no players left to wait for
+func waitForPlayers(d time.Duration) bool {
+	deadline := time.Now().Add(d)
+
+	for {
+		if len(objects.Players.GetPlayers()) == 0 {
+			return false
+		}
+
+		remaining := time.Until(deadline)
+
+		if remaining <= 0 {
+			return true
+		}
+
+		if remaining > time.Second {
+			remaining = time.Second
+		}
+
+		time.Sleep(remaining)
+	}
+}
+
+func shutdownAnnouncementTimes(gracePeriod time.Duration) []time.Duration {
+	if gracePeriod <= 0 {
+		return nil
+	}
+
+	times := []time.Duration{gracePeriod}
+
+	for _, announcement := range shutdownAnnouncements {
+		if announcement < gracePeriod {
+			times = append(times, announcement)
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		return times[i] > times[j]
+	})
+
+	return times
+}
+
+func formatCountdown(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return plural(int(d/time.Minute), "minute")
+	}
+
+	return plural(int(d.Round(time.Second)/time.Second), "second")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func waitTimeout(wait func(), timeout time.Duration) bool {
+	done := make(chan struct{})
+
+	go func() {
+		wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
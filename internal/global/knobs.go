@@ -1,110 +1,258 @@
 package global
 
-//GlobalKnobs extends RPGSettings
-//{
-//MovementSpeedModifier = 25;
-//ExperienceMod = 5.0;
-//ItemBuyValueModifier = 1.0;
-//ItemSellValueModifier = 0.20; //was 0.35
-//ItemSoulBoundTime = 600;
-//
-//ReSpecTime = 900;
-//
-//MaxLevel = 100;
-//
-//ProfessionRatioMin = .6;
-//ElementPercentageMin = .375;
-////	AttrTitleSlopRatio = 0.9;//Default
-//
-//DPSModifier = 1.0;
-//
-//WeaponDamagePerLevel = 10;
-//SkillDamagePerLevel = 15;
-//
-//MonsterAttackSpeed = 100;
-//MonsterDamageMod = 0;
-//MonsterCriticalChance = 6; //5;
-//MonsterStunMod = 100;
-//MonsterHealthRegen = 2;
-//MonsterPowerRegen = 2;
-//MonsterStunResist = 1;
-//
-//// chance to actually miss when the AR/DR comparison is made (along with damage modifier in such cases)
-//HeroMissChance = 100;
-//HeroMissDamageMod = 0;
-//MonsterMissChance = 100;
-//MonsterMissDamageMod = 0;
-//
-//// chance for a stun check to be made when damage is applied
-//HeroStunChance = 100;
-//MonsterStunChance = 50;
-//
-//HeroHealthPerLevel = 16;
-//HealthPerEndurance = 25;
-//
-//HeroPowerRegen = 3;
-//HeroHealthRegen = 2;
-//HeroStunResist = 1;
-//HeroStunMod = 100;
-//HeroCriticalChance = 3;
-//HeroAttackSpeed = 100;
-//
-//BaseSkillPowerCost = 10;
-//SkillPowerCostPerLevel = 1.215;
-//
-//SkillDamagePerIntellect = 1.5; //1.1682; //was 1.062;
-//PowerPerIntellect = 17; //was 12;
-//PowerPerLevel = 5;
-//
-//DefenseRatingPerStrength = 14; //making Strength more meaningful for Defense Rating was: 8.26; //9.21; //5.76;
-//MeleeDamagePerStrength =  2.3364; //increasing this by 10% was: 2.124;
-//
-//AttackRatingPerAgility = 14; //doubled the values for more granularity in the system//7; //5.76
-//RangedDamagePerAgility = 2.124;
-//
-//ItemGoldValuePerLevel = 50;
-//SkillValuePerLevel = 1113.621; //was 1551.621
-//QuestGoldPerLevel = 250;
-//QuestExperiencePerLevel = 100;
-//
-//ItemDefenseRatingPerLevel = 8.26; //7.1; //2.5;
-//
-//MaxSameSounds = 2;
-//AvatarSoundOffset = 40;	// place ears AvatarSoundOffset units from avatar, along vector to camera
-//AvatarSoundFactor = 0;   // blend AvatarSoundFactor*distance from AvatarSoundOffset position to camera.
-//
-//// item level deltas based on quality
-//ItemLevelDeltaQuest = 0;
-//ItemLevelDeltaNormal = -12;
-//ItemLevelDeltaSuperior = -10;
-//ItemLevelDeltaMagical = -7;
-//ItemLevelDeltaRare = -5;
-//ItemLevelDeltaUnique = -2;
-//ItemLevelDeltaCollection = 0;
-//ItemLevelDeltaMythic = 3;
-//
-//ItemPriceModifierQuest = 0.13;
-//ItemPriceModifierNormal = 0.26;
-//ItemPriceModifierSuperior = 0.56;
-//ItemPriceModifierMagical = 1.07;
-//ItemPriceModifierRare = 2.03;
-//ItemPriceModifierUnique = 4.57;
-//ItemPriceModifierCollection = 1.0; //Relics use this
-//ItemPriceModifierMythic = 38.91; //was 14
-//
-//ItemChanceRequiresMembershipRare = 50;
-//ItemChanceRequiresMembershipUnique = 50;
-//ItemChanceRequiresMembershipMythic = 50;
-//
-//FreePlayerExperienceMult = 0.87;
-//FreePlayerExperienceMod = avatar.base.FreePlayerExperienceModifier;
-//
-//FreePlayerRequiredKingsCoinMult = 1.25;
-//FreePlayerKingsCoinInflationMessage = "<br><font effect=glow>*If you were a member, you'd only pay %d King's Coin!</font><br><br>Want to become a member and get the cheaper price? Too bad, because this game is shutting down at the end of the year.";
-//
-//MemberGoldMod = 1.15;
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// The GlobalKnobs used by the server, they are loaded from the extracted config by the database package and can be
+// overridden with the `knobs` section of config.yaml. Zones read them from their own goroutines so they are never
+// changed in place, a changed copy replaces them instead.
+var knobs atomic.Pointer[GlobalKnobs]
+var knobsLock sync.Mutex
+
+// Knobs returns the knobs in use, they must not be changed, use SetKnobs or UpdateKnobs instead
+func Knobs() *GlobalKnobs {
+	if current := knobs.Load(); current != nil {
+		return current
+	}
+
+	knobs.CompareAndSwap(nil, NewGlobalKnobs())
+
+	return knobs.Load()
+}
+
+func SetKnobs(k *GlobalKnobs) {
+	knobsLock.Lock()
+	defer knobsLock.Unlock()
+
+	knobs.Store(k)
+}
+
+// UpdateKnobs changes a copy of the knobs and then replaces them, updates from different goroutines run one at a time
+func UpdateKnobs(f func(k *GlobalKnobs)) {
+	knobsLock.Lock()
+	defer knobsLock.Unlock()
+
+	updated := *Knobs()
+	f(&updated)
+
+	knobs.Store(&updated)
+}
+
+// GlobalKnobs extends RPGSettings, field names match the config keys
 //
-//MinLevelToCreatePosse = 15;
-//GoldCostToCreatePosse = 1000000;
-//PosseInvitationTimeout = 30; // in seconds
-//}
+//go:generate go run ../../scripts/generatelua -type=GlobalKnobs
+type GlobalKnobs struct {
+	MovementSpeedModifier float32
+	ExperienceMod         float32
+	ItemBuyValueModifier  float32
+	ItemSellValueModifier float32
+	ItemSoulBoundTime     int
+
+	ReSpecTime int
+
+	MaxLevel int
+
+	ProfessionRatioMin   float32
+	ElementPercentageMin float32
+
+	DPSModifier float32
+
+	WeaponDamagePerLevel float32
+	SkillDamagePerLevel  float32
+
+	MonsterAttackSpeed    float32
+	MonsterDamageMod      float32
+	MonsterCriticalChance float32
+	MonsterStunMod        float32
+	MonsterHealthRegen    float32
+	MonsterPowerRegen     float32
+	MonsterStunResist     float32
+
+	// Chance to actually miss when the AR/DR comparison is made (along with damage modifier in such cases)
+	HeroMissChance       float32
+	HeroMissDamageMod    float32
+	MonsterMissChance    float32
+	MonsterMissDamageMod float32
+
+	// Chance for a stun check to be made when damage is applied
+	HeroStunChance    float32
+	MonsterStunChance float32
+
+	HeroHealthPerLevel float32
+	HealthPerEndurance float32
+
+	HeroPowerRegen     float32
+	HeroHealthRegen    float32
+	HeroStunResist     float32
+	HeroStunMod        float32
+	HeroCriticalChance float32
+	HeroAttackSpeed    float32
+
+	BaseSkillPowerCost     float32
+	SkillPowerCostPerLevel float32
+
+	SkillDamagePerIntellect float32
+	PowerPerIntellect       float32
+	PowerPerLevel           float32
+
+	DefenseRatingPerStrength float32
+	MeleeDamagePerStrength   float32
+
+	AttackRatingPerAgility float32
+	RangedDamagePerAgility float32
+
+	ItemGoldValuePerLevel   float32
+	SkillValuePerLevel      float32
+	QuestGoldPerLevel       float32
+	QuestExperiencePerLevel float32
+
+	ItemDefenseRatingPerLevel float32
+
+	MaxSameSounds     int
+	AvatarSoundOffset float32
+	AvatarSoundFactor float32
+
+	// Item level deltas based on quality
+	ItemLevelDeltaQuest      int
+	ItemLevelDeltaNormal     int
+	ItemLevelDeltaSuperior   int
+	ItemLevelDeltaMagical    int
+	ItemLevelDeltaRare       int
+	ItemLevelDeltaUnique     int
+	ItemLevelDeltaCollection int
+	ItemLevelDeltaMythic     int
+
+	ItemPriceModifierQuest      float32
+	ItemPriceModifierNormal     float32
+	ItemPriceModifierSuperior   float32
+	ItemPriceModifierMagical    float32
+	ItemPriceModifierRare       float32
+	ItemPriceModifierUnique     float32
+	ItemPriceModifierCollection float32
+	ItemPriceModifierMythic     float32
+
+	ItemChanceRequiresMembershipRare   int
+	ItemChanceRequiresMembershipUnique int
+	ItemChanceRequiresMembershipMythic int
+
+	FreePlayerExperienceMult float32
+	FreePlayerExperienceMod  string
+
+	FreePlayerRequiredKingsCoinMult     float32
+	FreePlayerKingsCoinInflationMessage string
+
+	MemberGoldMod float32
+
+	MinLevelToCreatePosse  int
+	GoldCostToCreatePosse  int
+	PosseInvitationTimeout int // Seconds
+}
+
+// NewGlobalKnobs returns the retail values, these are used for any key missing from the extracted config
+func NewGlobalKnobs() *GlobalKnobs {
+	return &GlobalKnobs{
+		MovementSpeedModifier: 25,
+		ExperienceMod:         5.0,
+		ItemBuyValueModifier:  1.0,
+		ItemSellValueModifier: 0.20,
+		ItemSoulBoundTime:     600,
+
+		ReSpecTime: 900,
+
+		MaxLevel: 100,
+
+		ProfessionRatioMin:   .6,
+		ElementPercentageMin: .375,
+
+		DPSModifier: 1.0,
+
+		WeaponDamagePerLevel: 10,
+		SkillDamagePerLevel:  15,
+
+		MonsterAttackSpeed:    100,
+		MonsterDamageMod:      0,
+		MonsterCriticalChance: 6,
+		MonsterStunMod:        100,
+		MonsterHealthRegen:    2,
+		MonsterPowerRegen:     2,
+		MonsterStunResist:     1,
+
+		HeroMissChance:       100,
+		HeroMissDamageMod:    0,
+		MonsterMissChance:    100,
+		MonsterMissDamageMod: 0,
+
+		HeroStunChance:    100,
+		MonsterStunChance: 50,
+
+		HeroHealthPerLevel: 16,
+		HealthPerEndurance: 25,
+
+		HeroPowerRegen:     3,
+		HeroHealthRegen:    2,
+		HeroStunResist:     1,
+		HeroStunMod:        100,
+		HeroCriticalChance: 3,
+		HeroAttackSpeed:    100,
+
+		BaseSkillPowerCost:     10,
+		SkillPowerCostPerLevel: 1.215,
+
+		SkillDamagePerIntellect: 1.5,
+		PowerPerIntellect:       17,
+		PowerPerLevel:           5,
+
+		DefenseRatingPerStrength: 14,
+		MeleeDamagePerStrength:   2.3364,
+
+		AttackRatingPerAgility: 14,
+		RangedDamagePerAgility: 2.124,
+
+		ItemGoldValuePerLevel:   50,
+		SkillValuePerLevel:      1113.621,
+		QuestGoldPerLevel:       250,
+		QuestExperiencePerLevel: 100,
+
+		ItemDefenseRatingPerLevel: 8.26,
+
+		MaxSameSounds:     2,
+		AvatarSoundOffset: 40,
+		AvatarSoundFactor: 0,
+
+		ItemLevelDeltaQuest:      0,
+		ItemLevelDeltaNormal:     -12,
+		ItemLevelDeltaSuperior:   -10,
+		ItemLevelDeltaMagical:    -7,
+		ItemLevelDeltaRare:       -5,
+		ItemLevelDeltaUnique:     -2,
+		ItemLevelDeltaCollection: 0,
+		ItemLevelDeltaMythic:     3,
+
+		ItemPriceModifierQuest:      0.13,
+		ItemPriceModifierNormal:     0.26,
+		ItemPriceModifierSuperior:   0.56,
+		ItemPriceModifierMagical:    1.07,
+		ItemPriceModifierRare:       2.03,
+		ItemPriceModifierUnique:     4.57,
+		ItemPriceModifierCollection: 1.0,
+		ItemPriceModifierMythic:     38.91,
+
+		ItemChanceRequiresMembershipRare:   50,
+		ItemChanceRequiresMembershipUnique: 50,
+		ItemChanceRequiresMembershipMythic: 50,
+
+		FreePlayerExperienceMult: 0.87,
+		FreePlayerExperienceMod:  "avatar.base.FreePlayerExperienceModifier",
+
+		FreePlayerRequiredKingsCoinMult:     1.25,
+		FreePlayerKingsCoinInflationMessage: "<br><font effect=glow>*If you were a member, you'd only pay %d King's Coin!</font><br><br>Want to become a member and get the cheaper price? Too bad, because this game is shutting down at the end of the year.",
+
+		MemberGoldMod: 1.15,
+
+		MinLevelToCreatePosse:  15,
+		GoldCostToCreatePosse:  1000000,
+		PosseInvitationTimeout: 30,
+	}
+}
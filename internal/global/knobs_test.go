@@ -0,0 +1,123 @@
+package global
+
+import (
+	"fmt"
+	lua2 "github.com/yuin/gopher-lua"
+	"sync"
+	"testing"
+)
+
+func newKnobsState() *lua2.LState {
+	state := lua2.NewState()
+	registerLuaGlobalKnobs(state)
+	registerLuaKnobs(state)
+
+	return state
+}
+
+func TestLuaKnobs(t *testing.T) {
+	SetKnobs(NewGlobalKnobs())
+
+	state := newKnobsState()
+	defer state.Close()
+
+	before := Knobs()
+
+	if err := state.DoString(`
+		Knobs:experienceMod(10)
+		copy = Knobs:getGlobalKnobs()
+		copy:experienceMod(20)
+		result = Knobs:experienceMod()
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	if Knobs().ExperienceMod != 10 {
+		t.Errorf("experienceMod is %f, expected 10", Knobs().ExperienceMod)
+	}
+
+	if result := state.GetGlobal("result"); result.(lua2.LNumber) != 10 {
+		t.Errorf("script read %v, expected 10", result)
+	}
+
+	if before.ExperienceMod == 10 {
+		t.Error("the knobs were changed in place")
+	}
+}
+
+func TestUpdateKnobsConcurrently(t *testing.T) {
+	SetKnobs(NewGlobalKnobs())
+
+	before := *Knobs()
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				UpdateKnobs(func(k *GlobalKnobs) {
+					k.DPSModifier++
+				})
+			}
+		}()
+
+		// Combat and the monster AI read the knobs while they are updated
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				_ = Knobs().DPSModifier * Knobs().MonsterAttackSpeed
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Updates run one at a time so none of them are lost
+	if Knobs().DPSModifier != before.DPSModifier+400 {
+		t.Errorf("dpsmodifier is %f, expected %f", Knobs().DPSModifier, before.DPSModifier+400)
+	}
+
+	if Knobs().MonsterAttackSpeed != before.MonsterAttackSpeed {
+		t.Error("updating one knob changed another")
+	}
+}
+
+func TestLuaKnobsFromSeveralStates(t *testing.T) {
+	SetKnobs(NewGlobalKnobs())
+
+	// Each state sets its own knob, a set from one state must not undo the sets from the others
+	knobNames := []string{"dpsmodifier", "experienceMod", "monsterAttackSpeed", "monsterDamageMod"}
+	wg := sync.WaitGroup{}
+
+	for _, name := range knobNames {
+		wg.Add(1)
+
+		go func(name string) {
+			defer wg.Done()
+
+			state := newKnobsState()
+			defer state.Close()
+
+			for j := 1; j <= 100; j++ {
+				if err := state.DoString(fmt.Sprintf(`Knobs:%s(%d)`, name, j)); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(name)
+	}
+
+	wg.Wait()
+
+	knobs := Knobs()
+
+	for i, value := range []float32{knobs.DPSModifier, knobs.ExperienceMod, knobs.MonsterAttackSpeed, knobs.MonsterDamageMod} {
+		if value != 100 {
+			t.Errorf("%s is %f, expected 100", knobNames[i], value)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package global
+
+import lua2 "github.com/yuin/gopher-lua"
+
+// registerLuaKnobs exposes the knobs to scripts, e.g. `Knobs:experienceMod(10)` for an event weekend. Each zone has
+// its own Lua state so the accessors always go through Knobs and UpdateKnobs rather than holding on to the knobs.
+func registerLuaKnobs(state *lua2.LState) {
+	methods := make(map[string]lua2.LGFunction)
+
+	for name, method := range luaMethodsGlobalKnobs() {
+		methods[name] = luaKnobsMethod(method)
+	}
+
+	mt := state.NewTypeMetatable("Knobs")
+	state.SetField(mt, "__index", state.SetFuncs(state.NewTable(), methods))
+
+	ud := state.NewUserData()
+	state.SetMetatable(ud, mt)
+	state.SetGlobal("Knobs", ud)
+}
+
+// luaKnobsMethod runs a generated GlobalKnobs accessor, getters read a copy of the current knobs so scripts can't
+// change them by holding on to the result and setters go through UpdateKnobs
+func luaKnobsMethod(method lua2.LGFunction) lua2.LGFunction {
+	return func(l *lua2.LState) int {
+		if l.GetTop() == 0 {
+			l.ArgError(1, "Knobs methods must be called with Knobs:method()")
+			return 0
+		}
+
+		if l.GetTop() == 1 {
+			current := *Knobs()
+			l.Replace(1, current.ToLua(l))
+
+			return method(l)
+		}
+
+		results := 0
+
+		UpdateKnobs(func(k *GlobalKnobs) {
+			l.Replace(1, k.ToLua(l))
+			results = method(l)
+		})
+
+		return results
+	}
+}
@@ -1,15 +1,24 @@
 package global
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
+)
 
 // 33 is ideal
 const TickInterval = 33
 
-var Tick = uint(0)
+// Zones tick on their own goroutines so the tick count is only touched atomically
+var tick uint64
 var ServerStartTime = time.Time{}
 
 func GetTick() uint {
-	return Tick
+	return uint(atomic.LoadUint64(&tick))
+}
+
+// AdvanceTick is only called by the game loop
+func AdvanceTick() {
+	atomic.AddUint64(&tick, 1)
 }
 
 func GetDeltaTime() float64 {
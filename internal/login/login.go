@@ -2,12 +2,20 @@ package login
 
 import (
 	"RainbowRunner/internal/message"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/serverconfig"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"net"
+	"runtime/debug"
+	"sync"
 )
 
+var listener net.Listener
+var listenerLock sync.Mutex
+
 func StartLoginServer() {
 	listen, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", serverconfig.Config.Network.LoginServerPort))
 
@@ -15,27 +23,65 @@ func StartLoginServer() {
 		panic(err)
 	}
 
-	defer func() {
-		err := listen.Close()
-		if err != nil {
-			panic(err)
-		}
-	}()
+	listenerLock.Lock()
+	listener = listen
+	listenerLock.Unlock()
 
 	for {
 		conn, err := listen.Accept()
 
 		if err != nil {
-			panic(err)
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			log.Errorf("failed to accept login connection: %s", err.Error())
+			continue
 		}
 
 		go handleConnection(conn)
 	}
 }
 
+// StopLoginServer stops accepting new logins, clients that are already logging in are left to finish
+func StopLoginServer() {
+	listenerLock.Lock()
+	defer listenerLock.Unlock()
+
+	if listener == nil {
+		return
+	}
+
+	if err := listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.Errorf("failed to close login server: %s", err.Error())
+	}
+}
+
 func handleConnection(conn net.Conn) {
 	parser := message.NewAuthMessageParser(conn)
 	buf := make([]byte, 1024*10)
+	read := 0
+
+	// A malformed login must only drop the client that sent it
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			return
+		}
+
+		metrics.RecoveredPanics.Inc("login")
+
+		log.Errorf(
+			"recovered panic in login connection from %s, disconnecting: %v\n%s\ndata:\n%s",
+			conn.RemoteAddr().String(),
+			r,
+			debug.Stack(),
+			hex.Dump(buf[:read]),
+		)
+
+		_ = conn.Close()
+	}()
 
 	fmt.Println("Client connected")
 
@@ -45,18 +91,31 @@ func handleConnection(conn net.Conn) {
 	})
 
 	if err != nil {
-		panic(err)
+		log.Errorf("failed to greet login connection from %s: %s", conn.RemoteAddr().String(), err.Error())
+		_ = conn.Close()
+		return
 	}
 
 	for {
-		read, err := conn.Read(buf)
+		n, err := conn.Read(buf)
+		read = n
 
 		if err != nil {
 			log.Info(fmt.Sprintf("failed to read from connection: %e\n", err))
 			break
 		}
 
-		parser.Parse(buf, read)
+		err = parser.Parse(buf, read)
+
+		if errors.Is(err, message.ErrLoginFailed) {
+			log.Infof("closing login connection from %s after a failed login", conn.RemoteAddr().String())
+			break
+		}
+
+		if err != nil {
+			log.Errorf("closing login connection from %s: %s", conn.RemoteAddr().String(), err.Error())
+			break
+		}
 	}
 
 	err = conn.Close()
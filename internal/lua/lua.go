@@ -24,7 +24,8 @@ func reloadScripts() error {
 			return nil
 		}
 
-		splitPath := strings.Split(path, "\\")
+		// Split on slashes so script IDs are the same on every OS
+		splitPath := strings.Split(filepath.ToSlash(path), "/")
 		splitPathLength := len(splitPath)
 		fileName := splitPath[splitPathLength-1]
 		scriptName := strings.Split(fileName, ".")[0]
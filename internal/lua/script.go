@@ -24,6 +24,11 @@ func (s *LuaScript) Execute(state *lua2.LState) error {
 	return nil
 }
 
+// ID is the dotted path of the script under the scripts directory, e.g. zones.town.main
+func (s *LuaScript) ID() string {
+	return s.id
+}
+
 func (s *LuaScript) load() {
 	//TODO add optional caching
 	fh, err := os.Open(s.path)
@@ -8,10 +8,19 @@ import (
 )
 
 func HandleAboutToPlay(p *AuthMessageParser, reader *byter.Byter) error {
-	// SessionID
-	reader.UInt64()
+	sessionID1 := reader.UInt32()
+	sessionID2 := reader.UInt32()
 	serverID := reader.UInt8()
 
+	if !p.IsValidSession(sessionID1, sessionID2) {
+		log.Errorf("Invalid session for %s trying to join server %d", p.Username, serverID)
+
+		response := byter.NewLEByter(make([]byte, 0, 4))
+		response.WriteUInt32(uint32(AuthLoginFailAccessFailed))
+
+		return p.WriteAuthMessage(AuthServerPlayFailPacket, response)
+	}
+
 	log.Info(fmt.Sprintf("Wants to join server %d\n", serverID))
 
 	response := byter.NewLEByter(make([]byte, 0, 0xFF))
@@ -1,31 +1,63 @@
 package message
 
 import (
+	"RainbowRunner/internal/accounts"
 	byter "RainbowRunner/pkg/byter"
 	crypt "RainbowRunner/pkg/crypt"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"strings"
+	"time"
 )
 
+// These match the reasons used by the NCSoft auth protocol, the client picks the error text based on them
+type AuthLoginFailReason uint32
+
+const (
+	AuthLoginFailSystemError       AuthLoginFailReason = 0x01
+	AuthLoginFailPasswordWrong     AuthLoginFailReason = 0x02
+	AuthLoginFailUserOrPassWrong   AuthLoginFailReason = 0x03
+	AuthLoginFailAccessFailed      AuthLoginFailReason = 0x04
+	AuthLoginFailAccountInUse      AuthLoginFailReason = 0x07
+	AuthBlockedAccountPermanentBan AuthLoginFailReason = 0x20
+)
+
+var ErrLoginFailed = errors.New("login failed")
+
+// How long a wrong username or password waits before it is answered
+var loginFailDelay = 2 * time.Second
+
 func HandleLoginMessage(conn *AuthMessageParser, reader *byter.Byter) error {
 	decryptedLogin := crypt.DecryptDES(reader.Bytes(0x18), 0x18)
 	remainingPassword := reader.Bytes(0x1E - 0x18)
 	decryptedLogin = append(decryptedLogin, remainingPassword...)
-	username := string(decryptedLogin[0:14])
-	password := string(decryptedLogin[14:])
-	log.Info(fmt.Sprintf("Login attempt with %s:%s\n", username, password))
+	username := nullTerminated(decryptedLogin[0:14])
+	password := nullTerminated(decryptedLogin[14:])
 
-	var sb strings.Builder
+	log.Info(fmt.Sprintf("Login attempt with %s\n", username))
 
-	for _, char := range username {
-		if char == 0 {
-			break
-		}
-		sb.WriteRune(char)
+	_, err := accounts.Authenticate(username, password)
+
+	if err != nil {
+		return conn.rejectLogin(username, err)
 	}
 
-	conn.Username = sb.String()
+	conn.Username = username
+
+	conn.sessionID1, err = generateSessionID()
+
+	if err != nil {
+		return err
+	}
+
+	conn.sessionID2, err = generateSessionID()
+
+	if err != nil {
+		return err
+	}
 
 	/**
 	00000000 linACLoginOkPacket struc ; (sizeof=0x38, align=0x4, copyof_811)
@@ -48,8 +80,8 @@ func HandleLoginMessage(conn *AuthMessageParser, reader *byter.Byter) error {
 
 	var response = byter.NewLEByter(make([]byte, 0, 128))
 
-	response.WriteUInt32(0xFFEEFFEE)
-	response.WriteUInt32(0xAABBAABB)
+	response.WriteUInt32(conn.sessionID1)
+	response.WriteUInt32(conn.sessionID2)
 	response.WriteUInt32(0xDDCCDDCC)
 	response.WriteUInt32(0xBBCCBBCC)
 	response.WriteUInt32(0x00000000)
@@ -62,7 +94,7 @@ func HandleLoginMessage(conn *AuthMessageParser, reader *byter.Byter) error {
 	response.WriteBool(true)
 	response.WriteBool(true)
 
-	err := conn.WriteAuthMessage(AuthServerLoginOkPacket, response)
+	err = conn.WriteAuthMessage(AuthServerLoginOkPacket, response)
 
 	if err != nil {
 		return err
@@ -70,3 +102,58 @@ func HandleLoginMessage(conn *AuthMessageParser, reader *byter.Byter) error {
 
 	return nil
 }
+
+// rejectLogin tells the client why the login failed and returns ErrLoginFailed so the connection is closed, wrong
+// passwords are only answered after loginFailDelay to slow down guessing
+func (p *AuthMessageParser) rejectLogin(username string, err error) error {
+	var writeErr error
+
+	switch {
+	case errors.Is(err, accounts.ErrAccountBanned):
+		log.Infof("Login rejected for banned account %s", username)
+		writeErr = p.writeLoginFail(AuthServerBlockedAccountPacket, AuthBlockedAccountPermanentBan)
+	case errors.Is(err, accounts.ErrInvalidCredentials):
+		log.Infof("Login failed for %s", username)
+		time.Sleep(loginFailDelay)
+		writeErr = p.writeLoginFail(AuthServerLoginFailPacket, AuthLoginFailUserOrPassWrong)
+	default:
+		log.Errorf("Login failed for %s: %s", username, err.Error())
+		writeErr = p.writeLoginFail(AuthServerLoginFailPacket, AuthLoginFailSystemError)
+	}
+
+	if writeErr != nil {
+		return writeErr
+	}
+
+	return ErrLoginFailed
+}
+
+func (p *AuthMessageParser) writeLoginFail(messageType AuthServerMessage, reason AuthLoginFailReason) error {
+	response := byter.NewLEByter(make([]byte, 0, 4))
+	response.WriteUInt32(uint32(reason))
+
+	return p.WriteAuthMessage(messageType, response)
+}
+
+func generateSessionID() (uint32, error) {
+	b := make([]byte, 4)
+
+	if _, err := rand.Read(b); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func nullTerminated(data []byte) string {
+	var sb strings.Builder
+
+	for _, char := range data {
+		if char == 0 {
+			break
+		}
+		sb.WriteByte(char)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,182 @@
+package message
+
+import (
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
+	"RainbowRunner/pkg"
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/crypt"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// clientPacket encrypts a payload the same way the client does, the client uses the same framing as the server
+func clientPacket(t *testing.T, payload []byte) []byte {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	packet := make(chan []byte)
+
+	go func() {
+		data, _ := io.ReadAll(server)
+		packet <- data
+	}()
+
+	if _, err := pkg.NewConnection(client).WriteMessageBytes(payload); err != nil {
+		t.Fatal(err)
+	}
+
+	client.Close()
+
+	return <-packet
+}
+
+func loginPayload(username string, password string) []byte {
+	credentials := make([]byte, 14+16)
+	copy(credentials, username)
+	copy(credentials[14:], password)
+
+	payload := []byte{byte(AuthClientLoginPacket)}
+	payload = append(payload, crypt.EncryptDES(credentials[:0x18], 0x18)...)
+
+	return append(payload, credentials[0x18:]...)
+}
+
+// newTestAuthConnection returns a parser and the client end of its connection
+func newTestAuthConnection(t *testing.T) (*AuthMessageParser, net.Conn) {
+	client, server := net.Pipe()
+
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	return NewAuthMessageParser(server), client
+}
+
+// readAuthMessage reads and decrypts the next message sent to the client
+func readAuthMessage(t *testing.T, client net.Conn) (AuthServerMessage, *byter.Byter) {
+	t.Helper()
+
+	header := make([]byte, 2)
+
+	if _, err := io.ReadFull(client, header); err != nil {
+		t.Fatal(err)
+	}
+
+	body := make([]byte, binary.LittleEndian.Uint16(header)-2)
+
+	if _, err := io.ReadFull(client, body); err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted := crypt.DecryptBlowfish(body, len(body))
+
+	if !verifyChecksum(decrypted) {
+		t.Fatal("message from the server has a bad checksum")
+	}
+
+	reader := byter.NewLEByter(decrypted)
+
+	return AuthServerMessage(reader.UInt8()), reader
+}
+
+// useAccounts stores the accounts with the password "secret", the password is hashed with the lowest cost to keep the
+// tests fast
+func useAccounts(t *testing.T, accounts ...*storage.Account) {
+	store := storage.NewMemoryStore()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, account := range accounts {
+		account.PasswordHash = string(hash)
+
+		if err = store.CreateAccount(account); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldStore, oldAutoRegister, oldDelay := storage.Store, serverconfig.Config.Accounts.AutoRegister, loginFailDelay
+	storage.Store, serverconfig.Config.Accounts.AutoRegister, loginFailDelay = store, false, 50*time.Millisecond
+
+	t.Cleanup(func() {
+		storage.Store, serverconfig.Config.Accounts.AutoRegister, loginFailDelay = oldStore, oldAutoRegister, oldDelay
+	})
+}
+
+func TestHandleLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		response AuthServerMessage
+		reason   AuthLoginFailReason
+		delayed  bool
+	}{
+		{name: "correct", username: "tester", password: "secret", response: AuthServerLoginOkPacket},
+		{name: "wrong password", username: "tester", password: "wrong", response: AuthServerLoginFailPacket, reason: AuthLoginFailUserOrPassWrong, delayed: true},
+		{name: "unknown account", username: "other", password: "secret", response: AuthServerLoginFailPacket, reason: AuthLoginFailUserOrPassWrong, delayed: true},
+		{name: "banned", username: "banned", password: "secret", response: AuthServerBlockedAccountPacket, reason: AuthBlockedAccountPermanentBan},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useAccounts(t,
+				&storage.Account{Name: "tester"},
+				&storage.Account{Name: "banned", Banned: true},
+			)
+
+			parser, client := newTestAuthConnection(t)
+			packet := clientPacket(t, loginPayload(test.username, test.password))
+
+			parsed := make(chan error, 1)
+			start := time.Now()
+
+			go func() {
+				parsed <- parser.Parse(packet, len(packet))
+			}()
+
+			response, reader := readAuthMessage(t, client)
+			elapsed := time.Since(start)
+			err := <-parsed
+
+			if response != test.response {
+				t.Fatalf("got %s, expected %s", response.String(), test.response.String())
+			}
+
+			if test.response == AuthServerLoginOkPacket {
+				if err != nil || parser.Username != test.username {
+					t.Errorf("login ok but got %v with username %q", err, parser.Username)
+				}
+
+				return
+			}
+
+			// Failed logins are closed by the login server when Parse returns an error
+			if !errors.Is(err, ErrLoginFailed) {
+				t.Errorf("got %v, expected ErrLoginFailed", err)
+			}
+
+			if reason := AuthLoginFailReason(reader.UInt32()); reason != test.reason {
+				t.Errorf("got reason %d, expected %d", reason, test.reason)
+			}
+
+			if test.delayed && elapsed < loginFailDelay {
+				t.Errorf("failed login was answered after %s", elapsed)
+			}
+
+			if parser.Username != "" || parser.IsValidSession(parser.sessionID1, parser.sessionID2) {
+				t.Error("failed login was given a session")
+			}
+		})
+	}
+}
@@ -1,46 +1,103 @@
 package message
 
 import (
+	"RainbowRunner/internal/capture"
 	"RainbowRunner/pkg"
 	"RainbowRunner/pkg/byter"
 	"RainbowRunner/pkg/crypt"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"net"
+	"sync/atomic"
 )
 
+var lastConnectionID int64
+
 type AuthMessageParser struct {
+	ID         int
 	connection *pkg.Connection
 	Username   string
+
+	sessionID1 uint32
+	sessionID2 uint32
+
+	buffer []byte
 }
 
-func (p *AuthMessageParser) Parse(read []byte, count int) {
-	var readBytes = 0
+// Encrypted payloads are made of 8 byte blowfish blocks and always contain at least one data block and the checksum block
+const minAuthPacketLength = 2 + 16
+const maxAuthPacketLength = 0x1000
+
+// Parse buffers the data read from the connection and handles every complete packet, an error means the connection
+// is sending garbage and should be closed
+func (p *AuthMessageParser) Parse(read []byte, count int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(fmt.Sprintf("failed to handle auth message: %v", r))
+		}
+	}()
+
+	p.buffer = append(p.buffer, read[:count]...)
+
+	for len(p.buffer) >= 2 {
+		packetLength := int(binary.LittleEndian.Uint16(p.buffer))
+
+		if packetLength < minAuthPacketLength || packetLength > maxAuthPacketLength || (packetLength-2)%8 != 0 {
+			return errors.New(fmt.Sprintf("invalid auth packet length %d", packetLength))
+		}
+
+		if len(p.buffer) < packetLength {
+			return nil
+		}
+
+		var decrypted = crypt.DecryptBlowfish(p.buffer[2:packetLength], packetLength-2)
+
+		remaining := copy(p.buffer, p.buffer[packetLength:])
+		p.buffer = p.buffer[:remaining]
 
-	for readBytes < count {
-		packetLength := int(binary.LittleEndian.Uint16(read[readBytes:]))
+		if !verifyChecksum(decrypted) {
+			return errors.New("invalid auth packet checksum")
+		}
 
-		var decrypted = crypt.DecryptBlowfish(read[readBytes+2:readBytes+packetLength-2], packetLength-2)
-		var byteReader = byter.NewLEByter(decrypted)
+		err = p.processMessage(byter.NewLEByter(decrypted), packetLength)
 
-		if count-readBytes >= packetLength {
-			p.processMessage(byteReader, packetLength)
-			readBytes += packetLength
-		} else {
-			panic("Can't handle the split packets right now")
+		if err != nil {
+			return err
 		}
 	}
+
+	return nil
 }
 
-func (p *AuthMessageParser) processMessage(reader *byter.Byter, length int) {
+// verifyChecksum checks the XOR checksum added by the sender, the checksum is the XOR of every data word and the
+// padding is all zeroes so XORing every word in the payload including the checksum always results in 0
+func verifyChecksum(decrypted []byte) bool {
+	checksum := uint32(0)
+
+	for i := 0; i+4 <= len(decrypted); i += 4 {
+		checksum ^= binary.LittleEndian.Uint32(decrypted[i:])
+	}
+
+	return checksum == 0
+}
+
+func (p *AuthMessageParser) processMessage(reader *byter.Byter, length int) error {
 	messageTypeID := reader.UInt8()
 
-	log.Info(fmt.Sprintf(
-		"Received %s (%d bytes):\n%s\n",
-		AuthClientMessage(messageTypeID).String(), length, hex.Dump(reader.Buffer),
-	))
+	// The login packet contains the password and the DES key is shared by every client so never dump it
+	if AuthClientMessage(messageTypeID) == AuthClientLoginPacket {
+		log.Info(fmt.Sprintf("Received %s (%d bytes)\n", AuthClientMessage(messageTypeID).String(), length))
+		capture.RecordLogin(p.ID, capture.Inbound, messageTypeID, nil)
+	} else {
+		log.Info(fmt.Sprintf(
+			"Received %s (%d bytes):\n%s\n",
+			AuthClientMessage(messageTypeID).String(), length, hex.Dump(reader.Buffer),
+		))
+		capture.RecordLogin(p.ID, capture.Inbound, messageTypeID, reader.Buffer[1:])
+	}
 
 	var err error
 
@@ -53,9 +110,16 @@ func (p *AuthMessageParser) processMessage(reader *byter.Byter, length int) {
 		err = HandleServerListMessage(p, reader)
 	}
 
-	if err != nil {
-		panic(err)
+	return err
+}
+
+// IsValidSession checks the session IDs sent by the client match the ones given to it on login
+func (p *AuthMessageParser) IsValidSession(sessionID1, sessionID2 uint32) bool {
+	if p.Username == "" {
+		return false
 	}
+
+	return p.sessionID1 == sessionID1 && p.sessionID2 == sessionID2
 }
 
 func (p *AuthMessageParser) WriteAuthMessage(messageType AuthServerMessage, response *byter.Byter) error {
@@ -66,12 +130,14 @@ func (p *AuthMessageParser) WriteAuthMessage(messageType AuthServerMessage, resp
 	}
 
 	log.Info(fmt.Sprintf("Sent %s (%d bytes):\n%s\n", messageType.String(), len(sent), hex.Dump(sent)))
+	capture.RecordLogin(p.ID, capture.Outbound, byte(messageType), response.Buffer)
 
 	return nil
 }
 
 func NewAuthMessageParser(conn net.Conn) *AuthMessageParser {
 	return &AuthMessageParser{
+		ID:         int(atomic.AddInt64(&lastConnectionID, 1)),
 		connection: pkg.NewConnection(conn),
 	}
 }
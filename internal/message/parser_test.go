@@ -0,0 +1,173 @@
+package message
+
+import (
+	"RainbowRunner/internal/storage"
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func bytesOneAtATime(data []byte) [][]byte {
+	chunks := make([][]byte, len(data))
+
+	for i := range data {
+		chunks[i] = data[i : i+1]
+	}
+
+	return chunks
+}
+
+func splitAt(data []byte, at ...int) [][]byte {
+	chunks := make([][]byte, 0, len(at)+1)
+	last := 0
+
+	for _, i := range at {
+		chunks = append(chunks, data[last:i])
+		last = i
+	}
+
+	return append(chunks, data[last:])
+}
+
+func TestParse(t *testing.T) {
+	useAccounts(t, &storage.Account{Name: "tester"})
+
+	login := clientPacket(t, loginPayload("tester", "secret"))
+
+	tests := []struct {
+		name    string
+		packets int
+		chunks  func(stream []byte) [][]byte
+	}{
+		{"whole", 1, nil},
+		{"byte at a time", 1, bytesOneAtATime},
+		{"split in length", 1, func(s []byte) [][]byte { return splitAt(s, 1) }},
+		{"split after length", 1, func(s []byte) [][]byte { return splitAt(s, 2) }},
+		{"split in body", 1, func(s []byte) [][]byte { return splitAt(s, 13) }},
+		{"split before last byte", 1, func(s []byte) [][]byte { return splitAt(s, len(s)-1) }},
+		{"two in one read", 2, nil},
+		{"two byte at a time", 2, bytesOneAtATime},
+		{"two split across packets", 2, func(s []byte) [][]byte { return splitAt(s, 10, len(login)+5) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser, client := newTestAuthConnection(t)
+			stream := bytes.Repeat(login, test.packets)
+			chunks := [][]byte{stream}
+
+			if test.chunks != nil {
+				chunks = test.chunks(stream)
+			}
+
+			parsed := make(chan error, 1)
+
+			go func() {
+				for _, chunk := range chunks {
+					if err := parser.Parse(chunk, len(chunk)); err != nil {
+						parsed <- err
+						return
+					}
+				}
+
+				parsed <- nil
+			}()
+
+			// Every login is answered once it is complete
+			for i := 0; i < test.packets; i++ {
+				if response, _ := readAuthMessage(t, client); response != AuthServerLoginOkPacket {
+					t.Fatalf("got %s for login %d, expected %s", response.String(), i, AuthServerLoginOkPacket.String())
+				}
+			}
+
+			if err := <-parsed; err != nil {
+				t.Fatal(err)
+			}
+
+			if len(parser.buffer) != 0 {
+				t.Errorf("%d bytes left in the parser", len(parser.buffer))
+			}
+
+			if parser.Username != "tester" {
+				t.Errorf("got username %q after the login", parser.Username)
+			}
+		})
+	}
+}
+
+func TestParseInvalidLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+	}{
+		{"zero", 0},
+		{"only the length", 2},
+		{"no checksum block", minAuthPacketLength - 8},
+		{"not whole blocks", minAuthPacketLength + 1},
+		{"too long", maxAuthPacketLength + 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser, _ := newTestAuthConnection(t)
+
+			// The length is checked before the rest of the packet arrives
+			header := binary.LittleEndian.AppendUint16(nil, uint16(test.length))
+
+			if err := parser.Parse(header, len(header)); err == nil {
+				t.Errorf("length %d was accepted", test.length)
+			}
+		})
+	}
+
+	// The longest packet made of whole blocks is waited for rather than rejected
+	longest := maxAuthPacketLength - (maxAuthPacketLength-2)%8
+	parser, _ := newTestAuthConnection(t)
+	header := binary.LittleEndian.AppendUint16(nil, uint16(longest))
+
+	if err := parser.Parse(header, len(header)); err != nil {
+		t.Errorf("got %s for the longest packet", err.Error())
+	}
+}
+
+func TestParseBadChecksum(t *testing.T) {
+	useAccounts(t, &storage.Account{Name: "tester"})
+
+	packet := clientPacket(t, loginPayload("tester", "secret"))
+
+	// Changing the last block changes the decrypted checksum
+	packet[len(packet)-1] ^= 0xFF
+
+	parser, _ := newTestAuthConnection(t)
+
+	if err := parser.Parse(packet, len(packet)); err == nil {
+		t.Fatal("packet with a bad checksum was accepted")
+	}
+
+	if parser.Username != "" {
+		t.Error("packet with a bad checksum was handled")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	tests := []struct {
+		name      string
+		decrypted []byte
+		expected  bool
+	}{
+		{"empty", []byte{}, true},
+		{"all zero", make([]byte, 16), true},
+		{"matching checksum", []byte{0x01, 0x02, 0x03, 0x04, 0x10, 0x20, 0x30, 0x40, 0x11, 0x22, 0x33, 0x44}, true},
+		{"wrong checksum", []byte{0x01, 0x02, 0x03, 0x04, 0x10, 0x20, 0x30, 0x40, 0x11, 0x22, 0x33, 0x45}, false},
+		{"missing checksum", []byte{0x01, 0x02, 0x03, 0x04, 0x00, 0x00, 0x00, 0x00}, false},
+		{"trailing bytes ignored", []byte{0x01, 0x02, 0x03, 0x04, 0x01, 0x02, 0x03, 0x04, 0xFF, 0xFF}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if verifyChecksum(test.decrypted) != test.expected {
+				t.Errorf("got %v, expected %v", !test.expected, test.expected)
+			}
+		})
+	}
+}
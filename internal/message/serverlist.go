@@ -8,10 +8,12 @@ import (
 )
 
 func HandleServerListMessage(c *AuthMessageParser, reader *byter.Byter) error {
-	// Session1
-	reader.UInt32()
-	// Session2
-	reader.UInt32()
+	sessionID1 := reader.UInt32()
+	sessionID2 := reader.UInt32()
+
+	if !c.IsValidSession(sessionID1, sessionID2) {
+		return c.writeLoginFail(AuthServerSendServerFailPacket, AuthLoginFailAccessFailed)
+	}
 
 	/**
 	00000000 linACSendServerListExPacket struc ; (sizeof=0x24, align=0x4, copyof_819)
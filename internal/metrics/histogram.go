@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Only the most recent samples are kept so the histogram shows how the server is doing now rather than since it
+// started, at 33ms per tick this is roughly the last 30 seconds
+const rollingSamples = 900
+
+// Upper bounds of the histogram buckets in milliseconds, anything slower goes in the last bucket
+var bucketBounds = []float64{1, 2, 5, 10, 20, 33, 50, 100, 250, 500, 1000}
+
+type Bucket struct {
+	// LessOrEqualMs is the upper bound of the bucket, it is infinite for the last bucket and is left out of the JSON
+	LessOrEqualMs float64 `json:"le_ms,omitempty"`
+	Count         int     `json:"count"`
+}
+
+type HistogramSnapshot struct {
+	Count   int      `json:"count"`
+	MeanMs  float64  `json:"mean_ms"`
+	P50Ms   float64  `json:"p50_ms"`
+	P95Ms   float64  `json:"p95_ms"`
+	P99Ms   float64  `json:"p99_ms"`
+	MaxMs   float64  `json:"max_ms"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// RollingHistogram keeps the last rollingSamples durations in a ring buffer
+type RollingHistogram struct {
+	sync.Mutex
+
+	samples []time.Duration
+	next    int
+}
+
+func (h *RollingHistogram) Observe(d time.Duration) {
+	h.Lock()
+	defer h.Unlock()
+
+	if len(h.samples) < rollingSamples {
+		h.samples = append(h.samples, d)
+		return
+	}
+
+	h.samples[h.next] = d
+	h.next = (h.next + 1) % rollingSamples
+}
+
+func (h *RollingHistogram) Snapshot() HistogramSnapshot {
+	h.Lock()
+	sorted := make([]time.Duration, len(h.samples))
+	copy(sorted, h.samples)
+	h.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	snapshot := HistogramSnapshot{
+		Count:   len(sorted),
+		Buckets: make([]Bucket, len(bucketBounds)+1),
+	}
+
+	for i, bound := range bucketBounds {
+		snapshot.Buckets[i].LessOrEqualMs = bound
+	}
+
+	if len(sorted) == 0 {
+		return snapshot
+	}
+
+	var total time.Duration
+
+	for _, sample := range sorted {
+		total += sample
+		snapshot.Buckets[bucketIndex(milliseconds(sample))].Count++
+	}
+
+	snapshot.MeanMs = milliseconds(total) / float64(len(sorted))
+	snapshot.P50Ms = percentile(sorted, 0.50)
+	snapshot.P95Ms = percentile(sorted, 0.95)
+	snapshot.P99Ms = percentile(sorted, 0.99)
+	snapshot.MaxMs = milliseconds(sorted[len(sorted)-1])
+
+	return snapshot
+}
+
+func bucketIndex(ms float64) int {
+	for i, bound := range bucketBounds {
+		if ms <= bound {
+			return i
+		}
+	}
+
+	return len(bucketBounds)
+}
+
+// percentile uses the nearest rank of the already sorted samples
+func percentile(sorted []time.Duration, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	if rank < 0 {
+		rank = 0
+	}
+
+	return milliseconds(sorted[rank])
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"RainbowRunner/internal/global"
+	"fmt"
+	"github.com/goccy/go-json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ticksResponse struct {
+	BudgetMs      float64                 `json:"budget_ms"`
+	WindowSamples int                     `json:"window_samples"`
+	Loops         map[string]LoopSnapshot `json:"loops"`
+}
+
+// HandleTicks serves the rolling tick timings of every loop as JSON
+func HandleTicks(w http.ResponseWriter, req *http.Request) {
+	response := ticksResponse{
+		BudgetMs:      global.TickInterval,
+		WindowSamples: rollingSamples,
+		Loops:         Ticks.Snapshot(),
+	}
+
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		fmt.Printf("[metrics][error] could not serialise tick timings: %s\n", err.Error())
+		w.WriteHeader(500)
+		return
+	}
+
+	headers := w.Header()
+	headers.Set("Access-Control-Allow-Origin", "*")
+	headers.Set("Content-Type", "application/json")
+
+	w.WriteHeader(200)
+	w.Write(data)
+}
+
+// HandleMetrics serves every registered metric in the Prometheus text format
+func HandleMetrics(w http.ResponseWriter, req *http.Request) {
+	sb := strings.Builder{}
+
+	for _, c := range defaultRegistry.sorted() {
+		name, help, t := c.describe()
+		samples := c.collect()
+
+		sortSamples(samples)
+
+		sb.WriteString(fmt.Sprintf("# HELP %s %s\n", name, help))
+		sb.WriteString(fmt.Sprintf("# TYPE %s %s\n", name, t))
+
+		for _, sample := range samples {
+			sb.WriteString(name)
+			writeLabels(&sb, sample.Labels)
+			sb.WriteString(" ")
+			sb.WriteString(strconv.FormatFloat(sample.Value, 'g', -1, 64))
+			sb.WriteString("\n")
+		}
+	}
+
+	headers := w.Header()
+	headers.Set("Access-Control-Allow-Origin", "*")
+	headers.Set("Content-Type", "text/plain; version=0.0.4")
+
+	w.WriteHeader(200)
+	w.Write([]byte(sb.String()))
+}
+
+func writeLabels(sb *strings.Builder, labels []Label) {
+	if len(labels) == 0 {
+		return
+	}
+
+	sb.WriteString("{")
+
+	for i, label := range labels {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+
+		sb.WriteString(label.Name)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(label.Value))
+	}
+
+	sb.WriteString("}")
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+)
+
+var ConnectedClients = NewGauge("rr_connected_clients", "Clients connected to the game server")
+
+var BytesReceived = NewCounterVec("rr_received_bytes_total", "Uncompressed bytes of channel messages received from clients", "channel")
+var BytesSent = NewCounterVec("rr_sent_bytes_total", "Uncompressed bytes of messages sent to clients", "channel")
+
+var MessagesSent = NewCounterVec("rr_sent_messages_total", "Queued client entity messages sent to clients", "op_type")
+
+var UncompressedBytes = NewCounter("rr_zlib_uncompressed_bytes_total", "Bytes passed to zlib before sending")
+var CompressedBytes = NewCounter("rr_zlib_compressed_bytes_total", "Bytes zlib produced for sending")
+
+var OutboundDropped = NewCounter("rr_outbound_dropped_messages_total", "Messages dropped because the connection was closed or its outbound queue was full")
+var OutboundOverflowDisconnects = NewCounter("rr_outbound_overflow_disconnects_total", "Clients disconnected because their outbound queue was full")
+
+var LuaErrors = NewCounter("rr_lua_errors_total", "Errors returned by Lua scripts")
+
+var RecoveredPanics = NewCounterVec("rr_recovered_panics_total", "Panics recovered while handling a client or ticking a zone, a client panic disconnects that client", "source")
+
+var compressionRatio = newGaugeFunc("rr_zlib_compression_ratio", "Compressed bytes divided by uncompressed bytes since the server started", func() []Sample {
+	uncompressed := UncompressedBytes.Value()
+
+	if uncompressed == 0 {
+		return nil
+	}
+
+	return []Sample{{Value: float64(CompressedBytes.Value()) / float64(uncompressed)}}
+})
+
+var slowTicks = newGaugeFunc("rr_slow_ticks", "Ticks that took longer than the tick interval since the server started", func() []Sample {
+	return tickSamples(func(loop string, snapshot LoopSnapshot) []Sample {
+		return []Sample{{
+			Labels: []Label{{Name: "loop", Value: loop}},
+			Value:  float64(snapshot.SlowTicks),
+		}}
+	})
+})
+
+var tickDurations = newGaugeFunc("rr_tick_duration_milliseconds", "Tick duration over the recent ticks of each loop", func() []Sample {
+	return tickSamples(func(loop string, snapshot LoopSnapshot) []Sample {
+		return quantileSamples([]Label{{Name: "loop", Value: loop}}, snapshot.Total)
+	})
+})
+
+var tickPhaseDurations = newGaugeFunc("rr_tick_phase_duration_milliseconds", "Duration of each tick phase over the recent ticks of each loop", func() []Sample {
+	return tickSamples(func(loop string, snapshot LoopSnapshot) []Sample {
+		samples := make([]Sample, 0)
+
+		for phase, histogram := range snapshot.Phases {
+			samples = append(samples, quantileSamples([]Label{
+				{Name: "loop", Value: loop},
+				{Name: "phase", Value: phase},
+			}, histogram)...)
+		}
+
+		return samples
+	})
+})
+
+func tickSamples(f func(loop string, snapshot LoopSnapshot) []Sample) []Sample {
+	samples := make([]Sample, 0)
+
+	for loop, snapshot := range Ticks.Snapshot() {
+		samples = append(samples, f(loop, snapshot)...)
+	}
+
+	return samples
+}
+
+func quantileSamples(labels []Label, histogram HistogramSnapshot) []Sample {
+	if histogram.Count == 0 {
+		return nil
+	}
+
+	quantiles := []struct {
+		Name  string
+		Value float64
+	}{
+		{"0.5", histogram.P50Ms},
+		{"0.95", histogram.P95Ms},
+		{"0.99", histogram.P99Ms},
+		{"1", histogram.MaxMs},
+	}
+
+	samples := make([]Sample, len(quantiles))
+
+	for i, quantile := range quantiles {
+		sampleLabels := make([]Label, len(labels), len(labels)+1)
+		copy(sampleLabels, labels)
+
+		samples[i] = Sample{
+			Labels: append(sampleLabels, Label{Name: "quantile", Value: quantile.Name}),
+			Value:  quantile.Value,
+		}
+	}
+
+	return samples
+}
+
+// sortSamples orders the samples by their labels so the output doesn't jump around between requests
+func sortSamples(samples []Sample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return labelKey(samples[i].Labels) < labelKey(samples[j].Labels)
+	})
+}
+
+func labelKey(labels []Label) string {
+	key := ""
+
+	for _, label := range labels {
+		key += fmt.Sprintf("%s=%s,", label.Name, label.Value)
+	}
+
+	return key
+}
@@ -0,0 +1,231 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type metricType string
+
+const (
+	metricTypeCounter metricType = "counter"
+	metricTypeGauge   metricType = "gauge"
+)
+
+// Sample is a single value of a metric, labels are written in the order they are given
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+// collector writes the current samples of one metric when /metrics is requested
+type collector interface {
+	describe() (name string, help string, t metricType)
+	collect() []Sample
+}
+
+// CounterVec is a counter split by a single label, such as bytes per channel
+type CounterVec struct {
+	sync.Mutex
+
+	name   string
+	help   string
+	label  string
+	values map[string]uint64
+}
+
+func (c *CounterVec) Add(labelValue string, n uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.values[labelValue] += n
+}
+
+func (c *CounterVec) Inc(labelValue string) {
+	c.Add(labelValue, 1)
+}
+
+func (c *CounterVec) Total() uint64 {
+	c.Lock()
+	defer c.Unlock()
+
+	total := uint64(0)
+
+	for _, value := range c.values {
+		total += value
+	}
+
+	return total
+}
+
+func (c *CounterVec) describe() (string, string, metricType) {
+	return c.name, c.help, metricTypeCounter
+}
+
+func (c *CounterVec) collect() []Sample {
+	c.Lock()
+	defer c.Unlock()
+
+	samples := make([]Sample, 0, len(c.values))
+
+	for labelValue, value := range c.values {
+		samples = append(samples, Sample{
+			Labels: []Label{{Name: c.label, Value: labelValue}},
+			Value:  float64(value),
+		})
+	}
+
+	return samples
+}
+
+type Counter struct {
+	name  string
+	help  string
+	value uint64
+}
+
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.value, n)
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+func (c *Counter) describe() (string, string, metricType) {
+	return c.name, c.help, metricTypeCounter
+}
+
+func (c *Counter) collect() []Sample {
+	return []Sample{{Value: float64(c.Value())}}
+}
+
+type Gauge struct {
+	name  string
+	help  string
+	value int64
+}
+
+func (g *Gauge) Inc() {
+	atomic.AddInt64(&g.value, 1)
+}
+
+func (g *Gauge) Dec() {
+	atomic.AddInt64(&g.value, -1)
+}
+
+func (g *Gauge) describe() (string, string, metricType) {
+	return g.name, g.help, metricTypeGauge
+}
+
+func (g *Gauge) collect() []Sample {
+	return []Sample{{Value: float64(atomic.LoadInt64(&g.value))}}
+}
+
+// gaugeFunc reads its samples from somewhere else each time it is collected, used for values other packages already
+// keep such as the players in each zone
+type gaugeFunc struct {
+	name string
+	help string
+	f    func() []Sample
+}
+
+func (g *gaugeFunc) describe() (string, string, metricType) {
+	return g.name, g.help, metricTypeGauge
+}
+
+func (g *gaugeFunc) collect() []Sample {
+	return g.f()
+}
+
+type registry struct {
+	sync.RWMutex
+
+	collectors map[string]collector
+}
+
+func (r *registry) register(c collector) {
+	r.Lock()
+	defer r.Unlock()
+
+	name, _, _ := c.describe()
+	r.collectors[name] = c
+}
+
+func (r *registry) sorted() []collector {
+	r.RLock()
+	defer r.RUnlock()
+
+	names := make([]string, 0, len(r.collectors))
+
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	collectors := make([]collector, len(names))
+
+	for i, name := range names {
+		collectors[i] = r.collectors[name]
+	}
+
+	return collectors
+}
+
+var defaultRegistry = &registry{
+	collectors: make(map[string]collector),
+}
+
+func NewCounterVec(name string, help string, label string) *CounterVec {
+	c := &CounterVec{
+		name:   name,
+		help:   help,
+		label:  label,
+		values: make(map[string]uint64),
+	}
+
+	defaultRegistry.register(c)
+
+	return c
+}
+
+func NewCounter(name string, help string) *Counter {
+	c := &Counter{name: name, help: help}
+
+	defaultRegistry.register(c)
+
+	return c
+}
+
+func NewGauge(name string, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+
+	defaultRegistry.register(g)
+
+	return g
+}
+
+// RegisterGaugeFunc adds a gauge that calls f every time /metrics is requested, registering the same name again
+// replaces the previous function
+func RegisterGaugeFunc(name string, help string, f func() []Sample) {
+	newGaugeFunc(name, help, f)
+}
+
+func newGaugeFunc(name string, help string, f func() []Sample) *gaugeFunc {
+	g := &gaugeFunc{name: name, help: help, f: f}
+
+	defaultRegistry.register(g)
+
+	return g
+}
@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"RainbowRunner/internal/global"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Every loop is expected to finish a tick within the tick interval, anything slower delays the next tick
+const TickBudget = global.TickInterval * time.Millisecond
+
+// How many of the slowest details are kept for the slow tick log
+const maxTickDetails = 3
+
+type phaseTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+type tickDetail struct {
+	Name     string
+	Duration time.Duration
+}
+
+// TickTimer measures a single tick of a loop, each call to Phase ends the phase that started at the previous call.
+// A nil TickTimer does nothing so code that can run outside a loop doesn't need to check for one.
+type TickTimer struct {
+	loop    string
+	start   time.Time
+	last    time.Time
+	phases  []phaseTiming
+	details []tickDetail
+}
+
+func StartTick(loop string) *TickTimer {
+	now := time.Now()
+
+	return &TickTimer{
+		loop:  loop,
+		start: now,
+		last:  now,
+	}
+}
+
+// Phase records the time since the previous phase ended as the named phase
+func (t *TickTimer) Phase(name string) {
+	if t == nil {
+		return
+	}
+
+	now := time.Now()
+	t.phases = append(t.phases, phaseTiming{Name: name, Duration: now.Sub(t.last)})
+	t.last = now
+}
+
+// Detail records something that was timed inside a phase, such as a single entity's script, only the slowest are
+// kept to be logged if the tick is slow
+func (t *TickTimer) Detail(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+
+	if len(t.details) == maxTickDetails && d <= t.details[maxTickDetails-1].Duration {
+		return
+	}
+
+	if len(t.details) < maxTickDetails {
+		t.details = append(t.details, tickDetail{})
+	}
+
+	t.details[len(t.details)-1] = tickDetail{Name: name, Duration: d}
+
+	sort.SliceStable(t.details, func(i, j int) bool {
+		return t.details[i].Duration > t.details[j].Duration
+	})
+}
+
+// Finish records the tick and logs a breakdown if it went over TickBudget
+func (t *TickTimer) Finish() {
+	if t == nil {
+		return
+	}
+
+	total := time.Since(t.start)
+	stats := Ticks.loop(t.loop)
+
+	stats.observe(total, t.phases)
+
+	if total > TickBudget {
+		log.Warnf("slow tick in %s: %s", t.loop, t.breakdown(total))
+	}
+}
+
+func (t *TickTimer) breakdown(total time.Duration) string {
+	sb := strings.Builder{}
+
+	sb.WriteString(fmt.Sprintf("%.2fms (budget %.0fms)", milliseconds(total), milliseconds(TickBudget)))
+
+	for _, phase := range t.phases {
+		sb.WriteString(fmt.Sprintf(", %s %.2fms", phase.Name, milliseconds(phase.Duration)))
+	}
+
+	if len(t.details) > 0 {
+		sb.WriteString(", slowest:")
+
+		for _, detail := range t.details {
+			sb.WriteString(fmt.Sprintf(" %s %.2fms", detail.Name, milliseconds(detail.Duration)))
+		}
+	}
+
+	return sb.String()
+}
+
+type LoopSnapshot struct {
+	SlowTicks uint64                       `json:"slow_ticks"`
+	Total     HistogramSnapshot            `json:"total"`
+	Phases    map[string]HistogramSnapshot `json:"phases"`
+}
+
+type loopStats struct {
+	sync.Mutex
+
+	slowTicks uint64
+	total     *RollingHistogram
+	phases    map[string]*RollingHistogram
+}
+
+func (s *loopStats) observe(total time.Duration, phases []phaseTiming) {
+	s.Lock()
+	defer s.Unlock()
+
+	if total > TickBudget {
+		s.slowTicks++
+	}
+
+	s.total.Observe(total)
+
+	for _, phase := range phases {
+		histogram, ok := s.phases[phase.Name]
+
+		if !ok {
+			histogram = &RollingHistogram{}
+			s.phases[phase.Name] = histogram
+		}
+
+		histogram.Observe(phase.Duration)
+	}
+}
+
+func (s *loopStats) snapshot() LoopSnapshot {
+	s.Lock()
+	defer s.Unlock()
+
+	snapshot := LoopSnapshot{
+		SlowTicks: s.slowTicks,
+		Total:     s.total.Snapshot(),
+		Phases:    make(map[string]HistogramSnapshot, len(s.phases)),
+	}
+
+	for name, histogram := range s.phases {
+		snapshot.Phases[name] = histogram.Snapshot()
+	}
+
+	return snapshot
+}
+
+// TickStats holds the timings of every loop that has finished a tick, the game loop and each zone are separate loops
+type TickStats struct {
+	sync.RWMutex
+
+	loops map[string]*loopStats
+}
+
+func (s *TickStats) loop(name string) *loopStats {
+	s.RLock()
+	stats, ok := s.loops[name]
+	s.RUnlock()
+
+	if ok {
+		return stats
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if stats, ok = s.loops[name]; ok {
+		return stats
+	}
+
+	stats = &loopStats{
+		total:  &RollingHistogram{},
+		phases: make(map[string]*RollingHistogram),
+	}
+
+	s.loops[name] = stats
+
+	return stats
+}
+
+func (s *TickStats) Snapshot() map[string]LoopSnapshot {
+	s.RLock()
+	defer s.RUnlock()
+
+	snapshot := make(map[string]LoopSnapshot, len(s.loops))
+
+	for name, stats := range s.loops {
+		snapshot[name] = stats.snapshot()
+	}
+
+	return snapshot
+}
+
+var Ticks = &TickStats{
+	loops: make(map[string]*loopStats),
+}
@@ -0,0 +1,136 @@
+package names
+
+import (
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
+	"bufio"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidName = errors.New("names must be 3 to 16 letters")
+var ErrNameBlocked = errors.New("name contains a blocked word")
+var ErrNameTaken = errors.New("name is already taken")
+
+const MinLength = 3
+const MaxLength = 16
+
+var blockedWords []string
+
+// Names that have been given out since the server started, they cover characters that are still being created and
+// every name when there is no character store
+var reserved = make(map[string]bool)
+var reservedLock sync.Mutex
+
+// Init loads the blocked words, a missing file only means no names are blocked
+func Init() {
+	path := serverconfig.Config.Characters.BlockedNamesFile
+
+	if path == "" {
+		return
+	}
+
+	words, err := loadBlockedWords(path)
+
+	if err != nil {
+		log.Errorf("could not load blocked names from %s: %s", path, err.Error())
+		return
+	}
+
+	blockedWords = words
+
+	log.Infof("loaded %d blocked names", len(blockedWords))
+}
+
+// Validate checks the length, characters and blocked words without checking if the name is taken
+func Validate(name string) error {
+	if len(name) < MinLength || len(name) > MaxLength {
+		return ErrInvalidName
+	}
+
+	// Only letters so every name can be typed in chat commands
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return ErrInvalidName
+		}
+	}
+
+	lower := strings.ToLower(name)
+
+	for _, word := range blockedWords {
+		if strings.Contains(lower, word) {
+			return ErrNameBlocked
+		}
+	}
+
+	return nil
+}
+
+// Reserve validates the name and claims it so nobody else can create a character with it, the name must be released
+// if the character is not created
+func Reserve(name string) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+
+	key := strings.ToLower(name)
+
+	reservedLock.Lock()
+	defer reservedLock.Unlock()
+
+	if reserved[key] {
+		return ErrNameTaken
+	}
+
+	if storage.Store != nil {
+		_, err := storage.Store.GetCharacterByName(name)
+
+		if err == nil {
+			return ErrNameTaken
+		}
+
+		if !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+	}
+
+	reserved[key] = true
+
+	return nil
+}
+
+// Release makes the name available again, it is used when creating a character fails or a character is deleted
+func Release(name string) {
+	reservedLock.Lock()
+	defer reservedLock.Unlock()
+
+	delete(reserved, strings.ToLower(name))
+}
+
+func loadBlockedWords(path string) ([]string, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+
+		words = append(words, word)
+	}
+
+	return words, scanner.Err()
+}
@@ -0,0 +1,149 @@
+package names
+
+import (
+	"RainbowRunner/internal/storage"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useMemoryStore gives the test an empty store, no reserved names and the blocked words
+func useMemoryStore(t *testing.T, blocked ...string) *storage.MemoryStore {
+	store := storage.NewMemoryStore()
+
+	oldStore, oldBlocked := storage.Store, blockedWords
+	storage.Store, blockedWords = store, blocked
+
+	reservedLock.Lock()
+	reserved = make(map[string]bool)
+	reservedLock.Unlock()
+
+	t.Cleanup(func() {
+		storage.Store, blockedWords = oldStore, oldBlocked
+	})
+
+	return store
+}
+
+func TestValidate(t *testing.T) {
+	useMemoryStore(t, "admin", "gm")
+
+	tests := []struct {
+		name     string
+		expected error
+	}{
+		{"Ellie", nil},
+		{"abc", nil},
+		{"abcdefghijklmnop", nil},
+		{"ab", ErrInvalidName},
+		{"abcdefghijklmnopq", ErrInvalidName},
+		{"", ErrInvalidName},
+		{"Ellie1", ErrInvalidName},
+		{"El lie", ErrInvalidName},
+		{"Ellie_", ErrInvalidName},
+		{"Éllie", ErrInvalidName},
+		{"Admin", ErrNameBlocked},
+		{"TheAdmin", ErrNameBlocked},
+		{"BigGMan", ErrNameBlocked},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Validate(test.name); !errors.Is(err, test.expected) {
+				t.Errorf("Validate(%q) = %v, expected %v", test.name, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	tests := []struct {
+		name     string
+		stored   []string
+		reserved []string
+		reserve  string
+		expected error
+	}{
+		{name: "free", reserve: "Ellie"},
+		{name: "reserved", reserved: []string{"Ellie"}, reserve: "Ellie", expected: ErrNameTaken},
+		{name: "reserved other case", reserved: []string{"ellie"}, reserve: "ELLIE", expected: ErrNameTaken},
+		{name: "stored", stored: []string{"Ellie"}, reserve: "Ellie", expected: ErrNameTaken},
+		{name: "stored other case", stored: []string{"Ellie"}, reserve: "eLLie", expected: ErrNameTaken},
+		{name: "other name stored", stored: []string{"Ellie"}, reserve: "Ella"},
+		{name: "invalid", reserve: "E1", expected: ErrInvalidName},
+		{name: "blocked", reserve: "Admin", expected: ErrNameBlocked},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := useMemoryStore(t, "admin")
+
+			for _, name := range test.stored {
+				if err := store.CreateCharacter(&storage.Character{AccountName: "tester", Name: name}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, name := range test.reserved {
+				if err := Reserve(name); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := Reserve(test.reserve); !errors.Is(err, test.expected) {
+				t.Errorf("Reserve(%q) = %v, expected %v", test.reserve, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestReleaseAfterFailedCreate(t *testing.T) {
+	useMemoryStore(t)
+
+	if err := Reserve("Ellie"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Reserve("ellie"); !errors.Is(err, ErrNameTaken) {
+		t.Fatalf("expected the name to be taken, got %v", err)
+	}
+
+	// Creating the character failed so the name is given back
+	Release("ELLIE")
+
+	if err := Reserve("Ellie"); err != nil {
+		t.Errorf("expected the released name to be free, got %v", err)
+	}
+}
+
+func TestReserveWithoutStore(t *testing.T) {
+	useMemoryStore(t)
+	storage.Store = nil
+
+	if err := Reserve("Ellie"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Reserve("Ellie"); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("expected the name to be taken, got %v", err)
+	}
+}
+
+func TestLoadBlockedWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocked.txt")
+
+	if err := os.WriteFile(path, []byte("# comment\nAdmin\n\n  gm  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	words, err := loadBlockedWords(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(words) != 2 || words[0] != "admin" || words[1] != "gm" {
+		t.Errorf("got %q, expected [admin gm]", words)
+	}
+}
@@ -1,9 +1,15 @@
 package objects
 
 import (
+	"RainbowRunner/internal/database"
 	"RainbowRunner/internal/types/drobjecttypes"
 	byter "RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes"
+	"errors"
 	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"sync"
 )
 
 //go:generate go run ../../scripts/generatelua -type=Inventory -extends=GCObject
@@ -12,19 +18,186 @@ type Inventory struct {
 
 	itemID      int
 	InventoryID byte
-	Items       []IItem
+	Items       []*InventoryItem
+	// Size of the grid in cells, 0 when the inventory is not in the config and any position is allowed
+	Width  int
+	Height int
 }
 
-func (i *Inventory) AddItem(child drobjecttypes.DRObject) {
-	switch child.(type) {
-	case IItem:
-		child.(IItem).GetItem().Index = i.itemID
-	default:
-		panic(fmt.Sprintf("cannot add non-item to Inventory: %s", child.(IGCObject).GetGCObject().GCType))
+var ErrNoInventorySpace = errors.New("there is no space for the item")
+
+// Item sizes are looked up in the fixtures by GCType which is too slow to repeat for every cell
+var itemSizes = make(map[string]datatypes.Vector2)
+var itemSizesLock sync.RWMutex
+
+// AddItem keeps the item at the position it already has, the position is not checked
+func (i *Inventory) AddItem(child drobjecttypes.DRObject) error {
+	item, err := GetInventoryItem(child)
+
+	if err != nil {
+		return err
 	}
 
+	item.Index = i.itemID
+	item.Owner = i
+
 	i.itemID++
-	i.Items = append(i.Items, child.(IItem))
+	i.Items = append(i.Items, item)
+	i.AddChild(child)
+
+	return nil
+}
+
+// PlaceItem adds the item at the position if it is inside the grid and does not cover any other item
+func (i *Inventory) PlaceItem(child drobjecttypes.DRObject, x, y int) error {
+	item, err := GetInventoryItem(child)
+
+	if err != nil {
+		return err
+	}
+
+	covered, err := i.ItemsUnder(item, x, y)
+
+	if err != nil {
+		return err
+	}
+
+	if len(covered) > 0 {
+		return ErrNoInventorySpace
+	}
+
+	item.Position = datatypes.Vector2{X: int32(x), Y: int32(y)}
+
+	return i.AddItem(child)
+}
+
+// ItemsUnder returns the items the item would cover if it was placed at the position, the item itself is ignored so
+// it can be moved over where it already is
+func (i *Inventory) ItemsUnder(item *InventoryItem, x, y int) ([]*InventoryItem, error) {
+	width, height := itemSize(item)
+
+	if x < 0 || y < 0 || (i.Width > 0 && x+width > i.Width) || (i.Height > 0 && y+height > i.Height) {
+		return nil, errors.New(fmt.Sprintf("%dx%d item at %d, %d is outside the %dx%d inventory", width, height, x, y, i.Width, i.Height))
+	}
+
+	covered := make([]*InventoryItem, 0)
+
+	for _, other := range i.Items {
+		if other == item {
+			continue
+		}
+
+		position := other.Position
+		otherWidth, otherHeight := itemSize(other)
+
+		if x < int(position.X)+otherWidth && int(position.X) < x+width &&
+			y < int(position.Y)+otherHeight && int(position.Y) < y+height {
+			covered = append(covered, other)
+		}
+	}
+
+	return covered, nil
+}
+
+// FindFreeSpace finds the first position the item fits, searching each row from the top left. A side of an inventory
+// with an unknown size is searched up to just past the items already in it, so there is always space.
+func (i *Inventory) FindFreeSpace(item *InventoryItem) (x int, y int, ok bool) {
+	width, height := itemSize(item)
+	gridWidth, gridHeight := i.Width, i.Height
+
+	if gridWidth == 0 || gridHeight == 0 {
+		right, bottom := 0, 0
+
+		for _, other := range i.Items {
+			otherWidth, otherHeight := itemSize(other)
+
+			if int(other.Position.X)+otherWidth > right {
+				right = int(other.Position.X) + otherWidth
+			}
+
+			if int(other.Position.Y)+otherHeight > bottom {
+				bottom = int(other.Position.Y) + otherHeight
+			}
+		}
+
+		if gridWidth == 0 {
+			gridWidth = right + width
+		}
+
+		if gridHeight == 0 {
+			gridHeight = bottom + height
+		}
+	}
+
+	occupied := make([][]bool, gridHeight)
+
+	for row := range occupied {
+		occupied[row] = make([]bool, gridWidth)
+	}
+
+	for _, other := range i.Items {
+		position := other.Position
+		otherWidth, otherHeight := itemSize(other)
+
+		for row := int(position.Y); row < int(position.Y)+otherHeight && row < gridHeight; row++ {
+			for col := int(position.X); col < int(position.X)+otherWidth && col < gridWidth; col++ {
+				if row >= 0 && col >= 0 {
+					occupied[row][col] = true
+				}
+			}
+		}
+	}
+
+	for y = 0; y+height <= gridHeight; y++ {
+		for x = 0; x+width <= gridWidth; x++ {
+			if isAreaFree(occupied, x, y, width, height) {
+				return x, y, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+func isAreaFree(occupied [][]bool, x, y, width, height int) bool {
+	for row := y; row < y+height; row++ {
+		for col := x; col < x+width; col++ {
+			if occupied[row][col] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func itemSize(item *InventoryItem) (width int, height int) {
+	gcType := strings.ToLower(item.GetGCType())
+
+	itemSizesLock.RLock()
+	size, ok := itemSizes[gcType]
+	itemSizesLock.RUnlock()
+
+	if ok {
+		return int(size.X), int(size.Y)
+	}
+
+	width, height = 1, 1
+	drClass := database.FindItem(database.Armour, gcType)
+
+	if drClass == nil {
+		drClass = database.FindItem(database.Weapons, gcType)
+	}
+
+	if drClass != nil {
+		width, height = database.GetItemSize(drClass)
+	}
+
+	itemSizesLock.Lock()
+	itemSizes[gcType] = datatypes.Vector2{X: int32(width), Y: int32(height)}
+	itemSizesLock.Unlock()
+
+	return width, height
 }
 
 func (i *Inventory) WriteInit(body *byter.Byter) {
@@ -48,43 +221,61 @@ func (i *Inventory) WriteInitData(body *byter.Byter) {
 	}
 }
 
-func (i *Inventory) RemoveItemByIndex(index int) drobjecttypes.DRObject {
-	toRemove := -1
-	var toReturn drobjecttypes.DRObject = nil
+func (i *Inventory) GetItemByIndex(index int) *InventoryItem {
+	for _, item := range i.Items {
+		if item.Index == index {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// restoreItem puts back an item taken out with RemoveItemByIndex, it keeps the index and position the client knows
+func (i *Inventory) restoreItem(item *InventoryItem) {
+	item.Owner = i
 
-	for li, child := range i.Children() {
-		foundIndex := 0
+	i.Items = append(i.Items, item)
+	i.AddChild(item.Object)
+}
 
-		switch child.(type) {
-		case IEquipment:
-			foundIndex = child.(IEquipment).GetEquipment().Index
-		case *Item:
-			foundIndex = child.(*Item).Index
-		default:
-			panic(fmt.Sprintf("cannot remove non-item from Inventory: %s", child.(IGCObject).GetGCObject().GCType))
+// RemoveItemByIndex takes the item out of the inventory, it keeps its index and position until it is put somewhere else
+func (i *Inventory) RemoveItemByIndex(index int) *InventoryItem {
+	for li, item := range i.Items {
+		if item.Index != index {
+			continue
 		}
 
-		if foundIndex == index {
-			toRemove = li
-			toReturn = child
-		}
-	}
+		i.Items = append(i.Items[:li], i.Items[li+1:]...)
+		i.RemoveChild(item.Object)
+		item.Owner = nil
 
-	if toRemove > -1 {
-		i.GCChildren = append(i.GCChildren[:toRemove], i.GCChildren[toRemove+1:]...)
+		return item
 	}
 
-	return toReturn
+	return nil
 }
 
 func NewInventory(gcType string, index byte) *Inventory {
 	gcObject := NewGCObject("Inventory")
 	gcObject.GCType = gcType
 
+	return newInventory(gcObject, index)
+}
+
+func newInventory(gcObject *GCObject, index byte) *Inventory {
+	width, height, ok := database.GetInventorySize(gcObject.GCType)
+
+	if !ok {
+		log.Warnf("could not find the size of inventory %s, items can be placed anywhere", gcObject.GCType)
+	}
+
 	return &Inventory{
 		GCObject: gcObject,
 		// TODO figure out how to set inventory ID properly, client is always using 1
 		InventoryID: index,
+		Width:       width,
+		Height:      height,
 	}
 }
 
@@ -7,13 +7,14 @@ import (
 	"RainbowRunner/pkg/byter"
 	"errors"
 	"fmt"
+	log "github.com/sirupsen/logrus"
 )
 
 //go:generate go run ../../scripts/generatelua -type=EquipmentInventory -extends=Component
 type EquipmentInventory struct {
 	*Component
 	Avatar *Avatar
-	Slots  map[types.EquipmentSlot]IEquipment
+	Items  map[types.EquipmentSlot]*InventoryItem
 }
 
 func (n *EquipmentInventory) WriteInit(body *byter.Byter) {
@@ -29,19 +30,36 @@ func (n *EquipmentInventory) WriteInit(body *byter.Byter) {
 }
 
 func (n *EquipmentInventory) AddChild(child drobjecttypes.DRObject) {
-	if _, ok := child.(IEquipment); !ok {
-		panic(fmt.Sprintf("cannot add non-equipment item to EquipmentInventory: %s", child.(IGCObject).GetGCObject().GCType))
+	if _, err := n.AddItem(child); err != nil {
+		log.Errorf("could not equip '%s': %s", child.GetGCType(), err.Error())
 	}
+}
+
+// AddItem equips the item in the slot from its description
+func (n *EquipmentInventory) AddItem(child drobjecttypes.DRObject) (*InventoryItem, error) {
+	item, err := GetInventoryItem(child)
 
-	equip := child.(IEquipment).GetEquipment()
+	if err != nil {
+		return nil, err
+	}
+
+	equip := item.Equipment()
+
+	if equip == nil {
+		return nil, errors.New(fmt.Sprintf("'%s' is not equipment", child.GetGCType()))
+	}
 
-	if existing, ok := n.Slots[equip.Slot]; ok {
-		panic(fmt.Sprintf("cannot add equipment '%s' to slot '%s' because '%s' is already equipped", equip.GCType, equip.Slot.String(), existing.GetEquipment().GCType))
+	if existing, ok := n.Items[equip.Slot]; ok {
+		return nil, errors.New(fmt.Sprintf("'%s' is already equipped in slot '%s'", existing.GetGCType(), equip.Slot.String()))
 	}
 
-	equip.Index = int(equip.Slot)
+	item.Index = int(equip.Slot)
+	item.Owner = n
+	n.Items[equip.Slot] = item
 
 	n.GCObject.AddChild(child)
+
+	return item, nil
 }
 
 func (n *EquipmentInventory) ReadUpdate(reader *byter.Byter) error {
@@ -68,7 +86,6 @@ func (n *EquipmentInventory) ReadUpdate(reader *byter.Byter) error {
 
 func (n *EquipmentInventory) handleRemoveEquippedItem(reader *byter.Byter) error {
 	slot := reader.UInt32()
-	fmt.Printf("%d\n", slot)
 	CEWriter := NewClientEntityWriterWithByter()
 
 	unitContainer := n.Avatar.GetUnitContainer()
@@ -84,17 +101,19 @@ func (n *EquipmentInventory) handleRemoveEquippedItem(reader *byter.Byter) error
 	}
 
 	item := n.RemoveEquipmentBySlot(types.EquipmentSlot(slot))
-	err := n.addRemoveItemMessage(CEWriter, item.GetEquipment())
 
-	if err != nil {
-		return err
+	if item == nil {
+		return errors.New(fmt.Sprintf("nothing is equipped in slot %d", slot))
 	}
 
-	unitContainer.SetActiveItem(item.GetEquipment())
+	equipment := item.Equipment()
+	n.addRemoveItemMessage(CEWriter, equipment)
+
+	unitContainer.SetActiveItem(item)
 	unitContainer.WriteSetActiveItem(CEWriter.Body)
 
-	manipulators.RemoveChildByID(uint32(item.GetEquipment().ID()))
-	manipulators.WriteRemoveItem(CEWriter.Body, item.GetEquipment().Slot)
+	manipulators.RemoveChildByID(uint32(equipment.ID()))
+	manipulators.WriteRemoveItem(CEWriter.Body, equipment.Slot)
 
 	Players.GetPlayer(n.OwnerID()).MessageQueue.Enqueue(
 		message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeEquippedItemClickResponse,
@@ -112,7 +131,9 @@ func (n *EquipmentInventory) handleAddEquippedItem(reader *byter.Byter) error {
 		return errors.New(fmt.Sprintf("could not find unit container for player"))
 	}
 
-	if unitContainer.ActiveItem == nil {
+	item := unitContainer.ActiveItem
+
+	if item == nil {
 		return errors.New(fmt.Sprintf("cannot equip, no active item"))
 	}
 
@@ -122,26 +143,25 @@ func (n *EquipmentInventory) handleAddEquippedItem(reader *byter.Byter) error {
 		return errors.New(fmt.Sprintf("could not find unit manipulators for player"))
 	}
 
-	iEquipment, ok := unitContainer.ActiveItem.(IEquipment)
+	equipment := item.Equipment()
 
-	if !ok {
-		return errors.New(fmt.Sprintf("cannot equip, active item '%s' is not Equipment", unitContainer.ActiveItem.GetGCType()))
+	if equipment == nil {
+		unitContainer.returnActiveItem()
+		return errors.New(fmt.Sprintf("cannot equip, active item '%s' is not Equipment", item.GetGCType()))
 	}
 
-	equipment := iEquipment.GetEquipment()
-
 	if slot != uint32(equipment.Slot) {
+		unitContainer.returnActiveItem()
 		return errors.New(fmt.Sprintf("cannot equip item, wrong slot"))
 	}
 
-	n.AddChild(unitContainer.ActiveItem)
-
-	err := n.addAddItemMessage(CEWriter, equipment)
-
-	if err != nil {
+	if _, err := n.AddItem(item.Object); err != nil {
+		unitContainer.returnActiveItem()
 		return err
 	}
 
+	n.addAddItemMessage(CEWriter, equipment)
+
 	unitContainer.WriteClearActiveItem(CEWriter.Body)
 	unitContainer.SetActiveItem(nil)
 	//n.addSetActiveItemMessage(CEWriter, unitContainer, slot)
@@ -162,7 +182,7 @@ func (n *EquipmentInventory) addRemoveItemMessage(CEWriter *ClientEntityWriter,
 	CEWriter.Body.WriteByte(0x29)
 
 	if item == nil {
-		return errors.New(fmt.Sprintf("could not find item in slot %d", item.Slot))
+		return errors.New(fmt.Sprintf("cannot remove nil equipment"))
 	}
 
 	CEWriter.Body.WriteUInt32(uint32(item.Slot))
@@ -170,31 +190,18 @@ func (n *EquipmentInventory) addRemoveItemMessage(CEWriter *ClientEntityWriter,
 	return nil
 }
 
-func (n *EquipmentInventory) RemoveEquipmentBySlot(slot types.EquipmentSlot) IEquipment {
-	toRemove := -1
-	var toReturn IEquipment = nil
-
-	for li, child := range n.Children() {
-		foundIndex := types.EquipmentSlot(0)
+func (n *EquipmentInventory) RemoveEquipmentBySlot(slot types.EquipmentSlot) *InventoryItem {
+	item, ok := n.Items[slot]
 
-		switch child.(type) {
-		case IEquipment:
-			foundIndex = child.(IEquipment).GetEquipment().Slot
-		default:
-			panic(fmt.Sprintf("cannot add non-item to Inventory: %s", child.(IGCObject).GetGCObject().GCType))
-		}
-
-		if foundIndex == slot {
-			toRemove = li
-			toReturn = child.(IEquipment)
-		}
+	if !ok {
+		return nil
 	}
 
-	if toRemove > -1 {
-		n.GCChildren = append(n.GCChildren[:toRemove], n.GCChildren[toRemove+1:]...)
-	}
+	delete(n.Items, slot)
+	n.RemoveChild(item.Object)
+	item.Owner = nil
 
-	return toReturn
+	return item
 }
 
 func (n *EquipmentInventory) GetEquipment() []IEquipment {
@@ -210,17 +217,17 @@ func (n *EquipmentInventory) GetEquipment() []IEquipment {
 	return items
 }
 
-func (n *EquipmentInventory) addAddItemMessage(CEWriter *ClientEntityWriter, item IEquipment) error {
+func (n *EquipmentInventory) addAddItemMessage(CEWriter *ClientEntityWriter, item *Equipment) error {
 	CEWriter.BeginComponentUpdate(n)
 	// 0x28 Add
 	// 0x29 Remove
 	CEWriter.Body.WriteByte(0x28)
 
 	if item == nil {
-		return errors.New(fmt.Sprintf("could not find item in slot %d", item.GetEquipment().Slot))
+		return errors.New(fmt.Sprintf("cannot add nil equipment"))
 	}
 
-	item.GetEquipment().WriteInit(CEWriter.Body)
+	item.WriteInit(CEWriter.Body)
 	CEWriter.EndComponentUpdate(n)
 	return nil
 }
@@ -231,5 +238,6 @@ func NewEquipmentInventory(gcType string, avatar *Avatar) *EquipmentInventory {
 	return &EquipmentInventory{
 		Component: component,
 		Avatar:    avatar,
+		Items:     make(map[types.EquipmentSlot]*InventoryItem),
 	}
 }
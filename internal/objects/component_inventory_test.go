@@ -0,0 +1,237 @@
+package objects
+
+import (
+	"RainbowRunner/pkg/datatypes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// newTestItem makes an item with a fixed size so the tests don't need the item config
+func newTestItem(t *testing.T, width, height int) *Item {
+	gcType := fmt.Sprintf("test.item%dx%d", width, height)
+
+	itemSizesLock.Lock()
+	itemSizes[strings.ToLower(gcType)] = datatypes.Vector2{X: int32(width), Y: int32(height)}
+	itemSizesLock.Unlock()
+
+	return NewItem(gcType, ItemArmour)
+}
+
+func inventoryItem(t *testing.T, item *Item) *InventoryItem {
+	inventoryItem, err := GetInventoryItem(item)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return inventoryItem
+}
+
+func newTestInventory(width, height int) *Inventory {
+	return &Inventory{
+		GCObject:    NewGCObject("Inventory"),
+		InventoryID: 1,
+		Width:       width,
+		Height:      height,
+	}
+}
+
+// placed describes an item already in the inventory
+type placed struct {
+	width, height int
+	x, y          int
+}
+
+func fillInventory(t *testing.T, inventory *Inventory, items []placed) []*InventoryItem {
+	inventoryItems := make([]*InventoryItem, 0, len(items))
+
+	for _, p := range items {
+		item := newTestItem(t, p.width, p.height)
+
+		if err := inventory.PlaceItem(item, p.x, p.y); err != nil {
+			t.Fatalf("could not place %dx%d item at %d, %d: %s", p.width, p.height, p.x, p.y, err.Error())
+		}
+
+		inventoryItems = append(inventoryItems, item.InventoryItem)
+	}
+
+	return inventoryItems
+}
+
+func TestItemsUnder(t *testing.T) {
+	// A 2x2 item at 2, 2 and a 1x3 item at 5, 0 in a 6x4 inventory
+	existing := []placed{{2, 2, 2, 2}, {1, 3, 5, 0}}
+
+	tests := []struct {
+		name          string
+		width, height int
+		x, y          int
+		covered       []int
+		outside       bool
+	}{
+		{name: "empty space", width: 1, height: 1, x: 0, y: 0},
+		{name: "touching edges", width: 2, height: 2, x: 0, y: 2},
+		{name: "overlaps corner", width: 2, height: 2, x: 1, y: 1, covered: []int{0}},
+		{name: "inside", width: 1, height: 1, x: 3, y: 3, covered: []int{0}},
+		{name: "covers both", width: 3, height: 1, x: 3, y: 2, covered: []int{0, 1}},
+		{name: "bottom right corner", width: 1, height: 1, x: 5, y: 3},
+		{name: "past the right edge", width: 2, height: 1, x: 5, y: 3, outside: true},
+		{name: "past the bottom edge", width: 1, height: 2, x: 0, y: 3, outside: true},
+		{name: "negative", width: 1, height: 1, x: -1, y: 0, outside: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inventory := newTestInventory(6, 4)
+			items := fillInventory(t, inventory, existing)
+
+			covered, err := inventory.ItemsUnder(inventoryItem(t, newTestItem(t, test.width, test.height)), test.x, test.y)
+
+			if test.outside {
+				if err == nil {
+					t.Error("expected the position to be outside the inventory")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(covered) != len(test.covered) {
+				t.Fatalf("covers %d items, expected %d", len(covered), len(test.covered))
+			}
+
+			for i, index := range test.covered {
+				if covered[i] != items[index] {
+					t.Errorf("covered item %d is not item %d", i, index)
+				}
+			}
+		})
+	}
+}
+
+func TestItemsUnderIgnoresItself(t *testing.T) {
+	inventory := newTestInventory(4, 4)
+	items := fillInventory(t, inventory, []placed{{2, 2, 0, 0}})
+
+	covered, err := inventory.ItemsUnder(items[0], 1, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(covered) != 0 {
+		t.Errorf("the item covers %d items when moved over itself", len(covered))
+	}
+}
+
+func TestPlaceItem(t *testing.T) {
+	inventory := newTestInventory(4, 4)
+	fillInventory(t, inventory, []placed{{2, 2, 1, 1}})
+
+	if err := inventory.PlaceItem(newTestItem(t, 1, 1), 2, 2); !errors.Is(err, ErrNoInventorySpace) {
+		t.Errorf("expected ErrNoInventorySpace when overlapping, got %v", err)
+	}
+
+	if err := inventory.PlaceItem(newTestItem(t, 2, 1), 3, 0); err == nil {
+		t.Error("expected an error when placing past the edge")
+	}
+
+	item := newTestItem(t, 1, 1)
+
+	if err := inventory.PlaceItem(item, 3, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if item.InventoryItem.Position != (datatypes.Vector2{X: 3, Y: 3}) || item.InventoryItem.Owner != inventory {
+		t.Errorf("item is at %v in %v", item.InventoryItem.Position, item.InventoryItem.Owner)
+	}
+
+	if len(inventory.Items) != 2 || item.InventoryItem.Index == inventory.Items[0].Index {
+		t.Errorf("placed item has index %d, the inventory has %d items", item.InventoryItem.Index, len(inventory.Items))
+	}
+}
+
+func TestFindFreeSpace(t *testing.T) {
+	tests := []struct {
+		name          string
+		inventory     [2]int
+		existing      []placed
+		width, height int
+		x, y          int
+		full          bool
+	}{
+		{name: "empty", inventory: [2]int{4, 4}, width: 2, height: 2, x: 0, y: 0},
+		{name: "next to an item", inventory: [2]int{4, 4}, existing: []placed{{2, 2, 0, 0}}, width: 2, height: 2, x: 2, y: 0},
+		{name: "next row", inventory: [2]int{4, 4}, existing: []placed{{2, 2, 0, 0}, {1, 1, 2, 0}}, width: 2, height: 2, x: 2, y: 1},
+		{name: "gap too small", inventory: [2]int{3, 2}, existing: []placed{{1, 2, 1, 0}}, width: 2, height: 1, full: true},
+		{name: "exactly fills", inventory: [2]int{3, 2}, existing: []placed{{1, 2, 0, 0}}, width: 2, height: 2, x: 1, y: 0},
+		{name: "larger than the inventory", inventory: [2]int{2, 2}, width: 3, height: 1, full: true},
+		{name: "full", inventory: [2]int{2, 1}, existing: []placed{{1, 1, 0, 0}, {1, 1, 1, 0}}, width: 1, height: 1, full: true},
+
+		// Inventories that aren't in the config accept any position so there is always space
+		{name: "unknown size empty", width: 2, height: 3, x: 0, y: 0},
+		{name: "unknown size", existing: []placed{{2, 2, 0, 0}, {1, 1, 5, 5}}, width: 2, height: 2, x: 2, y: 0},
+		{name: "unknown size packed", existing: []placed{{1, 1, 0, 0}}, width: 1, height: 1, x: 1, y: 0},
+		{name: "unknown width", inventory: [2]int{0, 1}, existing: []placed{{1, 1, 0, 0}, {1, 1, 1, 0}}, width: 1, height: 1, x: 2, y: 0},
+		{name: "unknown height", inventory: [2]int{1, 0}, existing: []placed{{1, 1, 0, 0}}, width: 1, height: 1, x: 0, y: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inventory := newTestInventory(test.inventory[0], test.inventory[1])
+			fillInventory(t, inventory, test.existing)
+
+			item := newTestItem(t, test.width, test.height)
+			x, y, ok := inventory.FindFreeSpace(inventoryItem(t, item))
+
+			if ok == test.full {
+				t.Fatalf("found space %v, expected %v", ok, !test.full)
+			}
+
+			if test.full {
+				return
+			}
+
+			if x != test.x || y != test.y {
+				t.Errorf("found space at %d, %d, expected %d, %d", x, y, test.x, test.y)
+			}
+
+			// Whatever is found has to be accepted by PlaceItem
+			if err := inventory.PlaceItem(item, x, y); err != nil {
+				t.Errorf("could not place the item in the space that was found: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestRestoreItemKeepsIndex(t *testing.T) {
+	inventory := newTestInventory(4, 4)
+	items := fillInventory(t, inventory, []placed{{1, 1, 0, 0}, {2, 2, 2, 2}})
+
+	removed := inventory.RemoveItemByIndex(items[1].Index)
+	inventory.restoreItem(removed)
+
+	// The client was never told the item moved so it has to be found by the index it already has
+	if restored := inventory.GetItemByIndex(items[1].Index); restored != items[1] {
+		t.Fatal("the restored item is not at its old index")
+	}
+
+	if items[1].Position != (datatypes.Vector2{X: 2, Y: 2}) || items[1].Owner != inventory {
+		t.Errorf("restored item is at %v in %v", items[1].Position, items[1].Owner)
+	}
+
+	next := newTestItem(t, 1, 1)
+
+	if err := inventory.PlaceItem(next, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if next.InventoryItem.Index == items[0].Index || next.InventoryItem.Index == items[1].Index {
+		t.Errorf("new item reused index %d", next.InventoryItem.Index)
+	}
+}
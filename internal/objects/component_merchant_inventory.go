@@ -13,10 +13,7 @@ func NewMerchantInventory(gcType string, index byte) *MerchantInventory {
 	gcObject.GCType = gcType
 
 	return &MerchantInventory{
-		Inventory: &Inventory{
-			GCObject:    gcObject,
-			InventoryID: index,
-		},
+		Inventory: newInventory(gcObject, index),
 	}
 }
 
@@ -1,7 +1,9 @@
 package objects
 
 import (
+	"RainbowRunner/internal/types/configtypes"
 	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes"
 )
 
 //go:generate go run ../../scripts/generatelua -type=MonsterBehavior2 -extends=UnitBehavior
@@ -22,6 +24,36 @@ type MonsterBehavior2 struct {
 	MonsterBehavior2Unk6 uint16
 	MonsterBehavior2Unk7 uint16
 	MonsterBehavior2Unk8 uint16
+
+	// AI configuration, see monster_ai.go
+	AttackRange    float32
+	AggroRange     float32
+	LeashRange     float32
+	WanderRange    uint16
+	CreatureFamily string
+
+	state            MonsterAIState
+	stateTicks       uint
+	idleTicks        uint
+	nextAttackTick   uint
+	target           *monsterTarget
+	home             datatypes.Vector2Float32
+	homeSet          bool
+	chaseDestination datatypes.Vector2Float32
+	hasFled          bool
+}
+
+func (n *MonsterBehavior2) Tick() {
+	n.tickAI()
+	n.UnitBehavior.Tick()
+}
+
+// Configure sets up the AI from the NPC's entity description
+func (n *MonsterBehavior2) Configure(desc *configtypes.EntityDesc) {
+	n.Speed = int(desc.Speed)
+	n.TurnRate = desc.TurnRate
+	n.AttackRange = float32(desc.AttackRange)
+	n.CreatureFamily = desc.CreatureFamily
 }
 
 func (n *MonsterBehavior2) WriteInit(b *byter.Byter) {
@@ -86,7 +118,14 @@ func NewMonsterBehavior2(gctype string) *MonsterBehavior2 {
 	unitBehavior.UnitMoverFlags = 0x01 | 0x04 | 0x80
 	//unitBehavior.UnitMoverFlags = 0x00
 
-	return &MonsterBehavior2{
+	behavior := &MonsterBehavior2{
 		UnitBehavior: unitBehavior,
+		AggroRange:   monsterDefaultAggroRange,
+		LeashRange:   monsterDefaultLeashRange,
+		WanderRange:  monsterDefaultWanderRange,
 	}
+
+	behavior.enterIdle()
+
+	return behavior
 }
@@ -7,6 +7,7 @@ import (
 	"RainbowRunner/internal/global"
 	"RainbowRunner/internal/gosucks"
 	"RainbowRunner/internal/message"
+	"RainbowRunner/internal/pathfinding"
 	"RainbowRunner/internal/serverconfig"
 	"RainbowRunner/pkg/byter"
 	"RainbowRunner/pkg/datatypes"
@@ -67,23 +68,58 @@ type UnitBehavior struct {
 	// This is a hacky workaround to get differing behaviour for writeInit when target is non-owning player
 	IsOwnedByCurrentPlayer bool
 
-	//TODO add movement path
+	// The tick the unit stops being stunned, stunned units cannot move or attack
+	StunnedUntil uint
+
+	// Waypoints to targetPosition, these are filled in once the zone's path manager finishes pathRequest
+	path        []datatypes.Vector2Float32
+	pathRequest *pathfinding.PathRequest
 }
 
 func (u *UnitBehavior) Tick() {
-	if u.IsMoving {
+	if u.IsMoving && !u.IsStunned() {
 		//TODO handle turning
 		//turning is currently not 100% possible as the client is not recognising the unit behavior rotation
 		//and is instead always defaulting to 0
 
-		distanceToTarget := u.Position.ToVector2Float32().Distance(u.targetPosition)
+		if u.pathRequest != nil {
+			// Wait where we are until the path manager gets to the request
+			if !u.pathRequest.Done {
+				return
+			}
+
+			request := u.pathRequest
+			u.pathRequest = nil
+
+			if !request.Found {
+				log.Warningf("%s could not find a path to %s", u.String(), request.To.String())
+				u.IsMoving = false
+				return
+			}
+
+			u.path = request.Path
+		}
+
+		waypoint := u.targetPosition
+
+		if len(u.path) > 0 {
+			waypoint = u.path[0]
+		}
+
+		distanceToTarget := u.Position.ToVector2Float32().Distance(waypoint)
 		if distanceToTarget < 0.1 {
+			if len(u.path) > 1 {
+				u.path = u.path[1:]
+				return
+			}
+
 			//log.Info("reached target position")
+			u.path = nil
 			u.IsMoving = false
 			return
 		}
 
-		dirToTarget := u.targetPosition.Sub(u.Position.ToVector2Float32()).Normalize()
+		dirToTarget := waypoint.Sub(u.Position.ToVector2Float32()).Normalize()
 		moveDistance := math.Min(distanceToTarget, float64(u.Speed)*global.GetDeltaTime())
 
 		toMove := dirToTarget.Mul(float32(moveDistance)).ToVector3Float32()
@@ -216,7 +252,7 @@ func (u *UnitBehavior) WriteMoveUpdate(b *byter.Byter) {
 
 		if serverconfig.Config.Logging.LogMoves {
 			fmt.Printf(
-				"Sending move rotation 0x%x(%.2fdeg) (%d, %d) Hex (%x, %x)\n",
+				"Sending move rotation 0x%x(%.2fdeg) (%.2f, %.2f) Hex (%x, %x)\n",
 				int32(position.Rotation*256), position.Rotation, position.Position.X, position.Position.Y, position.Position.X, position.Position.Y,
 			)
 		}
@@ -375,7 +411,6 @@ func (u *UnitBehavior) ReadUpdate(reader *byter.Byter) error {
 	case 0x01: // Execute Action?
 		return u.handleExecuteAction(reader)
 		//u.handleClientBlockMovement(reader)
-		//u.handleClientAttack(reader)
 	case 0x65:
 		u.handleClientMove(u.EntityProperties.Conn, reader)
 	// Potentially requesting current position because starting a new path
@@ -428,29 +463,10 @@ func (u *UnitBehavior) sendWarpTo(posX, posY, posZ float32) {
 	writer.EndStream()
 
 	if u.RREntityProperties().Zone != nil {
-		u.RREntityProperties().Zone.SendToAll(writer.Body)
+		u.RREntityProperties().Zone.SendToNearby(u, writer.Body)
 	}
 }
 
-func (u *UnitBehavior) handleClientAttack(reader *byter.Byter) {
-	reader.DumpRemaining()
-
-	writer := NewClientEntityWriterWithByter()
-
-	writer.BeginStream()
-	writer.BeginComponentUpdate(u)
-
-	//00000000  07 34 b4 00 01 02 51 01  0a 76 b9 01 00 3d 4e ff  |.4....Q..v...=N.|
-	//00000010  ff ec 31 00 00                                    |..1..|
-
-	//00000000  02 51 01 0a 0b 90 01 00  88 4d ff ff ec 31 00 00  |.Q.......M...1..|
-
-	writer.EndComponentUpdate(u)
-	writer.EndStream()
-
-	connections.WriteCompressedASimple(u.RREntityProperties().Conn, writer.Body)
-}
-
 func (u *UnitBehavior) WriteWarp(writer *ClientEntityWriter) {
 	writer.BeginComponentUpdate(u)
 
@@ -486,6 +502,8 @@ func (u *UnitBehavior) handleExecuteAction(reader *byter.Byter) error {
 		err = u.handleActionUsePosition(reader, responseId, sessionID)
 	case actions2.BehaviourActionActivate:
 		err = u.handleExecuteActivate(reader, responseId, sessionID)
+	case actions2.BehaviourActionUseTarget:
+		err = u.handleActionUseTarget(reader, responseId, sessionID)
 	}
 
 	u.SessionID++
@@ -553,6 +571,57 @@ func (u *UnitBehavior) handleActionUsePosition(reader *byter.Byter, id byte, ses
 	return nil
 }
 
+// handleActionUseTarget is sent when the player clicks on a unit to attack it
+func (u *UnitBehavior) handleActionUseTarget(reader *byter.Byter, responseID byte, sessionID byte) error {
+	actionID := reader.Byte()
+	targetID := reader.UInt16()
+
+	if u.EntityProperties.Zone == nil {
+		return errors.New(fmt.Sprintf("unit %d used action %d on %d outside of a zone", u.ID(), actionID, targetID))
+	}
+
+	player := Players.GetPlayer(u.OwnerID())
+
+	if player == nil {
+		return errors.New(fmt.Sprintf("could not find player %d that owns unit %d", u.OwnerID(), u.ID()))
+	}
+
+	targetEntity := u.EntityProperties.Zone.FindEntityByID(targetID)
+
+	if targetEntity == nil {
+		return errors.New(fmt.Sprintf("could not find target entity with ID %d", targetID))
+	}
+
+	target, ok := targetEntity.(IUnit)
+
+	if !ok {
+		log.Errorf("tried to attack non-unit: %s", targetEntity.String())
+		return nil
+	}
+
+	CEWriter := NewClientEntityWriterWithByter()
+
+	CEWriter.BeginComponentUpdate(u)
+	CEWriter.CreateActionResponse(actions2.BehaviourActionUseTarget, responseID, sessionID)
+
+	useTargetAction := actions2.ActionUseTarget{
+		ActionID: actionID,
+		TargetID: targetID,
+	}
+
+	useTargetAction.Init(CEWriter.Body)
+
+	CEWriter.WriteSynch(u)
+
+	player.MessageQueue.Enqueue(
+		message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeBehaviourAction,
+	)
+
+	u.Attack(target)
+
+	return nil
+}
+
 func (u *UnitBehavior) Spawn(pos datatypes.Vector3Float32) {
 	action := actions2.ActionSpawn{
 		Pos: pos,
@@ -571,13 +640,31 @@ func (u *UnitBehavior) MoveTo(pos datatypes.Vector2Float32) {
 		PosY: pos.Y,
 	}
 
-	u.targetPosition = datatypes.Vector2Float32{X: pos.X, Y: pos.Y}
-	u.IsMoving = true
+	u.pathTo(pos)
 	//u.Position = datatypes.Vector3Float32{X: pos.X, Y: pos.Y, Z: u.Position.Z}
 
 	u.ExecuteAction(action)
 }
 
+// pathTo moves the unit along a path to pos without telling the client, the client runs its own path manager for
+// MoveTo so the server only has to keep track of where the unit is. Zones without a path map move in a straight line
+func (u *UnitBehavior) pathTo(pos datatypes.Vector2Float32) {
+	zone := u.RREntityProperties().Zone
+
+	if u.pathRequest != nil && zone != nil && zone.PathManager != nil {
+		zone.PathManager.Cancel(u.pathRequest)
+	}
+
+	u.targetPosition = pos
+	u.path = nil
+	u.pathRequest = nil
+	u.IsMoving = true
+
+	if zone != nil && zone.PathManager != nil {
+		u.pathRequest = zone.PathManager.Request(u.Position.ToVector2Float32(), pos)
+	}
+}
+
 func (u *UnitBehavior) MoveToEntity(g IWorldEntity) {
 	targetPosition := datatypes.Vector2Float32{}
 	nativeType := g.GetWorldEntity().GetChildByGCNativeType("UnitBehavior")
@@ -606,6 +693,26 @@ func (u *UnitBehavior) MoveToEntity(g IWorldEntity) {
 	u.MoveTo(targetPosition)
 }
 
+func (u *UnitBehavior) Attack(target IUnit) {
+	if u.IsStunned() {
+		return
+	}
+
+	events.Emit(AttackEvent{
+		UnitBehavior: u,
+		Target:       target,
+	})
+}
+
+func (u *UnitBehavior) Stun(ticks uint) {
+	u.StunnedUntil = global.GetTick() + ticks
+	u.IsMoving = false
+}
+
+func (u *UnitBehavior) IsStunned() bool {
+	return global.GetTick() < u.StunnedUntil
+}
+
 // ExecuteAction should never be called directly as it is only used to emit actions
 // you should call the direct action methods on this behaviour instead
 // e.g. MoveTo, Attack, etc
@@ -5,7 +5,6 @@ import (
 	"RainbowRunner/internal/types"
 	"RainbowRunner/internal/types/drobjecttypes"
 	byter "RainbowRunner/pkg/byter"
-	"RainbowRunner/pkg/datatypes"
 	"errors"
 	"fmt"
 )
@@ -15,8 +14,9 @@ type UnitContainer struct {
 	*Component
 
 	Manipulator drobjecttypes.DRObject
-	ActiveItem  drobjecttypes.DRObject
-	Avatar      *Avatar
+	// The item on the cursor
+	ActiveItem *InventoryItem
+	Avatar     *Avatar
 }
 
 func (u *UnitContainer) WriteInit(body *byter.Byter) {
@@ -60,7 +60,7 @@ func (u *UnitContainer) ReadUpdate(body *byter.Byter) error {
 
 		avatarUnitBehaviour := u.Avatar.GetUnitBehaviour()
 
-		itemObject := NewItemObject("itemobject", item)
+		itemObject := NewItemObject("itemobject", item.Object)
 		itemObject.EntityProperties.SetOwner(u.OwnerID())
 		itemObject.WorldPosition = avatarUnitBehaviour.Position
 		zone.SpawnEntity(types.UInt16(u.OwnerID()), itemObject)
@@ -97,28 +97,63 @@ func (u *UnitContainer) ReadUpdate(body *byter.Byter) error {
 
 func (u *UnitContainer) handlePlaceItemInInventory(body *byter.Byter) error {
 	inventoryID := body.Byte()
+	x := int(body.Byte())
+	y := int(body.Byte())
+
+	item := u.ActiveItem
+
+	if item == nil {
+		return errors.New(fmt.Sprintf("character does not have an active item"))
+	}
+
 	inventory := u.GetInventoryByID(inventoryID)
 
 	if inventory == nil {
-		return errors.New(fmt.Sprintf("character does not have an inventory"))
+		u.returnActiveItem()
+		return errors.New(fmt.Sprintf("character does not have inventory %d", inventoryID))
+	}
+
+	covered, err := inventory.ItemsUnder(item, x, y)
+
+	if err != nil {
+		u.returnActiveItem()
+		return err
+	}
+
+	// Placing an item on top of a single item swaps them, the covered item goes on the cursor
+	if len(covered) > 1 {
+		u.returnActiveItem()
+		return errors.New(fmt.Sprintf("cannot place item at %d, %d, it covers %d items", x, y, len(covered)))
 	}
 
 	CEWriter := NewClientEntityWriterWithByter()
 
-	item := u.ActiveItem
+	var swapped *InventoryItem
 
-	if item == nil {
-		return errors.New(fmt.Sprintf("character does not have an active item"))
+	if len(covered) == 1 {
+		swapped = inventory.RemoveItemByIndex(covered[0].Index)
+		u.WriteRemoveItem(CEWriter.Body, uint32(swapped.Index))
 	}
 
-	u.SetActiveItem(nil)
-	u.WriteClearActiveItem(CEWriter.Body)
+	if err = inventory.PlaceItem(item.Object, x, y); err != nil {
+		// Nothing else is under the item once the covered item is removed so this should not happen
+		if swapped != nil {
+			inventory.restoreItem(swapped)
+		}
+
+		u.returnActiveItem()
+		return err
+	}
 
-	x := body.Byte()
-	y := body.Byte()
+	u.WriteAddItem(CEWriter.Body, item)
 
-	inventory.AddItem(item)
-	u.WriteAddItem(CEWriter.Body, item, inventory, x, y)
+	if swapped != nil {
+		u.SetActiveItem(swapped)
+		u.WriteSetActiveItem(CEWriter.Body)
+	} else {
+		u.SetActiveItem(nil)
+		u.WriteClearActiveItem(CEWriter.Body)
+	}
 
 	Players.GetPlayer(uint16(u.OwnerID())).MessageQueue.Enqueue(
 		message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeInventoryItemClickResponse,
@@ -135,6 +170,11 @@ func (u *UnitContainer) handlePickupItemFromInventory(body *byter.Byter) error {
 		return errors.New(fmt.Sprintf("character does not have an inventory"))
 	}
 
+	if u.ActiveItem != nil {
+		u.returnActiveItem()
+		return errors.New(fmt.Sprintf("cannot pick up item %d while holding '%s'", index, u.ActiveItem.GetGCType()))
+	}
+
 	item := inventoryCast.RemoveItemByIndex(int(index))
 
 	if item == nil {
@@ -154,13 +194,60 @@ func (u *UnitContainer) handlePickupItemFromInventory(body *byter.Byter) error {
 	return nil
 }
 
+// AutoPlaceItem puts the item in the first space in the backpack that fits it and tells the client
+func (u *UnitContainer) AutoPlaceItem(item drobjecttypes.DRObject) error {
+	inventory, ok := u.GetChildByGCType("avatar.base.inventory").(*Inventory)
+
+	if !ok {
+		return errors.New(fmt.Sprintf("character does not have an inventory"))
+	}
+
+	inventoryItem, err := GetInventoryItem(item)
+
+	if err != nil {
+		return err
+	}
+
+	x, y, ok := inventory.FindFreeSpace(inventoryItem)
+
+	if !ok {
+		return ErrNoInventorySpace
+	}
+
+	if err = inventory.PlaceItem(item, x, y); err != nil {
+		return err
+	}
+
+	CEWriter := NewClientEntityWriterWithByter()
+	u.WriteAddItem(CEWriter.Body, inventoryItem)
+
+	Players.GetPlayer(uint16(u.OwnerID())).MessageQueue.Enqueue(
+		message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeInventoryItemClickResponse,
+	)
+	return nil
+}
+
+// returnActiveItem puts the active item back on the cursor after a rejected move, the client has already let go of it
+func (u *UnitContainer) returnActiveItem() {
+	if u.ActiveItem == nil {
+		return
+	}
+
+	CEWriter := NewClientEntityWriterWithByter()
+	u.WriteSetActiveItem(CEWriter.Body)
+
+	Players.GetPlayer(uint16(u.OwnerID())).MessageQueue.Enqueue(
+		message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeInventoryItemClickResponse,
+	)
+}
+
 func (u UnitContainer) WriteFullGCObject(byter *byter.Byter) {
 	u.GCObject.WriteFullGCObject(byter)
 
 	u.Manipulator.WriteFullGCObject(byter)
 }
 
-func (u *UnitContainer) SetActiveItem(item drobjecttypes.DRObject) {
+func (u *UnitContainer) SetActiveItem(item *InventoryItem) {
 	u.ActiveItem = item
 }
 
@@ -171,7 +258,7 @@ func (u *UnitContainer) WriteSetActiveItem(body *byter.Byter) {
 	// 0x28 set active item
 	CEWriter.Body.WriteByte(0x28)
 
-	u.ActiveItem.WriteInit(CEWriter.Body)
+	u.ActiveItem.Object.WriteInit(CEWriter.Body)
 
 	CEWriter.EndComponentUpdate(u)
 }
@@ -200,7 +287,8 @@ func (u *UnitContainer) WriteRemoveItem(body *byter.Byter, index uint32) {
 	CEWriter.EndComponentUpdate(u)
 }
 
-func (u *UnitContainer) WriteAddItem(body *byter.Byter, item drobjecttypes.DRObject, inventory *Inventory, x, y byte) {
+// WriteAddItem tells the client the item has been put in the inventory that owns it at its current position
+func (u *UnitContainer) WriteAddItem(body *byter.Byter, item *InventoryItem) {
 	CEWriter := NewClientEntityWriter(body)
 	CEWriter.BeginComponentUpdate(u)
 
@@ -210,16 +298,9 @@ func (u *UnitContainer) WriteAddItem(body *byter.Byter, item drobjecttypes.DRObj
 	// 0x1E Add Item
 	CEWriter.Body.WriteByte(0x1E)
 	// Inventory ID (not the same as GCObject ID)
-	CEWriter.Body.WriteByte(inventory.InventoryID)
-
-	if drItem, ok := item.(DRItem); ok {
-		drItem.SetInventoryPosition(datatypes.Vector2{
-			X: int32(x),
-			Y: int32(y),
-		})
-	}
+	CEWriter.Body.WriteByte(item.Owner.(IInventory).GetInventory().InventoryID)
 
-	item.WriteInit(CEWriter.Body)
+	item.Object.WriteInit(CEWriter.Body)
 	CEWriter.EndComponentUpdate(u)
 }
 
@@ -168,7 +168,7 @@ func (p *Avatar) SendMoveTo(unk uint8, compID uint16, posX, posY float32) {
 	connections.WriteCompressedA(p.RREntityProperties().Conn, 0x01, 0x0f, body)
 
 	if serverconfig.Config.Logging.LogMoves {
-		fmt.Printf("Send MoveTo %x (%d, %d) (%x, %x)\n", unk, posX, posY, posX, posY)
+		fmt.Printf("Send MoveTo %x (%.2f, %.2f) (%x, %x)\n", unk, posX, posY, posX, posY)
 	}
 }
 
@@ -180,6 +180,10 @@ func (p *Avatar) GetManipulators() *Manipulators {
 	return p.GetChildByGCNativeType("Manipulators").(*Manipulators)
 }
 
+func (p *Avatar) GetEquipmentInventory() *EquipmentInventory {
+	return p.GetChildByGCType("avatar.base.Equipment").(*EquipmentInventory)
+}
+
 func (p *Avatar) GetUnitBehaviour() *UnitBehavior {
 	unitBehaviour := p.GetChildByGCNativeType("UnitBehavior")
 	return unitBehaviour.(*UnitBehavior)
@@ -3,6 +3,7 @@ package objects
 import (
 	"RainbowRunner/internal/types/drobjecttypes"
 	"RainbowRunner/pkg/byter"
+	log "github.com/sirupsen/logrus"
 )
 
 //go:generate go run ../../scripts/generatelua -type=ItemObject -extends=WorldEntity
@@ -34,8 +35,23 @@ func (n *ItemObject) WriteInit(b *byter.Byter) {
 	n.Item.WriteInit(b)
 }
 
+// Activate picks the item up into the first space in the backpack, it stays on the ground if there is no space
+func (n *ItemObject) Activate(player *RRPlayer, u *UnitBehavior, id byte, sessionID byte) {
+	n.WorldEntity.Activate(player, u, id, sessionID)
+
+	avatar := player.CurrentCharacter.GetAvatar()
+
+	if err := avatar.GetUnitContainer().AutoPlaceItem(n.Item); err != nil {
+		log.Infof("%s could not pick up %s: %s", player.CurrentCharacter.Name, n.Item.GetGCType(), err.Error())
+		return
+	}
+
+	n.EntityProperties.Zone.Despawn(n)
+}
+
 func NewItemObject(gcType string, item drobjecttypes.DRObject) *ItemObject {
 	worldEntity := NewWorldEntity(gcType)
+	worldEntity.CanBeActivated = true
 
 	return &ItemObject{
 		WorldEntity: worldEntity,
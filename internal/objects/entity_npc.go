@@ -4,6 +4,7 @@ import (
 	"RainbowRunner/internal/types/configtypes"
 	"RainbowRunner/pkg/byter"
 	"RainbowRunner/pkg/datatypes"
+	"RainbowRunner/pkg/datatypes/drfloat"
 	"strings"
 )
 
@@ -12,6 +13,13 @@ type NPC struct {
 	*StockUnit
 
 	Level int32
+
+	// Combat stats from the entity description, zero values fall back to the level based defaults
+	AttackRating   float32
+	DefenseRating  float32
+	CriticalChance float32
+	DamageMod      float32
+	StunResist     int
 }
 
 func (n *NPC) WriteInit(b *byter.Byter) {
@@ -52,6 +60,20 @@ func NewNPCFromConfig(config *configtypes.NPCConfig) *NPC {
 
 	npc.WorldEntity.CanBeActivated = config.CanBeActivated
 
+	npc.AttackRating = config.Desc.AttackRating
+	npc.DefenseRating = config.Desc.DefenseRating
+	npc.CriticalChance = config.Desc.CriticalChance
+	npc.DamageMod = config.Desc.DamageMod
+	npc.StunResist = config.Desc.StunResist
+
+	if config.Desc.MaxHealth > 0 {
+		npc.MaxHP = drfloat.FromFloat32(config.Desc.MaxHealth)
+	} else if config.HitPoints > 0 {
+		npc.MaxHP = config.HitPoints
+	}
+
+	npc.HP = npc.MaxHP
+
 	behaviorType := "npc.Base.Behavior"
 
 	if strings.ToLower(config.Behaviour.Type) != "monsterbehavior2" {
@@ -60,8 +82,7 @@ func NewNPCFromConfig(config *configtypes.NPCConfig) *NPC {
 
 	behavior2 := NewMonsterBehavior2(behaviorType)
 
-	behavior2.Speed = int(config.Desc.Speed)
-	behavior2.TurnRate = config.Desc.TurnRate
+	behavior2.Configure(config.Desc)
 
 	npc.AddChild(behavior2)
 
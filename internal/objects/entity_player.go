@@ -24,6 +24,10 @@ type Player struct {
 	CurrentHP uint32 // This is probably a DRFloat
 	Spawned   bool
 	Zone      *Zone
+
+	CharacterID uint32
+
+	savedLocation *savedLocation
 }
 
 func (p *Player) GetRRPlayer() *RRPlayer {
@@ -181,7 +185,38 @@ func (p *Player) ChangeZone(zoneName string) {
 		p.LeaveZone()
 	}
 
-	p.JoinZone(tZone)
+	p.EnterZone(tZone, func() {
+		if p.CharacterID != 0 {
+			if err := SavePlayer(p); err != nil {
+				log.Errorf("failed to save character %s: %s", p.Name, err.Error())
+			}
+		}
+	})
+}
+
+// EnterZone hands the player over to the zone and joins it on the zone's goroutine, then is called there once the
+// player has joined
+func (p *Player) EnterZone(tZone *Zone, then func()) {
+	rrPlayer := Players.GetPlayer(p.OwnerID())
+
+	if rrPlayer != nil {
+		rrPlayer.SetOwnerZone(tZone)
+	}
+
+	join := func() {
+		p.JoinZone(tZone)
+
+		if then != nil {
+			then()
+		}
+	}
+
+	if !tZone.IsRunning() {
+		join()
+		return
+	}
+
+	tZone.Enqueue(join)
 }
 
 func (p *Player) JoinZone(tZone *Zone) {
@@ -221,19 +256,7 @@ func (p *Player) OnZoneJoin() {
 	rrplayer := Players.GetPlayer(p.OwnerID())
 
 	p.Spawned = true
-	entities := p.Zone.Entities()
-
-	for _, entity := range entities {
-		if int(entity.OwnerID()) == rrplayer.Conn.GetID() {
-			continue
-		}
-
-		CEWriter := NewClientEntityWriterWithByter()
-
-		WriteCreateExistingEntity(entity, CEWriter)
-
-		rrplayer.MessageQueue.Enqueue(message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeCreateEntity)
-	}
+	p.Zone.sendNearbyEntities(rrplayer)
 }
 
 func (p *Player) OnZoneLeave() {
@@ -242,7 +265,8 @@ func (p *Player) OnZoneLeave() {
 
 func (p *Player) LeaveZone() {
 	p.Spawned = false
-	p.Zone.RemovePlayer(int(p.ID()))
+	// Players are kept by their connection ID
+	p.Zone.RemovePlayer(int(p.OwnerID()))
 
 	rrplayer := Players.GetPlayer(uint16(p.OwnerID()))
 	rrplayer.MessageQueue.Clear(message.QueueTypeClientEntity)
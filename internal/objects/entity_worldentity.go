@@ -71,8 +71,9 @@ const (
 type WorldEntity struct {
 	*Entity
 
-	HP drfloat.DRFloat
-	MP drfloat.DRFloat
+	HP    drfloat.DRFloat
+	MaxHP drfloat.DRFloat
+	MP    drfloat.DRFloat
 
 	CollisionRadius int
 
@@ -145,6 +146,15 @@ func (g *WorldEntity) Tick() {
 	}
 }
 
+// ScriptName is the name of the entity's Lua script, empty if it has none
+func (g *WorldEntity) ScriptName() string {
+	if g.luaScript == nil {
+		return ""
+	}
+
+	return g.luaScript.Name()
+}
+
 func (g *WorldEntity) Init() {
 	if g.luaScript != nil {
 		err := g.luaScript.Init(g)
@@ -299,6 +309,10 @@ func (n *WorldEntity) worldEntityFlags() uint32 {
 	return flags
 }
 
+func (w *WorldEntity) IsDead() bool {
+	return w.HP <= 0
+}
+
 func (w *WorldEntity) GetSynch() uint32 {
 	synch := w.HP.ToWire() & 0xFFFFFF00
 
@@ -319,6 +333,7 @@ func NewWorldEntity(gcType string) *WorldEntity {
 	return &WorldEntity{
 		Entity:               entity,
 		HP:                   drfloat.FromInt32(100),
+		MaxHP:                drfloat.FromInt32(100),
 		MP:                   drfloat.FromInt32(100),
 		WorldEntityFlags:     0x04,
 		WorldEntityInitFlags: 0xFF,
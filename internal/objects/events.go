@@ -11,6 +11,12 @@ type ExecuteActionEvent struct {
 	UnitBehavior IUnitBehavior
 }
 
+// AttackEvent is emitted when a unit attacks another unit, the attack is resolved by the combat package
+type AttackEvent struct {
+	UnitBehavior *UnitBehavior
+	Target       IUnit
+}
+
 type PlayerEnteredZoneEvent struct {
 	Player *Player
 	Zone   *Zone
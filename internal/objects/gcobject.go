@@ -289,6 +289,12 @@ func (g *GCObject) AddChild(child drobjecttypes.DRObject) {
 	child.SetParent(g)
 
 	g.GCChildren = append(g.GCChildren, child)
+
+	// Children added to something already in a zone, such as items picked up into an inventory, are indexed so they
+	// can be found by ID
+	if g.EntityProperties.Zone != nil {
+		g.EntityProperties.Zone.adoptChild(child)
+	}
 }
 
 func (p GCObjectProperty) Serialise(b *byter.Byter, useHash bool) {
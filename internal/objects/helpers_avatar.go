@@ -3,6 +3,7 @@ package objects
 import (
 	"RainbowRunner/internal/database"
 	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
 	"RainbowRunner/internal/types/drobjecttypes"
 	"fmt"
 	lua2 "github.com/yuin/gopher-lua"
@@ -21,16 +22,71 @@ func registerLuaObjectHelpers(s *lua2.LState) {
 	})
 }
 
+var defaultAppearance = storage.Appearance{
+	Hair: 0x01,
+	Skin: 0x01,
+}
+
+const defaultAvatarClass = "avatar.classes.FighterFemale"
+const defaultAvatarLevel = 50
+
+// Items always need a mod, the starting equipment lists only give one for some items
+const defaultItemMod = "ScaleModPAL.Rare.Mod1"
+
+// The skills given to avatars whose class is not in the extracted config
+var defaultStartingSkills = []*database.StartingSkill{
+	{GCType: "skills.generic.Stomp", Slot: 0x64, Level: 1},
+	{GCType: "skills.generic.Sprint", Slot: 0x65, Level: 1},
+	{GCType: "skills.generic.Butcher", Slot: 0x66, Level: 1},
+	{GCType: "skills.generic.Blight", Slot: 0x67, Level: 1},
+	{GCType: "skills.generic.Charge", Slot: 0x68, Level: 1},
+	{GCType: "skills.generic.Cleave", Slot: 0x69, Level: 1},
+	{GCType: "skills.generic.IceBolt", Slot: 0x6A, Level: 1},
+	{GCType: "skills.generic.IceShot", Slot: 0x6B, Level: 1},
+	{GCType: "skills.generic.ManaShield", Slot: 0x6C, Level: 1},
+	{GCType: "skills.generic.FearShot", Slot: 10, Level: 1},
+}
+
 func LoadAvatar() *Avatar {
-	avatar := NewAvatar("avatar.classes.FighterFemale")
+	avatar := NewAvatarWithComponents(defaultAvatarClass, defaultAppearance, defaultAvatarLevel)
+
+	AddEquipment(avatar.GetEquipmentInventory(), avatar.GetManipulators(),
+		"PlateArmor3PAL.PlateArmor3-7",
+		"PlateBoots3PAL.PlateBoots3-7",
+		"PlateHelm3PAL.PlateHelm3-7",
+		"PlateGloves3PAL.PlateGloves3-7",
+		"CrystalMythicPAL.CrystalMythicShield1",
+	)
+
+	return avatar
+}
+
+// avatarStartingSkills are not stored with the character so they are rebuilt from the class every time it is loaded
+func avatarStartingSkills(gcType string) []*database.StartingSkill {
+	class := database.GetAvatarClass(gcType)
+
+	if class == nil || len(class.StartingSkills) == 0 {
+		return defaultStartingSkills
+	}
+
+	return class.StartingSkills
+}
+
+// NewAvatarWithComponents creates an avatar with all the components the client expects but no equipment or items
+func NewAvatarWithComponents(gcType string, appearance storage.Appearance, level byte) *Avatar {
+	avatar := NewAvatar(gcType)
 	avatar.GCLabel = "Avatar Name"
+	avatar.Level = level
+	avatar.FaceVariant = appearance.Face
+	avatar.HairStyle = appearance.Hair
+	avatar.HairColour = appearance.HairColour
 	avatar.Properties = []GCObjectProperty{
-		Uint32Prop("Hair", 0x01),
-		Uint32Prop("HairColor", 0x00),
-		Uint32Prop("Face", 0),
-		Uint32Prop("FaceFeature", 0),
-		Uint32Prop("Skin", 0x01),
-		Uint32Prop("Level", 50),
+		Uint32Prop("Hair", uint32(appearance.Hair)),
+		Uint32Prop("HairColor", uint32(appearance.HairColour)),
+		Uint32Prop("Face", uint32(appearance.Face)),
+		Uint32Prop("FaceFeature", uint32(appearance.FaceFeature)),
+		Uint32Prop("Skin", uint32(appearance.Skin)),
+		Uint32Prop("Level", uint32(level)),
 	}
 
 	//metrics := NewAvatarMetrics(0xFE34BE34, "EllieMetrics")
@@ -64,43 +120,20 @@ func LoadAvatar() *Avatar {
 	//}
 	//avatarSkills.AddChild(skillSlot)
 
-	skillsToAdd := []struct {
-		Name  string
-		Level byte
-		Unk0  uint32
-	}{
-		{"skills.generic.Stomp", 1, 1},
-		{"skills.generic.Sprint", 1, 2},
-		{"skills.generic.Butcher", 1, 3},
-		{"skills.generic.Blight", 1, 5},
-		{"skills.generic.Charge", 1, 6},
-		{"skills.generic.Cleave", 1, 7},
-		{"skills.generic.IceBolt", 1, 9},
-		{"skills.generic.IceShot", 1, 10},
-		{"skills.generic.ManaShield", 1, 11},
-		{"skills.generic.FearShot", 1, 10000},
-	}
-
-	for i, s := range skillsToAdd {
-		skill := NewActiveSkill(s.Name)
+	for i, s := range avatarStartingSkills(gcType) {
+		skill := NewActiveSkill(s.GCType)
 		skill.Level = s.Level
-		skill.GCLabel = s.Name
+		skill.GCLabel = s.GCType
 
 		skill.Properties = []GCObjectProperty{
 			//objects.Uint32Prop("Level", s.Level),
 		}
 
-		hotbarSlot := i + 0x64
-
-		if hotbarSlot >= 0x6D {
-			hotbarSlot = i + 1
-		}
-
-		if hotbarSlot >= 0x64 {
+		if s.Slot >= 0x64 {
 			manipulators.AddChild(skill)
 		}
 
-		avatarSkills.AddSkill(skill, i+1, hotbarSlot)
+		avatarSkills.AddSkill(skill, i+1, s.Slot)
 	}
 
 	//skillSlot := objects.NewComponent("skillslot", "skillslot")
@@ -167,14 +200,6 @@ func LoadAvatar() *Avatar {
 
 	//r := rand.New(rand.NewSource(time.Now().Unix()))
 
-	AddEquipment(avatarEquipment, manipulators,
-		"PlateArmor3PAL.PlateArmor3-7",
-		"PlateBoots3PAL.PlateBoots3-7",
-		"PlateHelm3PAL.PlateHelm3-7",
-		"PlateGloves3PAL.PlateGloves3-7",
-		"CrystalMythicPAL.CrystalMythicShield1",
-	)
-
 	unitBehaviour := NewUnitBehavior("avatar.base.UnitBehavior")
 	//unitBehaviour.UnitBehaviorUnk1 = 0x01
 	//unitBehaviour.UnitBehaviorUnk2 = 0x01
@@ -0,0 +1,370 @@
+package objects
+
+import (
+	"RainbowRunner/internal/database"
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
+	"RainbowRunner/internal/types"
+	"RainbowRunner/internal/types/drconfigtypes"
+	"RainbowRunner/internal/types/drobjecttypes"
+	"RainbowRunner/pkg/datatypes"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// savedLocation is where the character was when they were last saved, it is used once on the first zone join
+type savedLocation struct {
+	Zone     string
+	Position *datatypes.Vector3Float32
+	Heading  float32
+}
+
+// NewCharacter creates the record for a brand-new character of the class with its starting equipment from config
+func NewCharacter(accountName string, name string, class *database.AvatarClass, appearance storage.Appearance) *storage.Character {
+	level := byte(class.Level)
+
+	if level == 0 {
+		level = serverconfig.Config.Characters.StartingLevel
+	}
+
+	// Stored characters without a level are loaded at the default level
+	if level == 0 {
+		level = 1
+	}
+
+	if appearance.Skin == 0 {
+		appearance.Skin = class.Skin
+	}
+
+	if appearance.Skin == 0 {
+		appearance.Skin = defaultAppearance.Skin
+	}
+
+	avatar := NewAvatarWithComponents(class.GCType, appearance, level)
+
+	equipment := avatar.GetEquipmentInventory()
+	manipulators := avatar.GetManipulators()
+
+	for _, startingEquipment := range class.StartingEquipment {
+		item, err := newStartingEquipment(startingEquipment)
+
+		if err != nil {
+			log.Errorf("could not add starting equipment for %s: %s", class.GCType, err.Error())
+			continue
+		}
+
+		equipment.AddChild(item)
+		manipulators.AddChild(item)
+	}
+
+	player := NewPlayer(name)
+	player.AddChild(avatar)
+
+	character := &storage.Character{
+		AccountName: accountName,
+	}
+
+	UpdateCharacterFromPlayer(character, player)
+
+	return character
+}
+
+// LoadPlayerFromCharacter rebuilds a player and their avatar from a stored character
+func LoadPlayerFromCharacter(character *storage.Character) *Player {
+	class := character.Class
+
+	if class == "" {
+		class = defaultAvatarClass
+	}
+
+	level := character.Level
+
+	if level == 0 {
+		level = defaultAvatarLevel
+	}
+
+	avatar := NewAvatarWithComponents(class, character.Appearance, level)
+	avatar.ExpThisLevel = character.Experience
+
+	equipment := avatar.GetEquipmentInventory()
+	manipulators := avatar.GetManipulators()
+
+	for _, record := range character.Equipment {
+		item, err := newItemFromRecord(record)
+
+		if err != nil {
+			log.Errorf("could not load equipment for character %d: %s", character.ID, err.Error())
+			continue
+		}
+
+		equipment.AddChild(item)
+		manipulators.AddChild(item)
+	}
+
+	unitContainer := avatar.GetUnitContainer()
+
+	for _, record := range character.Inventory {
+		inventory := unitContainer.GetInventoryByID(record.InventoryID)
+
+		if inventory == nil {
+			log.Errorf("could not find inventory %d for character %d", record.InventoryID, character.ID)
+			continue
+		}
+
+		item, err := newItemFromRecord(record)
+
+		if err != nil {
+			log.Errorf("could not load item for character %d: %s", character.ID, err.Error())
+			continue
+		}
+
+		if err = inventory.PlaceItem(item, int(record.X), int(record.Y)); err != nil {
+			placeOverlappingItem(inventory, item, record)
+		}
+	}
+
+	player := NewPlayer(character.Name)
+	player.CharacterID = character.ID
+	player.AddChild(avatar)
+
+	if character.Zone != "" {
+		player.savedLocation = &savedLocation{
+			Zone:     character.Zone,
+			Position: character.Position,
+			Heading:  character.Heading,
+		}
+	}
+
+	return player
+}
+
+// placeOverlappingItem moves items saved before placement was checked to the first free space, they are left where they
+// were saved if the inventory is full so nothing is lost
+func placeOverlappingItem(inventory *Inventory, item drobjecttypes.DRObject, record *storage.ItemRecord) {
+	inventoryItem, err := GetInventoryItem(item)
+
+	if err != nil {
+		log.Errorf("could not add %s to inventory %d: %s", record.GCType, record.InventoryID, err.Error())
+		return
+	}
+
+	if x, y, ok := inventory.FindFreeSpace(inventoryItem); ok && inventory.PlaceItem(item, x, y) == nil {
+		return
+	}
+
+	log.Warnf("no space for %s in inventory %d, it overlaps other items", record.GCType, record.InventoryID)
+
+	inventoryItem.Position = datatypes.Vector2{X: record.X, Y: record.Y}
+
+	if err := inventory.AddItem(item); err != nil {
+		log.Errorf("could not add %s to inventory %d: %s", record.GCType, record.InventoryID, err.Error())
+	}
+}
+
+// UpdateCharacterFromPlayer copies the current state of the player into the stored character
+func UpdateCharacterFromPlayer(character *storage.Character, player *Player) {
+	avatar := player.GetAvatar()
+
+	character.Name = player.Name
+	character.Class = avatar.GCType
+	character.Level = avatar.Level
+	character.Experience = avatar.ExpThisLevel
+	character.Appearance = avatarAppearance(avatar)
+
+	if player.Zone != nil {
+		character.Zone = player.Zone.Name
+		character.Position = nil
+
+		// Positions are only meaningful once the avatar has been placed in the zone
+		if player.Spawned {
+			unitBehavior := avatar.GetUnitBehaviour()
+			position := unitBehavior.Position
+
+			character.Position = &position
+			character.Heading = unitBehavior.Heading
+		}
+	}
+
+	character.Equipment = make([]*storage.ItemRecord, 0)
+
+	for _, equipment := range avatar.GetEquipmentInventory().GetEquipment() {
+		character.Equipment = append(character.Equipment, newItemRecord(equipment.(drobjecttypes.DRObject), 0))
+	}
+
+	character.Inventory = make([]*storage.ItemRecord, 0)
+
+	for _, child := range avatar.GetUnitContainer().Children() {
+		inventory, ok := child.(*Inventory)
+
+		if !ok {
+			continue
+		}
+
+		for _, item := range inventory.Children() {
+			if _, ok := item.(IItem); !ok {
+				continue
+			}
+
+			character.Inventory = append(character.Inventory, newItemRecord(item, inventory.InventoryID))
+		}
+	}
+}
+
+// SavePlayer writes the current state of the player back to the character store
+func SavePlayer(player *Player) error {
+	if player.CharacterID == 0 {
+		return errors.New(fmt.Sprintf("player %s does not have a stored character", player.Name))
+	}
+
+	character, err := storage.Store.GetCharacter(player.CharacterID)
+
+	if err != nil {
+		return err
+	}
+
+	UpdateCharacterFromPlayer(character, player)
+
+	return storage.Store.SaveCharacter(character)
+}
+
+// SaveAllPlayers saves the current character of every connected player
+func SaveAllPlayers() {
+	for _, rrPlayer := range Players.GetPlayers() {
+		if rrPlayer.CurrentCharacter == nil || rrPlayer.CurrentCharacter.CharacterID == 0 {
+			continue
+		}
+
+		if err := SavePlayer(rrPlayer.CurrentCharacter); err != nil {
+			log.Errorf("failed to save character %s: %s", rrPlayer.CurrentCharacter.Name, err.Error())
+		}
+	}
+}
+
+// SavedZoneName is the zone the character was in when it was last saved, or empty for new characters
+func (p *Player) SavedZoneName() string {
+	if p.savedLocation == nil {
+		return ""
+	}
+
+	return p.savedLocation.Zone
+}
+
+// restoreSavedPosition moves the avatar back to where it was saved if it is entering the zone it was saved in
+func (p *Player) restoreSavedPosition(zone *Zone) {
+	location := p.savedLocation
+	p.savedLocation = nil
+
+	if location == nil || location.Position == nil || !strings.EqualFold(location.Zone, zone.Name) {
+		return
+	}
+
+	unitBehavior := p.GetAvatar().GetUnitBehaviour()
+	unitBehavior.Heading = location.Heading
+	unitBehavior.Spawn(*location.Position)
+}
+
+func avatarAppearance(avatar *Avatar) storage.Appearance {
+	appearance := storage.Appearance{
+		Face:       avatar.FaceVariant,
+		Hair:       avatar.HairStyle,
+		HairColour: avatar.HairColour,
+	}
+
+	for _, property := range avatar.Properties {
+		value, ok := property.Value.(uint32)
+
+		if !ok {
+			continue
+		}
+
+		switch property.Name {
+		case "FaceFeature":
+			appearance.FaceFeature = byte(value)
+		case "Skin":
+			appearance.Skin = byte(value)
+		}
+	}
+
+	return appearance
+}
+
+func newItemRecord(object drobjecttypes.DRObject, inventoryID byte) *storage.ItemRecord {
+	item := object.(IItem).GetItem()
+
+	record := &storage.ItemRecord{
+		GCType:      item.GCType,
+		ModGCType:   item.Mod,
+		ItemType:    string(item.ItemType),
+		InventoryID: inventoryID,
+	}
+
+	if item.InventoryItem != nil {
+		record.X = item.InventoryItem.Position.X
+		record.Y = item.InventoryItem.Position.Y
+	}
+
+	if equipment, ok := object.(IEquipment); ok {
+		record.Slot = uint32(equipment.GetEquipment().Slot)
+	}
+
+	return record
+}
+
+// newStartingEquipment looks the item up in the fixtures because the starting lists only have the GCType and slot
+func newStartingEquipment(equipment *database.StartingEquipment) (drobjecttypes.DRObject, error) {
+	mod := equipment.ModGCType
+
+	if mod == "" {
+		mod = defaultItemMod
+	}
+
+	if database.FindItem(database.Armour, equipment.GCType) != nil {
+		return NewEquipment(equipment.GCType, mod, ItemArmour, equipment.Slot), nil
+	}
+
+	weapon := database.FindItem(database.Weapons, equipment.GCType)
+
+	if weapon == nil {
+		return nil, errors.New(fmt.Sprintf("could not find item '%s'", equipment.GCType))
+	}
+
+	if database.IsMeleeWeapon(weapon) {
+		return NewMeleeWeapon(equipment.GCType, mod), nil
+	}
+
+	return NewEquipment(equipment.GCType, mod, ItemRangedWeapon, equipment.Slot), nil
+}
+
+func newItemFromRecord(record *storage.ItemRecord) (drobjecttypes.DRObject, error) {
+	itemType := ItemType(record.ItemType)
+
+	if record.Slot == uint32(types.EquipmentSlotNone) {
+		item := NewItem(string(itemType), itemType)
+		item.GCType = record.GCType
+		item.Mod = record.ModGCType
+		item.ItemType = itemType
+
+		return item, nil
+	}
+
+	var db []*drconfigtypes.DRClassChildGroup
+
+	if itemType == ItemArmour {
+		db = database.Armour
+	} else {
+		db = database.Weapons
+	}
+
+	// NewEquipment panics for unknown items, stored items can outlive the config they were created from
+	if database.FindItem(db, record.GCType) == nil {
+		return nil, errors.New(fmt.Sprintf("could not find item '%s'", record.GCType))
+	}
+
+	if itemType == ItemMeleeWeapon {
+		return NewMeleeWeapon(record.GCType, record.ModGCType), nil
+	}
+
+	return NewEquipment(record.GCType, record.ModGCType, itemType, types.EquipmentSlot(record.Slot)), nil
+}
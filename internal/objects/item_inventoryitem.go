@@ -0,0 +1,65 @@
+package objects
+
+import (
+	"RainbowRunner/internal/types/drobjecttypes"
+	"RainbowRunner/pkg/datatypes"
+	"errors"
+	"fmt"
+)
+
+// InventoryItem holds everything about an item that depends on where it is kept rather than what it is, the same
+// InventoryItem follows the item between inventories, equipment and the cursor
+//
+//go:generate go run ../../scripts/generatelua -type=InventoryItem
+type InventoryItem struct {
+	// Unique within the inventory for inventory items, the slot for equipped items
+	Index    int
+	Position datatypes.Vector2
+	Count    int
+	// The Inventory or EquipmentInventory the item is in, nil when it is on the cursor or on the ground
+	Owner drobjecttypes.DRObject
+	// The Item, Equipment or MeleeWeapon
+	Object drobjecttypes.DRObject
+}
+
+// Item is the Item embedded in the object, every kind of item has one
+func (i *InventoryItem) Item() *Item {
+	return i.Object.(IItem).GetItem()
+}
+
+// Equipment returns nil if the item cannot be equipped
+func (i *InventoryItem) Equipment() *Equipment {
+	if equipment, ok := i.Object.(IEquipment); ok {
+		return equipment.GetEquipment()
+	}
+
+	return nil
+}
+
+func (i *InventoryItem) GetGCType() string {
+	return i.Object.GetGCType()
+}
+
+// GetInventoryItem returns the InventoryItem of the object, creating it the first time the object is kept somewhere
+func GetInventoryItem(object drobjecttypes.DRObject) (*InventoryItem, error) {
+	if object == nil {
+		return nil, errors.New("cannot use nil as an inventory item")
+	}
+
+	iItem, ok := object.(IItem)
+
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("'%s' is not an item", object.GetGCType()))
+	}
+
+	item := iItem.GetItem()
+
+	if item.InventoryItem == nil {
+		item.InventoryItem = &InventoryItem{
+			Count:  1,
+			Object: object,
+		}
+	}
+
+	return item.InventoryItem, nil
+}
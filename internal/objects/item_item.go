@@ -2,21 +2,16 @@ package objects
 
 import (
 	"RainbowRunner/pkg/byter"
-	"RainbowRunner/pkg/datatypes"
 )
 
 //go:generate go run ../../scripts/generatelua -type=Item -extends=Manipulator
 type Item struct {
 	*Manipulator
-	ModCount          int
-	Mod               string
-	ItemType          ItemType
-	InventoryPosition datatypes.Vector2
-	Index             int
-}
-
-func (n *Item) SetInventoryPosition(vector2 datatypes.Vector2) {
-	n.InventoryPosition = vector2
+	ModCount int
+	Mod      string
+	ItemType ItemType
+	// Where the item is kept, nil until it is first added to an inventory or equipped
+	InventoryItem *InventoryItem
 }
 
 func (n *Item) WriteInit(b *byter.Byter) {
@@ -28,13 +23,21 @@ func (n *Item) WriteInit(b *byter.Byter) {
 	// This is the item index within the specific inventory
 	// Equipment = Slots
 	// Inventory = unique ID
-	// TODO extract this into InventoryItem/EquippedItem
-	b.WriteUInt32(uint32(n.Index))
+	index, x, y, count := 0, 0, 0, 1
+
+	if n.InventoryItem != nil {
+		index = n.InventoryItem.Index
+		x = int(n.InventoryItem.Position.X)
+		y = int(n.InventoryItem.Position.Y)
+		count = n.InventoryItem.Count
+	}
+
+	b.WriteUInt32(uint32(index))
 
-	b.WriteByte(byte(n.InventoryPosition.X))
-	b.WriteByte(byte(n.InventoryPosition.Y))
+	b.WriteByte(byte(x))
+	b.WriteByte(byte(y))
 
-	b.WriteByte(0x01) // Item count
+	b.WriteByte(byte(count)) // Item count
 
 	b.WriteByte(50 + 5) // Required level + 5
 
@@ -3,6 +3,7 @@ package objects
 import (
 	"RainbowRunner/internal/actions"
 	"RainbowRunner/internal/database"
+	"RainbowRunner/internal/global"
 	lua2 "RainbowRunner/internal/lua"
 	"RainbowRunner/internal/script"
 	"RainbowRunner/internal/types/configtypes"
@@ -11,12 +12,13 @@ import (
 	lua "github.com/yuin/gopher-lua"
 )
 
-//go:generate go run ../../scripts/generateluaregistrations -includes=.,../actions,../database,../types/configtypes
+//go:generate go run ../../scripts/generateluaregistrations -includes=.,../actions,../database,../global,../types/configtypes
 func RegisterLuaGlobals(state *lua.LState) {
 	script.RegisterAllTime(state)
 	RegisterAllLuaFunctions(state)
 	actions.RegisterAllLuaFunctions(state)
 	database.RegisterAllLuaFunctions(state)
+	global.RegisterAllLuaFunctions(state)
 	configtypes.RegisterAllLuaFunctions(state)
 
 	lua2.RegisterModules(state)
@@ -26,6 +26,7 @@ func RegisterAllLuaFunctions(state *lua2.LState) {
 	registerLuaGCObject(state)
 	registerLuaHero(state)
 	registerLuaInventory(state)
+	registerLuaInventoryItem(state)
 	registerLuaItem(state)
 	registerLuaItemObject(state)
 	registerLuaManipulator(state)
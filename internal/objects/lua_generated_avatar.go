@@ -35,7 +35,7 @@ func luaMethodsAvatar() map[string]lua2.LGFunction {
 		"rotation":           lua.LuaGenericGetSetNumber[IAvatar](func(v IAvatar) *int32 { return &v.GetAvatar().Rotation }),
 		"clientUpdateNumber": lua.LuaGenericGetSetNumber[IAvatar](func(v IAvatar) *byte { return &v.GetAvatar().ClientUpdateNumber }),
 		"moveUpdate":         lua.LuaGenericGetSetNumber[IAvatar](func(v IAvatar) *int { return &v.GetAvatar().MoveUpdate }),
-		"isSpawned":          lua.LuaGenericGetSetBool[IAvatar](func(v IAvatar) *bool { return &v.GetAvatar().Spawned }),
+		"spawned":            lua.LuaGenericGetSetBool[IAvatar](func(v IAvatar) *bool { return &v.GetAvatar().Spawned }),
 		"faceVariant":        lua.LuaGenericGetSetNumber[IAvatar](func(v IAvatar) *byte { return &v.GetAvatar().FaceVariant }),
 		"hairStyle":          lua.LuaGenericGetSetNumber[IAvatar](func(v IAvatar) *byte { return &v.GetAvatar().HairStyle }),
 		"hairColour":         lua.LuaGenericGetSetNumber[IAvatar](func(v IAvatar) *byte { return &v.GetAvatar().HairColour }),
@@ -177,6 +177,19 @@ func luaMethodsAvatar() map[string]lua2.LGFunction {
 			return 1
 		},
 
+		"getEquipmentInventory": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IAvatar](l, 1)
+			obj := objInterface.GetAvatar()
+			res0 := obj.GetEquipmentInventory()
+			if res0 != nil {
+				l.Push(res0.ToLua(l))
+			} else {
+				l.Push(lua2.LNil)
+			}
+
+			return 1
+		},
+
 		"getUnitBehaviour": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IAvatar](l, 1)
 			obj := objInterface.GetAvatar()
@@ -32,8 +32,8 @@ func registerLuaEquipmentInventory(state *lua2.LState) {
 func luaMethodsEquipmentInventory() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
 		"avatar": lua.LuaGenericGetSetValueAny[IEquipmentInventory](func(v IEquipmentInventory) **Avatar { return &v.GetEquipmentInventory().Avatar }),
-		"slots": lua.LuaGenericGetSetValueAny[IEquipmentInventory](func(v IEquipmentInventory) *map[types.EquipmentSlot]IEquipment {
-			return &v.GetEquipmentInventory().Slots
+		"items": lua.LuaGenericGetSetValueAny[IEquipmentInventory](func(v IEquipmentInventory) *map[types.EquipmentSlot]*InventoryItem {
+			return &v.GetEquipmentInventory().Items
 		}),
 
 		"writeInit": func(l *lua2.LState) int {
@@ -76,10 +76,11 @@ func luaMethodsEquipmentInventory() map[string]lua2.LGFunction {
 			res0 := obj.RemoveEquipmentBySlot(
 				lua.CheckValue[types.EquipmentSlot](l, 2),
 			)
-			ud := l.NewUserData()
-			ud.Value = res0
-			l.SetMetatable(ud, l.GetTypeMetatable("IEquipment"))
-			l.Push(ud)
+			if res0 != nil {
+				l.Push(res0.ToLua(l))
+			} else {
+				l.Push(lua2.LNil)
+			}
 
 			return 1
 		},
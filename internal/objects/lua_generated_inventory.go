@@ -31,16 +31,22 @@ func registerLuaInventory(state *lua2.LState) {
 func luaMethodsInventory() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
 		"inventoryID": lua.LuaGenericGetSetNumber[IInventory](func(v IInventory) *byte { return &v.GetInventory().InventoryID }),
-		"items":       lua.LuaGenericGetSetValueAny[IInventory](func(v IInventory) *[]IItem { return &v.GetInventory().Items }),
+		"items":       lua.LuaGenericGetSetValueAny[IInventory](func(v IInventory) *[]*InventoryItem { return &v.GetInventory().Items }),
+		"width":       lua.LuaGenericGetSetNumber[IInventory](func(v IInventory) *int { return &v.GetInventory().Width }),
+		"height":      lua.LuaGenericGetSetNumber[IInventory](func(v IInventory) *int { return &v.GetInventory().Height }),
 
 		"addItem": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IInventory](l, 1)
 			obj := objInterface.GetInventory()
-			obj.AddItem(
+			res0 := obj.AddItem(
 				lua.CheckValue[drobjecttypes.DRObject](l, 2),
 			)
+			ud := l.NewUserData()
+			ud.Value = res0
+			l.SetMetatable(ud, l.GetTypeMetatable("error"))
+			l.Push(ud)
 
-			return 0
+			return 1
 		},
 
 		"writeInit": func(l *lua2.LState) int {
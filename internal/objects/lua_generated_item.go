@@ -4,7 +4,6 @@ package objects
 import (
 	lua "RainbowRunner/internal/lua"
 	"RainbowRunner/pkg/byter"
-	"RainbowRunner/pkg/datatypes"
 	lua2 "github.com/yuin/gopher-lua"
 )
 
@@ -30,21 +29,10 @@ func registerLuaItem(state *lua2.LState) {
 
 func luaMethodsItem() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
-		"modCount":          lua.LuaGenericGetSetNumber[IItem](func(v IItem) *int { return &v.GetItem().ModCount }),
-		"mod":               lua.LuaGenericGetSetString[IItem](func(v IItem) *string { return &v.GetItem().Mod }),
-		"itemType":          lua.LuaGenericGetSetValueAny[IItem](func(v IItem) *ItemType { return &v.GetItem().ItemType }),
-		"inventoryPosition": lua.LuaGenericGetSetValueAny[IItem](func(v IItem) *datatypes.Vector2 { return &v.GetItem().InventoryPosition }),
-		"index":             lua.LuaGenericGetSetNumber[IItem](func(v IItem) *int { return &v.GetItem().Index }),
-
-		"setInventoryPosition": func(l *lua2.LState) int {
-			objInterface := lua.CheckInterfaceValue[IItem](l, 1)
-			obj := objInterface.GetItem()
-			obj.SetInventoryPosition(
-				lua.CheckValue[datatypes.Vector2](l, 2),
-			)
-
-			return 0
-		},
+		"modCount":      lua.LuaGenericGetSetNumber[IItem](func(v IItem) *int { return &v.GetItem().ModCount }),
+		"mod":           lua.LuaGenericGetSetString[IItem](func(v IItem) *string { return &v.GetItem().Mod }),
+		"itemType":      lua.LuaGenericGetSetValueAny[IItem](func(v IItem) *ItemType { return &v.GetItem().ItemType }),
+		"inventoryItem": lua.LuaGenericGetSetValueAny[IItem](func(v IItem) **InventoryItem { return &v.GetItem().InventoryItem }),
 
 		"writeInit": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IItem](l, 1)
@@ -32,6 +32,17 @@ func luaMethodsItemObject() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
 		"item": lua.LuaGenericGetSetValueAny[IItemObject](func(v IItemObject) *drobjecttypes.DRObject { return &v.GetItemObject().Item }),
 
+		"activate": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IItemObject](l, 1)
+			obj := objInterface.GetItemObject()
+			obj.Activate(
+				lua.CheckReferenceValue[RRPlayer](l, 2),
+				lua.CheckReferenceValue[UnitBehavior](l, 3), byte(l.CheckNumber(4)), byte(l.CheckNumber(5)),
+			)
+
+			return 0
+		},
+
 		"type": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IItemObject](l, 1)
 			obj := objInterface.GetItemObject()
@@ -3,6 +3,7 @@ package objects
 
 import (
 	lua "RainbowRunner/internal/lua"
+	"RainbowRunner/internal/types/configtypes"
 	"RainbowRunner/pkg/byter"
 	lua2 "github.com/yuin/gopher-lua"
 )
@@ -40,6 +41,29 @@ func luaMethodsMonsterBehavior2() map[string]lua2.LGFunction {
 		"monsterBehavior2Unk6":             lua.LuaGenericGetSetNumber[IMonsterBehavior2](func(v IMonsterBehavior2) *uint16 { return &v.GetMonsterBehavior2().MonsterBehavior2Unk6 }),
 		"monsterBehavior2Unk7":             lua.LuaGenericGetSetNumber[IMonsterBehavior2](func(v IMonsterBehavior2) *uint16 { return &v.GetMonsterBehavior2().MonsterBehavior2Unk7 }),
 		"monsterBehavior2Unk8":             lua.LuaGenericGetSetNumber[IMonsterBehavior2](func(v IMonsterBehavior2) *uint16 { return &v.GetMonsterBehavior2().MonsterBehavior2Unk8 }),
+		"attackRange":                      lua.LuaGenericGetSetNumber[IMonsterBehavior2](func(v IMonsterBehavior2) *float32 { return &v.GetMonsterBehavior2().AttackRange }),
+		"aggroRange":                       lua.LuaGenericGetSetNumber[IMonsterBehavior2](func(v IMonsterBehavior2) *float32 { return &v.GetMonsterBehavior2().AggroRange }),
+		"leashRange":                       lua.LuaGenericGetSetNumber[IMonsterBehavior2](func(v IMonsterBehavior2) *float32 { return &v.GetMonsterBehavior2().LeashRange }),
+		"wanderRange":                      lua.LuaGenericGetSetNumber[IMonsterBehavior2](func(v IMonsterBehavior2) *uint16 { return &v.GetMonsterBehavior2().WanderRange }),
+		"creatureFamily":                   lua.LuaGenericGetSetString[IMonsterBehavior2](func(v IMonsterBehavior2) *string { return &v.GetMonsterBehavior2().CreatureFamily }),
+
+		"tick": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IMonsterBehavior2](l, 1)
+			obj := objInterface.GetMonsterBehavior2()
+			obj.Tick()
+
+			return 0
+		},
+
+		"configure": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IMonsterBehavior2](l, 1)
+			obj := objInterface.GetMonsterBehavior2()
+			obj.Configure(
+				lua.CheckReferenceValue[configtypes.EntityDesc](l, 2),
+			)
+
+			return 0
+		},
 
 		"writeInit": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IMonsterBehavior2](l, 1)
@@ -51,6 +75,37 @@ func luaMethodsMonsterBehavior2() map[string]lua2.LGFunction {
 			return 0
 		},
 
+		"onAttacked": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IMonsterBehavior2](l, 1)
+			obj := objInterface.GetMonsterBehavior2()
+			obj.OnAttacked(
+				lua.CheckReferenceValue[UnitBehavior](l, 2),
+			)
+
+			return 0
+		},
+
+		"state": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IMonsterBehavior2](l, 1)
+			obj := objInterface.GetMonsterBehavior2()
+			res0 := obj.State()
+			ud := l.NewUserData()
+			ud.Value = res0
+			l.SetMetatable(ud, l.GetTypeMetatable("MonsterAIState"))
+			l.Push(ud)
+
+			return 1
+		},
+
+		"isHostile": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IMonsterBehavior2](l, 1)
+			obj := objInterface.GetMonsterBehavior2()
+			res0 := obj.IsHostile()
+			l.Push(lua2.LBool(res0))
+
+			return 1
+		},
+
 		"getMonsterBehavior2": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IMonsterBehavior2](l, 1)
 			obj := objInterface.GetMonsterBehavior2()
@@ -30,7 +30,12 @@ func registerLuaNPC(state *lua2.LState) {
 
 func luaMethodsNPC() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
-		"level": lua.LuaGenericGetSetNumber[INPC](func(v INPC) *int32 { return &v.GetNPC().Level }),
+		"level":          lua.LuaGenericGetSetNumber[INPC](func(v INPC) *int32 { return &v.GetNPC().Level }),
+		"attackRating":   lua.LuaGenericGetSetNumber[INPC](func(v INPC) *float32 { return &v.GetNPC().AttackRating }),
+		"defenseRating":  lua.LuaGenericGetSetNumber[INPC](func(v INPC) *float32 { return &v.GetNPC().DefenseRating }),
+		"criticalChance": lua.LuaGenericGetSetNumber[INPC](func(v INPC) *float32 { return &v.GetNPC().CriticalChance }),
+		"damageMod":      lua.LuaGenericGetSetNumber[INPC](func(v INPC) *float32 { return &v.GetNPC().DamageMod }),
+		"stunResist":     lua.LuaGenericGetSetNumber[INPC](func(v INPC) *int { return &v.GetNPC().StunResist }),
 
 		"writeInit": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[INPC](l, 1)
@@ -29,10 +29,11 @@ func registerLuaPlayer(state *lua2.LState) {
 
 func luaMethodsPlayer() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
-		"name":      lua.LuaGenericGetSetString[IPlayer](func(v IPlayer) *string { return &v.GetPlayer().Name }),
-		"currentHP": lua.LuaGenericGetSetNumber[IPlayer](func(v IPlayer) *uint32 { return &v.GetPlayer().CurrentHP }),
-		"spawned":   lua.LuaGenericGetSetBool[IPlayer](func(v IPlayer) *bool { return &v.GetPlayer().Spawned }),
-		"zone":      lua.LuaGenericGetSetValueAny[IPlayer](func(v IPlayer) **Zone { return &v.GetPlayer().Zone }),
+		"name":        lua.LuaGenericGetSetString[IPlayer](func(v IPlayer) *string { return &v.GetPlayer().Name }),
+		"currentHP":   lua.LuaGenericGetSetNumber[IPlayer](func(v IPlayer) *uint32 { return &v.GetPlayer().CurrentHP }),
+		"spawned":     lua.LuaGenericGetSetBool[IPlayer](func(v IPlayer) *bool { return &v.GetPlayer().Spawned }),
+		"zone":        lua.LuaGenericGetSetValueAny[IPlayer](func(v IPlayer) **Zone { return &v.GetPlayer().Zone }),
+		"characterID": lua.LuaGenericGetSetNumber[IPlayer](func(v IPlayer) *uint32 { return &v.GetPlayer().CharacterID }),
 
 		"getRRPlayer": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IPlayer](l, 1)
@@ -164,6 +165,15 @@ func luaMethodsPlayer() map[string]lua2.LGFunction {
 			return 1
 		},
 
+		"savedZoneName": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IPlayer](l, 1)
+			obj := objInterface.GetPlayer()
+			res0 := obj.SavedZoneName()
+			l.Push(lua2.LString(res0))
+
+			return 1
+		},
+
 		"getPlayer": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IPlayer](l, 1)
 			obj := objInterface.GetPlayer()
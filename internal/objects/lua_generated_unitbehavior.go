@@ -55,6 +55,7 @@ func luaMethodsUnitBehavior() map[string]lua2.LGFunction {
 		"unitBehaviorTicksSinceLastUpdate": lua.LuaGenericGetSetNumber[IUnitBehavior](func(v IUnitBehavior) *byte { return &v.GetUnitBehavior().UnitBehaviorTicksSinceLastUpdate }),
 		"unitBehaviorInitFlags2":           lua.LuaGenericGetSetNumber[IUnitBehavior](func(v IUnitBehavior) *byte { return &v.GetUnitBehavior().UnitBehaviorInitFlags2 }),
 		"isOwnedByCurrentPlayer":           lua.LuaGenericGetSetBool[IUnitBehavior](func(v IUnitBehavior) *bool { return &v.GetUnitBehavior().IsOwnedByCurrentPlayer }),
+		"stunnedUntil":                     lua.LuaGenericGetSetNumber[IUnitBehavior](func(v IUnitBehavior) *uint { return &v.GetUnitBehavior().StunnedUntil }),
 
 		"tick": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IUnitBehavior](l, 1)
@@ -166,6 +167,33 @@ func luaMethodsUnitBehavior() map[string]lua2.LGFunction {
 			return 0
 		},
 
+		"attack": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IUnitBehavior](l, 1)
+			obj := objInterface.GetUnitBehavior()
+			obj.Attack(
+				lua.CheckValue[IUnit](l, 2),
+			)
+
+			return 0
+		},
+
+		"stun": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IUnitBehavior](l, 1)
+			obj := objInterface.GetUnitBehavior()
+			obj.Stun(uint(l.CheckNumber(2)))
+
+			return 0
+		},
+
+		"isStunned": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IUnitBehavior](l, 1)
+			obj := objInterface.GetUnitBehavior()
+			res0 := obj.IsStunned()
+			l.Push(lua2.LBool(res0))
+
+			return 1
+		},
+
 		"executeAction": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IUnitBehavior](l, 1)
 			obj := objInterface.GetUnitBehavior()
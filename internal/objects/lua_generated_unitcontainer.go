@@ -31,7 +31,7 @@ func registerLuaUnitContainer(state *lua2.LState) {
 func luaMethodsUnitContainer() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
 		"manipulator": lua.LuaGenericGetSetValueAny[IUnitContainer](func(v IUnitContainer) *drobjecttypes.DRObject { return &v.GetUnitContainer().Manipulator }),
-		"activeItem":  lua.LuaGenericGetSetValueAny[IUnitContainer](func(v IUnitContainer) *drobjecttypes.DRObject { return &v.GetUnitContainer().ActiveItem }),
+		"activeItem":  lua.LuaGenericGetSetValueAny[IUnitContainer](func(v IUnitContainer) **InventoryItem { return &v.GetUnitContainer().ActiveItem }),
 		"avatar":      lua.LuaGenericGetSetValueAny[IUnitContainer](func(v IUnitContainer) **Avatar { return &v.GetUnitContainer().Avatar }),
 
 		"writeInit": func(l *lua2.LState) int {
@@ -72,7 +72,7 @@ func luaMethodsUnitContainer() map[string]lua2.LGFunction {
 			objInterface := lua.CheckInterfaceValue[IUnitContainer](l, 1)
 			obj := objInterface.GetUnitContainer()
 			obj.SetActiveItem(
-				lua.CheckValue[drobjecttypes.DRObject](l, 2),
+				lua.CheckReferenceValue[InventoryItem](l, 2),
 			)
 
 			return 0
@@ -113,8 +113,7 @@ func luaMethodsUnitContainer() map[string]lua2.LGFunction {
 			obj := objInterface.GetUnitContainer()
 			obj.WriteAddItem(
 				lua.CheckReferenceValue[byter.Byter](l, 2),
-				lua.CheckValue[drobjecttypes.DRObject](l, 3),
-				lua.CheckReferenceValue[Inventory](l, 4), byte(l.CheckNumber(5)), byte(l.CheckNumber(6)),
+				lua.CheckReferenceValue[InventoryItem](l, 3),
 			)
 
 			return 0
@@ -152,6 +152,15 @@ func luaMethodsWorldEntity() map[string]lua2.LGFunction {
 			return 0
 		},
 
+		"isDead": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IWorldEntity](l, 1)
+			obj := objInterface.GetWorldEntity()
+			res0 := obj.IsDead()
+			l.Push(lua2.LBool(res0))
+
+			return 1
+		},
+
 		"getWorldEntity": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IWorldEntity](l, 1)
 			obj := objInterface.GetWorldEntity()
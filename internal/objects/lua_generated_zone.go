@@ -4,6 +4,8 @@ package objects
 import (
 	"RainbowRunner/internal/database"
 	lua "RainbowRunner/internal/lua"
+	"RainbowRunner/internal/message"
+	"RainbowRunner/internal/pathfinding"
 	"RainbowRunner/internal/types"
 	"RainbowRunner/internal/types/drobjecttypes"
 	"RainbowRunner/pkg/byter"
@@ -33,11 +35,12 @@ func registerLuaZone(state *lua2.LState) {
 
 func luaMethodsZone() map[string]lua2.LGFunction {
 	return lua.LuaMethodsExtend(map[string]lua2.LGFunction{
-		"name":       lua.LuaGenericGetSetString[IZone](func(v IZone) *string { return &v.GetZone().Name }),
-		"scripts":    lua.LuaGenericGetSetValueAny[IZone](func(v IZone) **ZoneLuaScripts { return &v.GetZone().Scripts }),
-		"baseConfig": lua.LuaGenericGetSetValueAny[IZone](func(v IZone) **database.ZoneConfig { return &v.GetZone().BaseConfig }),
-		"pathMap":    lua.LuaGenericGetSetValueAny[IZone](func(v IZone) **types.PathMap { return &v.GetZone().PathMap }),
-		"id":         lua.LuaGenericGetSetNumber[IZone](func(v IZone) *uint32 { return &v.GetZone().ID }),
+		"name":        lua.LuaGenericGetSetString[IZone](func(v IZone) *string { return &v.GetZone().Name }),
+		"scripts":     lua.LuaGenericGetSetValueAny[IZone](func(v IZone) **ZoneLuaScripts { return &v.GetZone().Scripts }),
+		"baseConfig":  lua.LuaGenericGetSetValueAny[IZone](func(v IZone) **database.ZoneConfig { return &v.GetZone().BaseConfig }),
+		"pathMap":     lua.LuaGenericGetSetValueAny[IZone](func(v IZone) **types.PathMap { return &v.GetZone().PathMap }),
+		"pathManager": lua.LuaGenericGetSetValueAny[IZone](func(v IZone) **pathfinding.PathManager { return &v.GetZone().PathManager }),
+		"id":          lua.LuaGenericGetSetNumber[IZone](func(v IZone) *uint32 { return &v.GetZone().ID }),
 
 		"getZone": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IZone](l, 1)
@@ -61,6 +64,15 @@ func luaMethodsZone() map[string]lua2.LGFunction {
 			return 1
 		},
 
+		"isRunning": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IZone](l, 1)
+			obj := objInterface.GetZone()
+			res0 := obj.IsRunning()
+			l.Push(lua2.LBool(res0))
+
+			return 1
+		},
+
 		"entities": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IZone](l, 1)
 			obj := objInterface.GetZone()
@@ -310,11 +322,15 @@ func luaMethodsZone() map[string]lua2.LGFunction {
 		"giveID": func(l *lua2.LState) int {
 			objInterface := lua.CheckInterfaceValue[IZone](l, 1)
 			obj := objInterface.GetZone()
-			obj.GiveID(
+			res0 := obj.GiveID(
 				lua.CheckValue[drobjecttypes.DRObject](l, 2),
 			)
+			ud := l.NewUserData()
+			ud.Value = res0
+			l.SetMetatable(ud, l.GetTypeMetatable("error"))
+			l.Push(ud)
 
-			return 0
+			return 1
 		},
 
 		"onPlayerEnter": func(l *lua2.LState) int {
@@ -356,6 +372,40 @@ func luaMethodsZone() map[string]lua2.LGFunction {
 
 			return 0
 		},
+
+		"playerKnows": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IZone](l, 1)
+			obj := objInterface.GetZone()
+			res0 := obj.PlayerKnows(
+				lua.CheckReferenceValue[RRPlayer](l, 2),
+				lua.CheckValue[drobjecttypes.DRObject](l, 3),
+			)
+			l.Push(lua2.LBool(res0))
+
+			return 1
+		},
+
+		"notifyNearbyPlayers": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IZone](l, 1)
+			obj := objInterface.GetZone()
+			obj.NotifyNearbyPlayers(
+				lua.CheckValue[drobjecttypes.DRObject](l, 2), func(v uint16) *uint16 { return &v }(uint16(l.CheckNumber(3))), message.OpType(l.CheckNumber(4)),
+				lua.CheckValue[func() *byter.Byter](l, 5),
+			)
+
+			return 0
+		},
+
+		"sendToNearby": func(l *lua2.LState) int {
+			objInterface := lua.CheckInterfaceValue[IZone](l, 1)
+			obj := objInterface.GetZone()
+			obj.SendToNearby(
+				lua.CheckValue[drobjecttypes.DRObject](l, 2),
+				lua.CheckReferenceValue[byter.Byter](l, 3),
+			)
+
+			return 0
+		},
 	})
 }
 func newLuaZone(l *lua2.LState) int {
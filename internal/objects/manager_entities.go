@@ -7,13 +7,14 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // TODO consider removing/refactoring this entire thing as entities probably need to be separated by zones
 var Entities *EntityManager
 
 // Reserving 10 IDs for player characters
-var currentID = uint16(10)
+var currentID = uint32(10)
 
 type EntityManager struct {
 	sync.RWMutex
@@ -93,10 +94,10 @@ func (m *EntityManager) GetEntities() []drobjecttypes.DRObject {
 	return list
 }
 
+// NewID is only for objects that are not in a zone yet, such as characters on the character select screen, zones
+// allocate their own IDs in Zone.GiveID
 func NewID() (ID uint16) {
-	ID = currentID
-	currentID++
-	return ID
+	return uint16(atomic.AddUint32(&currentID, 1) - 1)
 }
 
 func NewEntityManager() *EntityManager {
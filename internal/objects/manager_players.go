@@ -2,14 +2,16 @@ package objects
 
 import (
 	"RainbowRunner/internal/connections"
+	"RainbowRunner/internal/game/messages"
 	"RainbowRunner/internal/message"
-	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/pkg/byter"
-	"encoding/hex"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 var Players = NewPlayerManager()
@@ -45,18 +47,94 @@ func (m *PlayerManager) Register(rrconn *connections.RRConn) *RRPlayer {
 
 func (m *PlayerManager) OnDisconnect(id int) {
 	m.Lock()
-	defer m.Unlock()
+	player, ok := m.Players[id]
+	delete(m.Players, id)
+	m.Unlock()
 
 	fmt.Printf("Player %d Disconnected\n", id)
-	if player, ok := Players.Players[id]; ok {
+
+	if !ok {
+		return
+	}
+
+	// The character is saved and removed by the zone that owns it so it isn't changing underneath us. This happens
+	// outside the lock as the job runs inline when the player isn't in a running zone.
+	player.Run(func() {
+		saveCharacter(player)
+
 		if player.CurrentCharacter != nil && player.CurrentCharacter.Zone != nil {
 			player.CurrentCharacter.Zone.RemovePlayer(id)
 		}
-	}
+	})
 
 	//Entities.RemoveOwnedBy(id)
+}
+
+// SaveAll saves the character of every player on the goroutine of their zone, it returns false if the saves did not
+// all finish before the timeout
+func (m *PlayerManager) SaveAll(timeout time.Duration) bool {
+	players := m.GetPlayers()
+	wg := sync.WaitGroup{}
+
+	wg.Add(len(players))
+
+	for _, player := range players {
+		player := player
+
+		player.Run(func() {
+			defer wg.Done()
+			saveCharacter(player)
+		})
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Announce sends a global announcement to every player that is in a zone
+func (m *PlayerManager) Announce(text string) {
+	msg := messages.ChatMessage{
+		Channel: messages.MessageChannelSourceGlobalAnnouncement,
+		Message: text,
+	}
+
+	for _, player := range m.GetPlayers() {
+		player := player
+
+		// Whether the character is spawned is changed by the zone that owns the player so it is checked there
+		player.Run(func() {
+			if player.CurrentCharacter == nil || !player.CurrentCharacter.Spawned {
+				return
+			}
+
+			// The connection's own message buffer belongs to the zone goroutine so each announcement gets its own
+			body := byter.NewLEByter(make([]byte, 0, 1024))
+			msg.Write(body)
+
+			connections.WriteCompressedASimple(player.Conn, body)
+		})
+	}
+}
 
-	delete(Players.Players, id)
+func saveCharacter(player *RRPlayer) {
+	if player.CurrentCharacter == nil || player.CurrentCharacter.CharacterID == 0 {
+		return
+	}
+
+	if err := SavePlayer(player.CurrentCharacter); err != nil {
+		logrus.Errorf("failed to save character %s: %s", player.CurrentCharacter.Name, err.Error())
+	}
 }
 
 func (m *PlayerManager) GetPlayerByCharacterName(name string) *RRPlayer {
@@ -91,58 +169,52 @@ func (m *PlayerManager) GetPlayerOrNil(id uint16) *RRPlayer {
 	return player
 }
 
-var playerSendBuffer = byter.NewByter(make([]byte, 1024*1024+10))
-
-func (m *PlayerManager) AfterTick() {
-	playerSendBuffer.Clear()
-	clientEntityWriter := NewClientEntityWriter(playerSendBuffer)
-
-	for _, player := range m.Players {
-		if player.CurrentCharacter == nil {
-			continue
-		}
-
-		if !player.CurrentCharacter.Spawned {
+// ClearUnzonedMessages drops client entity messages queued for players that are not in a zone, they have nothing spawned
+// to send them to. Players in a zone have their messages flushed by the zone at the end of its tick.
+func (m *PlayerManager) ClearUnzonedMessages() {
+	for _, player := range m.GetPlayers() {
+		if player.OwnerZone() == nil {
 			player.MessageQueue.Clear(message.QueueTypeClientEntity)
-			continue
 		}
-
-		clientEntitySend := false
-
-		clientEntityWriter.BeginStream()
-
-		for !player.MessageQueue.IsEmpty(message.QueueTypeClientEntity) {
-			item := player.MessageQueue.Dequeue(message.QueueTypeClientEntity)
-			playerSendBuffer.Write(item.Data)
-
-			if serverconfig.Config.Logging.LogFilterMessages {
-				if logIt, ok := serverconfig.Config.Logging.LogSentMessageTypes[strings.ToLower(item.OpType.String())]; ok && logIt {
-					logrus.Info(fmt.Sprintf("Sent Message:\n%s", hex.Dump(item.Data.Data())))
-				}
-			}
-
-			clientEntitySend = true
-		}
-
-		clientEntityWriter.EndStream()
-
-		if clientEntitySend {
-			connections.WriteCompressedASimple(player.Conn, playerSendBuffer)
-		}
-
-		playerSendBuffer.Clear()
-		player.ClientEntityWriter.Clear()
 	}
 }
 
-func (m *PlayerManager) BeforeTick() {
-	for _, player := range m.Players {
-		player.ClientEntityWriter.BeginStream()
+func NewPlayerManager() *PlayerManager {
+	pm := &PlayerManager{
+		Players: make(map[int]*RRPlayer),
 	}
+
+	metrics.RegisterGaugeFunc("rr_outbound_queue_depth", "Messages waiting in the outbound queue of each connection", func() []metrics.Sample {
+		return pm.outboundSamples(func(stats connections.OutboundStats) int {
+			return stats.QueueDepth
+		})
+	})
+
+	metrics.RegisterGaugeFunc("rr_outbound_queue_max_depth", "Deepest the outbound queue of each connection has been", func() []metrics.Sample {
+		return pm.outboundSamples(func(stats connections.OutboundStats) int {
+			return stats.MaxQueueDepth
+		})
+	})
+
+	metrics.RegisterGaugeFunc("rr_outbound_queue_capacity", "Messages the outbound queue of each connection can hold before it is disconnected", func() []metrics.Sample {
+		return pm.outboundSamples(func(stats connections.OutboundStats) int {
+			return stats.QueueCapacity
+		})
+	})
+
+	return pm
 }
 
-func NewPlayerManager() *PlayerManager {
-	return &PlayerManager{
-		Players: make(map[int]*RRPlayer),
+func (m *PlayerManager) outboundSamples(value func(stats connections.OutboundStats) int) []metrics.Sample {
+	players := m.GetPlayers()
+	samples := make([]metrics.Sample, 0, len(players))
+
+	for _, player := range players {
+		samples = append(samples, metrics.Sample{
+			Labels: []metrics.Label{{Name: "connection", Value: strconv.Itoa(player.Conn.GetID())}},
+			Value:  float64(value(player.Conn.OutboundStats())),
+		})
 	}
+
+	return samples
 }
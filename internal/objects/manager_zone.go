@@ -1,11 +1,11 @@
 package objects
 
 import (
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/serverconfig"
 	"RainbowRunner/pkg/events"
 	"crypto/md5"
 	"encoding/binary"
-	log "github.com/sirupsen/logrus"
 	"sync"
 )
 
@@ -16,25 +16,40 @@ type ZoneManager struct {
 	Zones map[string]*Zone
 }
 
+// GetOrCreateZone returns the zone with its tick loop running, new zones are initialised on the caller's goroutine
+// before their loop starts
 func (m *ZoneManager) GetOrCreateZone(zoneName string) *Zone {
+	m.Lock()
+	defer m.Unlock()
+
 	if _, ok := m.Zones[zoneName]; !ok {
-		m.CreateZone(zoneName)
-		m.Zones[zoneName].Init()
+		z := m.createZone(zoneName)
+		z.Init()
+		z.Start()
 
-		return m.Zones[zoneName]
+		return z
 	}
 
 	z := m.Zones[zoneName]
 
 	if serverconfig.Config.ReinitialiseZonesOnEnter {
-		z.ClearEntities()
-		z.Init()
+		z.Enqueue(func() {
+			z.ClearEntities()
+			z.Init()
+		})
 	}
 
 	return z
 }
 
 func (m *ZoneManager) CreateZone(name string) *Zone {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.createZone(name)
+}
+
+func (m *ZoneManager) createZone(name string) *Zone {
 	nameHashBytes := md5.Sum([]byte(name))
 	nameHash := binary.LittleEndian.Uint32(nameHashBytes[:])
 
@@ -62,20 +77,17 @@ func (m *ZoneManager) GetZones() []*Zone {
 	return list
 }
 
-func (m *ZoneManager) Tick() {
-	m.RLock()
-	defer m.RUnlock()
-
-	for _, zone := range m.Zones {
-		if !zone.Initialised() {
-			continue
-		}
-
-		err := zone.Tick()
+// Stop stops the tick loop of every zone
+func (m *ZoneManager) Stop() {
+	for _, zone := range m.GetZones() {
+		zone.Stop()
+	}
+}
 
-		if err != nil {
-			log.Error(err)
-		}
+// Close stops every zone and closes their Lua states, used when the server is shutting down
+func (m *ZoneManager) Close() {
+	for _, zone := range m.GetZones() {
+		zone.Close()
 	}
 }
 
@@ -88,5 +100,31 @@ func NewZoneManager() *ZoneManager {
 		event.Zone.OnPlayerEnter(event.Player)
 	})
 
+	metrics.RegisterGaugeFunc("rr_zone_players", "Players in each zone", func() []metrics.Sample {
+		return zm.zoneSamples(func(z *Zone) int {
+			return len(z.Players())
+		})
+	})
+
+	metrics.RegisterGaugeFunc("rr_zone_entities", "Entities spawned in each zone", func() []metrics.Sample {
+		return zm.zoneSamples(func(z *Zone) int {
+			return len(z.Entities())
+		})
+	})
+
 	return zm
 }
+
+func (m *ZoneManager) zoneSamples(count func(z *Zone) int) []metrics.Sample {
+	zones := m.GetZones()
+	samples := make([]metrics.Sample, 0, len(zones))
+
+	for _, zone := range zones {
+		samples = append(samples, metrics.Sample{
+			Labels: []metrics.Label{{Name: "zone", Value: zone.Name}},
+			Value:  float64(count(zone)),
+		})
+	}
+
+	return samples
+}
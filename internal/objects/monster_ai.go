@@ -0,0 +1,387 @@
+package objects
+
+import (
+	"RainbowRunner/internal/actions"
+	"RainbowRunner/internal/global"
+	"RainbowRunner/pkg/datatypes"
+	"math"
+	"math/rand"
+)
+
+type MonsterAIState byte
+
+const (
+	MonsterAIStateIdle MonsterAIState = iota
+	MonsterAIStateWander
+	MonsterAIStateSearchForAttack
+	MonsterAIStateChase
+	MonsterAIStateAttack
+	MonsterAIStateFlee
+	MonsterAIStateReturn
+)
+
+var monsterAIStateNames = [...]string{
+	MonsterAIStateIdle:            "Idle",
+	MonsterAIStateWander:          "Wander",
+	MonsterAIStateSearchForAttack: "SearchForAttack",
+	MonsterAIStateChase:           "Chase",
+	MonsterAIStateAttack:          "Attack",
+	MonsterAIStateFlee:            "Flee",
+	MonsterAIStateReturn:          "Return",
+}
+
+func (s MonsterAIState) String() string {
+	if int(s) < len(monsterAIStateNames) {
+		return monsterAIStateNames[s]
+	}
+
+	return "Unknown"
+}
+
+// Defaults for the MonsterBehavior2Desc ranges, the extracted configs we use don't carry the behavior description
+// properties yet so these are used for every monster
+const (
+	monsterDefaultAggroRange  = 150
+	monsterDefaultLeashRange  = 600
+	monsterDefaultWanderRange = 60
+	monsterDefaultFleeRange   = 200
+)
+
+const (
+	// How often idle and wandering monsters look for players in range
+	monsterScanTicks = 500 / global.TickInterval
+
+	monsterIdleMinTicks = 3000 / global.TickInterval
+	monsterIdleMaxTicks = 8000 / global.TickInterval
+	monsterWanderTicks  = 5000 / global.TickInterval
+	monsterFleeTicks    = 3000 / global.TickInterval
+
+	// The time between attacks at 100% MonsterAttackSpeed
+	monsterAttackTicks = 1500 / global.TickInterval
+
+	// How far a chased target can move before the monster is sent a new MoveTo
+	monsterChaseRepathDistance = 20
+
+	// Monsters flee once when their health drops below this fraction of their max health
+	monsterFleeHealthRatio = 0.2
+)
+
+// monsterTarget is a unit the monster is fighting, the behavior is kept so the position is always current
+type monsterTarget struct {
+	Unit     IUnit
+	Behavior *UnitBehavior
+}
+
+func (t *monsterTarget) ID() uint16 {
+	return uint16(t.Unit.GetUnit().RREntityProperties().ID)
+}
+
+func (t *monsterTarget) Position() datatypes.Vector2Float32 {
+	return t.Behavior.Position.ToVector2Float32()
+}
+
+func (t *monsterTarget) IsValid(zone *Zone) bool {
+	return !t.Unit.GetUnit().IsDead() && t.Behavior.RREntityProperties().Zone == zone
+}
+
+// tickAI runs the monster state machine, transitions are driven by the distance to the home position and the current
+// target, the client is kept in sync by executing the matching behavior actions
+func (m *MonsterBehavior2) tickAI() {
+	zone := m.RREntityProperties().Zone
+
+	if zone == nil || !m.IsHostile() {
+		return
+	}
+
+	unit, ok := m.GCParent.(IUnit)
+
+	if !ok || unit.GetUnit().IsDead() {
+		return
+	}
+
+	if !m.homeSet {
+		m.home = m.Position.ToVector2Float32()
+		m.homeSet = true
+	}
+
+	if m.IsStunned() {
+		return
+	}
+
+	m.stateTicks++
+
+	switch m.state {
+	case MonsterAIStateIdle:
+		if m.scanForTarget(zone) {
+			return
+		}
+
+		if m.stateTicks >= m.idleTicks && m.WanderRange > 0 && m.Speed > 0 {
+			m.setState(MonsterAIStateWander)
+			m.ExecuteAction(&actions.ActionWander{Radius: m.WanderRange})
+		}
+	case MonsterAIStateWander:
+		// The client picks the wander positions itself, the server keeps the home position as the wander radius is
+		// small enough for the range checks not to matter
+		if m.scanForTarget(zone) {
+			return
+		}
+
+		if m.stateTicks >= monsterWanderTicks {
+			m.enterIdle()
+		}
+	case MonsterAIStateSearchForAttack:
+		if !m.target.IsValid(zone) {
+			m.returnHome()
+			return
+		}
+
+		m.ExecuteAction(&actions.ActionSearchForAttack{TargetID: m.target.ID()})
+		m.setState(MonsterAIStateChase)
+		m.chase()
+	case MonsterAIStateChase:
+		if m.shouldDisengage(zone) {
+			m.returnHome()
+			return
+		}
+
+		if m.shouldFlee(unit) {
+			m.flee()
+			return
+		}
+
+		if m.inAttackRange() {
+			m.IsMoving = false
+			m.setState(MonsterAIStateAttack)
+			m.nextAttackTick = global.GetTick()
+			return
+		}
+
+		if m.target.Position().Distance(m.chaseDestination) > monsterChaseRepathDistance {
+			m.chase()
+		}
+	case MonsterAIStateAttack:
+		if m.shouldDisengage(zone) {
+			m.returnHome()
+			return
+		}
+
+		if m.shouldFlee(unit) {
+			m.flee()
+			return
+		}
+
+		if !m.inAttackRange() {
+			m.setState(MonsterAIStateChase)
+			m.chase()
+			return
+		}
+
+		if global.GetTick() >= m.nextAttackTick {
+			m.Attack(m.target.Unit)
+			m.nextAttackTick = global.GetTick() + m.attackInterval()
+		}
+	case MonsterAIStateFlee:
+		if m.stateTicks < monsterFleeTicks && m.IsMoving {
+			return
+		}
+
+		m.IsMoving = false
+
+		if m.shouldDisengage(zone) {
+			m.returnHome()
+			return
+		}
+
+		m.setState(MonsterAIStateChase)
+		m.chase()
+	case MonsterAIStateReturn:
+		if !m.IsMoving {
+			m.hasFled = false
+			m.enterIdle()
+		}
+	}
+}
+
+// OnAttacked makes the monster fight back and alerts nearby monsters of the same creature family
+func (m *MonsterBehavior2) OnAttacked(attacker *UnitBehavior) {
+	target := newMonsterTarget(attacker)
+
+	if target == nil {
+		return
+	}
+
+	m.engage(target)
+
+	zone := m.RREntityProperties().Zone
+
+	if zone == nil || m.CreatureFamily == "" {
+		return
+	}
+
+	for _, entity := range zone.Entities() {
+		npc, ok := entity.(INPC)
+
+		if !ok || npc.GetNPC().IsDead() {
+			continue
+		}
+
+		other, ok := npc.GetNPC().GetChildByGCNativeType("UnitBehavior").(IMonsterBehavior2)
+
+		if !ok || other.GetMonsterBehavior2() == m {
+			continue
+		}
+
+		ally := other.GetMonsterBehavior2()
+
+		if ally.CreatureFamily != m.CreatureFamily || ally.Position.ToVector2Float32().Distance(m.Position.ToVector2Float32()) > float64(ally.AggroRange) {
+			continue
+		}
+
+		ally.engage(target)
+	}
+}
+
+func (m *MonsterBehavior2) State() MonsterAIState {
+	return m.state
+}
+
+// IsHostile monsters without an attack range are townsfolk and vendors that never fight
+func (m *MonsterBehavior2) IsHostile() bool {
+	return m.AttackRange > 0
+}
+
+func (m *MonsterBehavior2) engage(target *monsterTarget) {
+	if !m.IsHostile() || m.state == MonsterAIStateFlee || m.state == MonsterAIStateReturn {
+		return
+	}
+
+	if m.target != nil && (m.state == MonsterAIStateChase || m.state == MonsterAIStateAttack) {
+		return
+	}
+
+	m.target = target
+	m.setState(MonsterAIStateSearchForAttack)
+}
+
+func (m *MonsterBehavior2) scanForTarget(zone *Zone) bool {
+	if m.stateTicks%monsterScanTicks != 0 {
+		return false
+	}
+
+	position := m.Position.ToVector2Float32()
+	closestDistance := float64(m.AggroRange)
+	var closest *monsterTarget
+
+	for _, player := range zone.Players() {
+		if player.CurrentCharacter == nil {
+			continue
+		}
+
+		avatar, ok := player.CurrentCharacter.GetChildByGCNativeType("Avatar").(*Avatar)
+
+		if !ok || !avatar.Spawned || avatar.IsDead() {
+			continue
+		}
+
+		behavior, ok := avatar.GetChildByGCNativeType("UnitBehavior").(IUnitBehavior)
+
+		if !ok {
+			continue
+		}
+
+		distance := behavior.GetUnitBehavior().Position.ToVector2Float32().Distance(position)
+
+		if distance <= closestDistance {
+			closestDistance = distance
+			closest = &monsterTarget{Unit: avatar, Behavior: behavior.GetUnitBehavior()}
+		}
+	}
+
+	if closest == nil {
+		return false
+	}
+
+	m.engage(closest)
+	return true
+}
+
+func (m *MonsterBehavior2) chase() {
+	m.chaseDestination = m.target.Position()
+
+	if m.Speed > 0 {
+		m.MoveTo(m.chaseDestination)
+	}
+}
+
+func (m *MonsterBehavior2) flee() {
+	m.hasFled = true
+	m.setState(MonsterAIStateFlee)
+
+	position := m.Position.ToVector2Float32()
+	direction := position.Sub(m.target.Position()).Normalize()
+
+	// The client moves the unit for the flee action, the server only tracks where it should end up
+	if m.Speed > 0 {
+		m.pathTo(position.Add(direction.Mul(monsterDefaultFleeRange)))
+	}
+
+	m.ExecuteAction(&actions.ActionFlee{TargetID: m.target.ID()})
+}
+
+func (m *MonsterBehavior2) returnHome() {
+	m.target = nil
+	m.setState(MonsterAIStateReturn)
+
+	if m.Speed > 0 {
+		m.MoveTo(m.home)
+	}
+}
+
+func (m *MonsterBehavior2) enterIdle() {
+	m.setState(MonsterAIStateIdle)
+	m.idleTicks = uint(monsterIdleMinTicks + rand.Intn(monsterIdleMaxTicks-monsterIdleMinTicks))
+}
+
+func (m *MonsterBehavior2) setState(state MonsterAIState) {
+	m.state = state
+	m.stateTicks = 0
+}
+
+func (m *MonsterBehavior2) shouldDisengage(zone *Zone) bool {
+	if m.target == nil || !m.target.IsValid(zone) {
+		return true
+	}
+
+	return m.Position.ToVector2Float32().Distance(m.home) > float64(m.LeashRange)
+}
+
+func (m *MonsterBehavior2) shouldFlee(unit IUnit) bool {
+	u := unit.GetUnit()
+
+	return !m.hasFled && u.HP.ToFloat32() <= u.MaxHP.ToFloat32()*monsterFleeHealthRatio
+}
+
+func (m *MonsterBehavior2) inAttackRange() bool {
+	return m.Position.ToVector2Float32().Distance(m.target.Position()) <= float64(m.AttackRange)
+}
+
+func (m *MonsterBehavior2) attackInterval() uint {
+	speed := float64(global.Knobs().MonsterAttackSpeed)
+
+	if speed <= 0 {
+		return monsterAttackTicks
+	}
+
+	return uint(math.Max(1, monsterAttackTicks*100/speed))
+}
+
+func newMonsterTarget(behavior *UnitBehavior) *monsterTarget {
+	unit, ok := behavior.GCParent.(IUnit)
+
+	if !ok {
+		return nil
+	}
+
+	return &monsterTarget{Unit: unit, Behavior: behavior}
+}
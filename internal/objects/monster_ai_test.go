@@ -0,0 +1,296 @@
+package objects
+
+import (
+	"RainbowRunner/internal/global"
+	"RainbowRunner/pkg/datatypes"
+	"RainbowRunner/pkg/datatypes/drfloat"
+	"testing"
+)
+
+// newTestMonster spawns a hostile monster at the zone origin, its home is set on the first tick
+func newTestMonster(z *Zone) *MonsterBehavior2 {
+	npc := NewNPCSimple("test.Monster")
+	npc.MaxHP = drfloat.FromFloat32(100)
+	npc.HP = drfloat.FromFloat32(100)
+
+	behavior := NewMonsterBehavior2("test.MonsterBehavior")
+	behavior.AttackRange = 20
+	behavior.Speed = 100
+	npc.AddChild(behavior)
+
+	z.SpawnEntityWithPosition(npc, datatypes.Vector3Float32{}, 0, nil)
+
+	return behavior
+}
+
+// newTestTarget adds a player to the zone with a spawned avatar at the position, the avatar's behavior is returned so
+// the test can move it
+func newTestTarget(t *testing.T, z *Zone, id int, x float32) *UnitBehavior {
+	rrPlayer, character := newTestPlayer(t, id)
+
+	avatar := NewAvatar("avatar.classes.FighterFemale")
+	avatar.MaxHP = drfloat.FromFloat32(100)
+	avatar.HP = drfloat.FromFloat32(100)
+	avatar.Spawned = true
+
+	behavior := NewUnitBehavior("avatar.base.UnitBehavior")
+	behavior.Position = datatypes.Vector3Float32{X: x}
+	avatar.AddChild(behavior)
+	character.AddChild(avatar)
+
+	z.setZone(avatar)
+	z.AddPlayer(rrPlayer)
+
+	return behavior
+}
+
+// tickMonster runs the state machine until the monster reaches the state, positions only change when the test sets them
+func tickMonster(t *testing.T, m *MonsterBehavior2, state MonsterAIState, maxTicks int) {
+	t.Helper()
+
+	for i := 0; i < maxTicks; i++ {
+		m.tickAI()
+
+		if m.State() == state {
+			return
+		}
+	}
+
+	t.Fatalf("monster is %s after %d ticks, expected %s", m.State(), maxTicks, state)
+}
+
+// chaseTarget brings a new monster into the chase state against a target standing in aggro range
+func chaseTarget(t *testing.T, z *Zone, id int) (*MonsterBehavior2, *UnitBehavior) {
+	m := newTestMonster(z)
+	target := newTestTarget(t, z, id, 100)
+
+	tickMonster(t, m, MonsterAIStateSearchForAttack, monsterScanTicks)
+	tickMonster(t, m, MonsterAIStateChase, 1)
+
+	return m, target
+}
+
+func TestMonsterAggro(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance float32
+		dead     bool
+		hidden   bool
+		aggro    bool
+	}{
+		{name: "in range", distance: 100, aggro: true},
+		{name: "edge of range", distance: monsterDefaultAggroRange, aggro: true},
+		{name: "out of range", distance: monsterDefaultAggroRange + 1},
+		{name: "dead", distance: 100, dead: true},
+		{name: "not spawned", distance: 100, hidden: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			z := NewZone("test_zone_aggro", 1)
+			m := newTestMonster(z)
+			target := newTestTarget(t, z, 0x7101, test.distance)
+			avatar := target.GCParent.(*Avatar)
+
+			if test.dead {
+				avatar.HP = 0
+			}
+
+			avatar.Spawned = !test.hidden
+
+			// Idle monsters only look for players every few ticks
+			for i := 1; i < monsterScanTicks; i++ {
+				m.tickAI()
+			}
+
+			if m.State() != MonsterAIStateIdle {
+				t.Fatalf("monster is %s before it scanned for players", m.State())
+			}
+
+			m.tickAI()
+
+			if !test.aggro {
+				if m.State() != MonsterAIStateIdle || m.target != nil {
+					t.Errorf("monster is %s with target %v, expected it to stay idle", m.State(), m.target)
+				}
+
+				return
+			}
+
+			if m.State() != MonsterAIStateSearchForAttack || m.target.Behavior != target {
+				t.Fatalf("monster is %s, expected it to search for the player", m.State())
+			}
+
+			// The monster heads for where the target is standing
+			m.tickAI()
+
+			if m.State() != MonsterAIStateChase || !m.IsMoving || m.targetPosition != target.Position.ToVector2Float32() {
+				t.Errorf("monster is %s moving %v to %v, expected it to chase the player", m.State(), m.IsMoving, m.targetPosition)
+			}
+		})
+	}
+}
+
+func TestMonsterChaseAndAttack(t *testing.T) {
+	z := NewZone("test_zone_chase", 1)
+	m, target := chaseTarget(t, z, 0x7102)
+
+	// A target that moves a little isn't chased again
+	target.Position.X = 110
+	m.tickAI()
+
+	if m.State() != MonsterAIStateChase || m.targetPosition.X != 100 {
+		t.Fatalf("monster is %s moving to %v after the target moved a little", m.State(), m.targetPosition)
+	}
+
+	target.Position.X = 150
+	m.tickAI()
+
+	if m.targetPosition.X != 150 {
+		t.Fatalf("monster is moving to %v, expected it to follow the target", m.targetPosition)
+	}
+
+	// Reaching the target stops the monster and it attacks straight away
+	m.Position.X = 140
+	m.tickAI()
+
+	if m.State() != MonsterAIStateAttack || m.IsMoving {
+		t.Fatalf("monster is %s moving %v in attack range", m.State(), m.IsMoving)
+	}
+
+	m.tickAI()
+
+	attackTick := m.nextAttackTick
+
+	if attackTick != global.GetTick()+m.attackInterval() {
+		t.Fatalf("monster did not attack, next attack at %d", attackTick)
+	}
+
+	// The next attack waits for the attack interval
+	m.tickAI()
+
+	if m.nextAttackTick != attackTick {
+		t.Errorf("monster attacked again before tick %d", attackTick)
+	}
+
+	// The monster chases a target that steps out of range
+	target.Position.X = 200
+	m.tickAI()
+
+	if m.State() != MonsterAIStateChase || !m.IsMoving || m.targetPosition.X != 200 {
+		t.Errorf("monster is %s moving %v to %v after the target left attack range", m.State(), m.IsMoving, m.targetPosition)
+	}
+}
+
+func TestMonsterReturnsHome(t *testing.T) {
+	tests := []struct {
+		name  string
+		leave func(m *MonsterBehavior2, target *UnitBehavior)
+	}{
+		{name: "leashed", leave: func(m *MonsterBehavior2, target *UnitBehavior) {
+			m.Position.X = monsterDefaultLeashRange + 1
+			target.Position.X = monsterDefaultLeashRange + 10
+		}},
+		{name: "target died", leave: func(m *MonsterBehavior2, target *UnitBehavior) {
+			target.GCParent.(*Avatar).HP = 0
+		}},
+		{name: "target left the zone", leave: func(m *MonsterBehavior2, target *UnitBehavior) {
+			NewZone("test_zone_other", 2).setZone(target.GCParent)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			z := NewZone("test_zone_return", 1)
+			m, target := chaseTarget(t, z, 0x7103)
+
+			m.Position.X = 50
+			test.leave(m, target)
+			m.tickAI()
+
+			if m.State() != MonsterAIStateReturn || m.target != nil {
+				t.Fatalf("monster is %s with target %v, expected it to return home", m.State(), m.target)
+			}
+
+			if !m.IsMoving || m.targetPosition != (datatypes.Vector2Float32{}) {
+				t.Fatalf("monster is moving %v to %v, expected it to move home", m.IsMoving, m.targetPosition)
+			}
+
+			// Returning monsters can't be pulled back into the fight
+			m.OnAttacked(target)
+
+			if m.State() != MonsterAIStateReturn {
+				t.Fatalf("monster is %s after being attacked on the way home", m.State())
+			}
+
+			m.tickAI()
+
+			if m.State() != MonsterAIStateReturn {
+				t.Fatalf("monster is %s before it got home", m.State())
+			}
+
+			m.Position = datatypes.Vector3Float32{}
+			m.IsMoving = false
+			m.tickAI()
+
+			if m.State() != MonsterAIStateIdle {
+				t.Errorf("monster is %s after getting home, expected it to be idle", m.State())
+			}
+		})
+	}
+}
+
+func TestMonsterFlees(t *testing.T) {
+	z := NewZone("test_zone_flee", 1)
+	m, _ := chaseTarget(t, z, 0x7104)
+
+	m.GCParent.(IUnit).GetUnit().HP = drfloat.FromFloat32(10)
+	m.tickAI()
+
+	// The monster runs directly away from the target
+	if m.State() != MonsterAIStateFlee || !m.IsMoving || m.targetPosition.X != -monsterDefaultFleeRange {
+		t.Fatalf("monster is %s moving %v to %v at low health", m.State(), m.IsMoving, m.targetPosition)
+	}
+
+	m.IsMoving = false
+	m.tickAI()
+
+	// Monsters only flee once
+	if m.State() != MonsterAIStateChase {
+		t.Fatalf("monster is %s after fleeing, expected it to chase again", m.State())
+	}
+
+	m.tickAI()
+
+	if m.State() != MonsterAIStateChase {
+		t.Errorf("monster is %s, expected it to keep chasing", m.State())
+	}
+}
+
+func TestMonsterWanders(t *testing.T) {
+	z := NewZone("test_zone_wander", 1)
+	m := newTestMonster(z)
+
+	tickMonster(t, m, MonsterAIStateWander, monsterIdleMaxTicks)
+
+	if m.stateTicks != 0 {
+		t.Fatal("wander state was not started")
+	}
+
+	tickMonster(t, m, MonsterAIStateIdle, monsterWanderTicks)
+
+	if m.idleTicks < monsterIdleMinTicks || m.idleTicks >= monsterIdleMaxTicks {
+		t.Errorf("monster will idle for %d ticks", m.idleTicks)
+	}
+
+	// Monsters that can't move stay where they are
+	m.Speed = 0
+
+	for i := 0; i < monsterIdleMaxTicks; i++ {
+		m.tickAI()
+
+		if m.State() != MonsterAIStateIdle {
+			t.Fatalf("monster that can't move is %s", m.State())
+		}
+	}
+}
@@ -3,7 +3,13 @@ package objects
 import (
 	"RainbowRunner/internal/connections"
 	"RainbowRunner/internal/message"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/serverconfig"
+	"encoding/hex"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"sync/atomic"
 )
 
 //go:generate go run ../../scripts/generatelua -type=RRPlayer
@@ -15,6 +21,10 @@ type RRPlayer struct {
 	MessageQueue       *message.Queue
 
 	debugOptions *RRPlayerDebugOptions
+
+	// The zone whose goroutine handles this player, it is changed as soon as the player starts moving zones so anything
+	// the client sends afterwards is queued behind the join
+	ownerZone atomic.Pointer[Zone]
 }
 
 //go:generate go run ../../scripts/generatelua -type=RRPlayerDebugOptions
@@ -39,6 +49,103 @@ func (p *RRPlayer) Zone() *Zone {
 	return p.CurrentCharacter.Zone
 }
 
+func (p *RRPlayer) OwnerZone() *Zone {
+	return p.ownerZone.Load()
+}
+
+func (p *RRPlayer) SetOwnerZone(zone *Zone) {
+	p.ownerZone.Store(zone)
+}
+
+// Run runs the job on the goroutine of the zone that owns the player, or straight away if they are not in a zone yet.
+// Jobs that were queued before the player moved zones are passed on to the new zone.
+func (p *RRPlayer) Run(job func()) {
+	zone := p.OwnerZone()
+
+	if zone == nil || !zone.IsRunning() {
+		job()
+		return
+	}
+
+	zone.Enqueue(func() {
+		if p.OwnerZone() != zone {
+			p.Run(job)
+			return
+		}
+
+		job()
+	})
+}
+
+// FlushMessages sends everything queued for the client this tick as one client entity stream, it must run on the
+// goroutine of the zone that owns the player
+func (p *RRPlayer) FlushMessages() {
+	if p.CurrentCharacter == nil {
+		return
+	}
+
+	if !p.CurrentCharacter.Spawned {
+		p.MessageQueue.Clear(message.QueueTypeClientEntity)
+		return
+	}
+
+	clientEntitySend := false
+	clientEntityWriter := p.ClientEntityWriter
+
+	clientEntityWriter.Clear()
+	clientEntityWriter.BeginStream()
+
+	for !p.MessageQueue.IsEmpty(message.QueueTypeClientEntity) {
+		item := p.MessageQueue.Dequeue(message.QueueTypeClientEntity)
+		clientEntityWriter.Body.Write(item.Data)
+		metrics.MessagesSent.Inc(item.OpType.String())
+
+		if serverconfig.Config.Logging.LogFilterMessages {
+			if logIt, ok := serverconfig.Config.Logging.LogSentMessageTypes[strings.ToLower(item.OpType.String())]; ok && logIt {
+				log.Info(fmt.Sprintf("Sent Message:\n%s", hex.Dump(item.Data.Data())))
+			}
+		}
+
+		clientEntitySend = true
+	}
+
+	clientEntityWriter.EndStream()
+
+	if clientEntitySend {
+		connections.WriteCompressedASimple(p.Conn, clientEntityWriter.Body)
+	}
+}
+
+// GetCharacter returns the character in the slot on the character select screen, or nil if the slot is empty
+func (p *RRPlayer) GetCharacter(slot int) *Player {
+	if slot < 0 || slot >= len(p.Characters) {
+		return nil
+	}
+
+	return p.Characters[slot]
+}
+
+// GetCharacterByName finds one of the account's characters, names are not case-sensitive
+func (p *RRPlayer) GetCharacterByName(name string) *Player {
+	for _, character := range p.Characters {
+		if strings.EqualFold(character.Name, name) {
+			return character
+		}
+	}
+
+	return nil
+}
+
+// RemoveCharacter takes the character off the character select screen, the other characters keep their order
+func (p *RRPlayer) RemoveCharacter(character *Player) {
+	for i, c := range p.Characters {
+		if c == character {
+			p.Characters = append(p.Characters[:i], p.Characters[i+1:]...)
+			return
+		}
+	}
+}
+
 func NewRRPlayer(rrconn *connections.RRConn, cewriter *ClientEntityWriter, queue *message.Queue) *RRPlayer {
 	defaultSendMovement := serverconfig.Config.SendMovementMessages
 
@@ -4,8 +4,10 @@ import (
 	"RainbowRunner/internal/connections"
 	"RainbowRunner/internal/database"
 	"RainbowRunner/internal/game/messages"
+	"RainbowRunner/internal/global"
 	"RainbowRunner/internal/lua"
 	"RainbowRunner/internal/message"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/pathfinding"
 	script2 "RainbowRunner/internal/script"
 	"RainbowRunner/internal/serverconfig"
@@ -13,10 +15,12 @@ import (
 	"RainbowRunner/internal/types/drobjecttypes"
 	"RainbowRunner/pkg/byter"
 	"RainbowRunner/pkg/datatypes"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"strings"
 	"sync"
+	"time"
 )
 
 //go:generate go run ../../scripts/generatelua -type=Zone
@@ -31,8 +35,19 @@ type Zone struct {
 
 	BaseConfig  *database.ZoneConfig
 	PathMap     *types.PathMap
+	PathManager *pathfinding.PathManager
 	ID          uint32
 	initialised bool
+
+	interest *zoneInterest
+	ids      *entityIDAllocator
+	ticks    uint
+
+	// Work from other goroutines that changes the zone, see Start
+	jobs     global.TickJobQueue
+	loopLock sync.Mutex
+	stop     chan struct{}
+	stopped  chan struct{}
 }
 
 func (z *Zone) Initialised() bool {
@@ -69,6 +84,7 @@ func (z *Zone) RemovePlayer(id int) {
 	z.Lock()
 
 	delete(z.players, uint16(id))
+	z.interest.forgetPlayer(uint16(id))
 
 	toDelete := make([]uint16, 0, 1024)
 
@@ -87,6 +103,8 @@ func (z *Zone) RemovePlayer(id int) {
 		delete(z.entities, index)
 		z.Unlock()
 
+		z.releaseIDs(entity)
+
 		if player, ok := entity.(IPlayer); ok {
 			avatar := player.GetPlayer().GetChildByGCNativeType("Avatar")
 
@@ -113,43 +131,98 @@ func (z *Zone) Despawn(entity drobjecttypes.DRObject) {
 
 	z.Unlock()
 
+	z.releaseIDs(entity)
 	z.OnEntityDespawned(entity)
 }
 
+// releaseIDs frees the IDs of the entity and all of its children, the objects keep their IDs so they can reserve them
+// again if they are spawned back into the zone after the quarantine. They are no longer in the zone so children added
+// to them later are not indexed here.
+func (z *Zone) releaseIDs(entity drobjecttypes.DRObject) {
+	release := func(object drobjecttypes.DRObject) {
+		props := object.(IRREntityPropertiesHaver).GetRREntityProperties()
+		z.ids.Release(uint16(props.ID), object)
+
+		if props.Zone == z {
+			props.Zone = nil
+		}
+	}
+
+	release(entity)
+	entity.WalkChildren(release)
+}
+
+// giveIDs gives the entity and all of its children an ID in the zone, if any of them can't be given one the IDs that
+// were given are released again
+func (z *Zone) giveIDs(entity drobjecttypes.DRObject) error {
+	err := z.GiveID(entity)
+
+	entity.WalkChildren(func(object drobjecttypes.DRObject) {
+		if err == nil {
+			err = z.GiveID(object)
+		}
+	})
+
+	if err != nil {
+		z.releaseIDs(entity)
+	}
+
+	return err
+}
+
+// SpawnEntity adds the entity to the zone, the spawn is refused if the entity or any of its children can't be given
+// an ID
 func (z *Zone) SpawnEntity(owner *uint16, entity drobjecttypes.DRObject) {
-	//z.Lock()
+	if err := z.giveIDs(entity); err != nil {
+		log.Errorf("could not spawn %s in zone %s: %s", entity.GetGCType(), z.Name, err.Error())
+		return
+	}
 
 	z.setZone(entity)
-	z.GiveID(entity)
 
 	if owner != nil {
 		entity.(IRREntityPropertiesHaver).GetRREntityProperties().SetOwner(*owner)
-	}
-
-	entity.WalkChildren(func(object drobjecttypes.DRObject) {
-		z.GiveID(object)
-		z.setZone(object)
 
-		if owner != nil {
+		entity.WalkChildren(func(object drobjecttypes.DRObject) {
 			object.(IRREntityPropertiesHaver).GetRREntityProperties().SetOwner(*owner)
-		}
-	})
+		})
+	}
 
 	id := uint16(entity.(IRREntityPropertiesHaver).GetRREntityProperties().ID)
 
-	if _, ok := z.entities[id]; ok {
+	// Other goroutines such as the metrics gauges read the entities while the zone ticks
+	z.Lock()
+
+	if existing, ok := z.entities[id]; ok {
+		z.Unlock()
+
+		if existing != entity {
+			log.Errorf("could not spawn %s in zone %s, ID %d is used by %s", entity.GetGCType(), z.Name, id, existing.GetGCType())
+		}
+
 		return
 	}
 
 	z.entities[id] = entity
 
-	//z.Unlock()
+	z.Unlock()
 
 	entity.Init()
 
 	z.OnEntitySpawned(entity)
 }
 
+// adoptChild indexes an object added to something that is already in the zone, such as a looted item, so it can be
+// found by ID
+func (z *Zone) adoptChild(child drobjecttypes.DRObject) {
+	if err := z.giveIDs(child); err != nil {
+		log.Errorf("could not add %s to zone %s: %s", child.GetGCType(), z.Name, err.Error())
+		return
+	}
+
+	z.setZone(child)
+}
+
 func (z *Zone) AddPlayer(player *RRPlayer) {
 	z.Lock()
 	z.players[uint16(player.Conn.GetID())] = player
@@ -326,27 +399,80 @@ func (z *Zone) ClearEntities() {
 	defer z.Unlock()
 
 	z.entities = make(map[uint16]drobjecttypes.DRObject)
+	z.interest = newZoneInterest()
+	z.ids.ReleaseAll()
 }
 
 func (z *Zone) ReloadPathMap() {
 	z.PathMap = pathfinding.ReloadPathMap(z.Name)
+	z.PathManager = nil
+
+	if z.PathMap != nil {
+		z.PathManager = pathfinding.NewPathManager(z.PathMap)
+	}
 }
 
 func (z *Zone) Tick() error {
+	return z.tick(nil)
+}
+
+// tick records each phase on the timer, the timer is nil when the zone is ticked from Lua
+func (z *Zone) tick(timer *metrics.TickTimer) error {
+	if z.PathManager != nil {
+		z.PathManager.Update()
+	}
+
+	timer.Phase("paths")
+
 	es := z.Entities()
 
 	for _, entity := range es {
 		if entity == nil {
 			continue
 		}
-		entity.Tick()
+
+		start := time.Now()
+		z.tickEntity(entity)
+		timer.Detail(describeTickEntity(entity), time.Since(start))
 	}
 
+	timer.Phase("entities")
+
+	if z.ticks%interestUpdateTicks == 0 {
+		z.updateInterest()
+	}
+
+	z.ticks++
+
+	timer.Phase("interest")
+
 	err := z.Scripts.Tick()
 
+	timer.Phase("lua")
+
 	return err
 }
 
+// tickEntity recovers a panic so one broken entity doesn't stop the rest of the zone from ticking
+func (z *Zone) tickEntity(entity drobjecttypes.DRObject) {
+	defer z.recoverPanic(describeTickEntity(entity) + " tick")
+
+	entity.Tick()
+}
+
+// describeTickEntity names an entity in the slow tick log, including its script as that is usually the slow part
+func describeTickEntity(entity drobjecttypes.DRObject) string {
+	name := fmt.Sprintf("%s(%d)", entity.GetGCType(), entityID(entity))
+
+	if worldEntity, ok := entity.(IWorldEntity); ok {
+		if scriptName := worldEntity.GetWorldEntity().ScriptName(); scriptName != "" {
+			name += "[" + scriptName + "]"
+		}
+	}
+
+	return name
+}
+
 func (z *Zone) FindEntityByGCTypeName(name string) drobjecttypes.DRObject {
 	for _, entity := range z.Entities() {
 		if entity == nil {
@@ -376,40 +502,31 @@ func (z *Zone) FindEntityByName(name string) drobjecttypes.DRObject {
 	return nil
 }
 
+// FindEntityByID finds any object in the zone by ID, including components and children of entities
 func (z *Zone) FindEntityByID(id uint16) drobjecttypes.DRObject {
-	z.RLock()
-	defer z.RUnlock()
-	for _, entity := range z.entities {
-		if entity.(IRREntityPropertiesHaver).GetRREntityProperties().ID == uint32(id) {
-			return entity
-		}
+	return z.ids.Find(id)
+}
 
-		var foundEntity drobjecttypes.DRObject = nil
+// GiveID indexes the object by its ID, objects without one or whose ID is already taken in this zone are given a new
+// one. An error is returned when the zone has run out of IDs and the object is left without one in the zone.
+func (z *Zone) GiveID(entity drobjecttypes.DRObject) error {
+	eProps := entity.(IRREntityPropertiesHaver).GetRREntityProperties()
 
-		entity.WalkChildren(func(object drobjecttypes.DRObject) {
-			// TODO optimise this, no need to loop all children when found
-			if object.(IRREntityPropertiesHaver).GetRREntityProperties().ID == uint32(id) {
-				foundEntity = object
-			}
-		})
+	if eProps.ID == 0 || !z.ids.Reserve(uint16(eProps.ID), entity) {
+		id, ok := z.ids.Allocate(entity)
 
-		if foundEntity != nil {
-			return foundEntity
+		if !ok {
+			return errors.New(fmt.Sprintf("zone %s has run out of entity IDs", z.Name))
 		}
-	}
-	return nil
-}
 
-func (z *Zone) GiveID(entity drobjecttypes.DRObject) {
-	eProps := entity.(IRREntityPropertiesHaver).GetRREntityProperties()
-
-	if eProps.ID == 0 {
-		eProps.ID = uint32(NewID())
+		eProps.ID = uint32(id)
 	}
 
 	if serverconfig.Config.Logging.LogIDs {
 		fmt.Printf("%d - %s(%s)\n", eProps.ID, entity.(IGCObject).GetGCObject().GCType, entity.(IGCObject).GetGCObject().GCLabel)
 	}
+
+	return nil
 }
 
 // OnPlayerEnter is called when a player enters the zone from the game client and requires the initial zone state
@@ -430,6 +547,8 @@ func (z *Zone) OnPlayerEnter(player *Player) {
 
 	z.Scripts.OnPlayerEnter(player)
 
+	player.restoreSavedPosition(z)
+
 	avatar := player.GetChildByGCNativeType("Avatar").(*Avatar)
 
 	avatar.SendFollowClient()
@@ -441,23 +560,14 @@ func (z *Zone) OnPlayerEnter(player *Player) {
 }
 
 // TODO batch entity spawn events
+// OnEntitySpawned only creates the entity for players that can see it, the rest are sent it by updateInterest once
+// it comes into view
 func (z *Zone) OnEntitySpawned(entity drobjecttypes.DRObject) {
-	z.NotifyPlayers(types.Pointer(entity.OwnerID()), func() *byter.Byter {
-		CEWriter := NewClientEntityWriterWithByter()
-
-		WriteCreateExistingEntity(entity, CEWriter)
-		return CEWriter.Body
-	})
+	z.onInterestEntitySpawned(entity)
 }
 
 func (z *Zone) OnEntityDespawned(entity drobjecttypes.DRObject) {
-	z.NotifyPlayers(types.Pointer(entity.OwnerID()), func() *byter.Byter {
-		CEWriter := NewClientEntityWriterWithByter()
-
-		CEWriter.Remove(entity)
-
-		return CEWriter.Body
-	})
+	z.onInterestEntityDespawned(entity)
 }
 
 func (z *Zone) NotifyPlayers(excludeID *uint16, f func() *byter.Byter) {
@@ -515,6 +625,8 @@ func NewZone(name string, id uint32) *Zone {
 		ID:       id,
 		entities: make(map[uint16]drobjecttypes.DRObject),
 		players:  make(map[uint16]*RRPlayer),
+		interest: newZoneInterest(),
+		ids:      newEntityIDAllocator(),
 	}
 
 	return zone
@@ -0,0 +1,123 @@
+package objects
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindEntityByIDFindsChildrenAddedAfterSpawn(t *testing.T) {
+	z := NewZone("test_zone_ids", 1)
+
+	waypoint := NewWaypoint("test.Waypoint")
+	z.SpawnEntity(nil, waypoint)
+
+	if z.FindEntityByID(uint16(waypoint.ID())) != waypoint {
+		t.Fatal("spawned entity is not found by its ID")
+	}
+
+	child := NewGCObject("TestChild")
+	child.EntityProperties.ID = 0x1234
+	waypoint.AddChild(child)
+
+	// The child is indexed when it is added, not when it is first looked up
+	if z.ids.Find(0x1234) != child {
+		t.Fatal("child was not added to the ID index")
+	}
+
+	if found := z.FindEntityByID(0x1234); found != child {
+		t.Fatalf("found %v, expected the child added after spawn", found)
+	}
+
+	z.Despawn(waypoint)
+
+	if z.FindEntityByID(0x1234) != nil {
+		t.Error("child is still found after its entity was despawned")
+	}
+
+	// Children added after the despawn don't go into the zone the entity left
+	late := NewGCObject("TestChild")
+	waypoint.AddChild(late)
+
+	if late.EntityProperties.ID != 0 || late.EntityProperties.Zone != nil {
+		t.Error("child added after despawn was indexed in the zone")
+	}
+}
+
+func TestInventoryItemsAreIndexedWhenAdded(t *testing.T) {
+	z := NewZone("test_zone_inventory_ids", 1)
+
+	waypoint := NewWaypoint("test.Waypoint")
+	inventory := newTestInventory(4, 4)
+	waypoint.AddChild(inventory)
+	z.SpawnEntity(nil, waypoint)
+
+	if z.FindEntityByID(uint16(inventory.ID())) != inventory {
+		t.Fatal("inventory is not found by its ID")
+	}
+
+	item := newTestItem(t, 1, 1)
+
+	if err := inventory.AddItem(item); err != nil {
+		t.Fatal(err)
+	}
+
+	if item.ID() == 0 || z.FindEntityByID(uint16(item.ID())) != item {
+		t.Fatalf("item %d added after spawn is not found by its ID", item.ID())
+	}
+
+	if item.EntityProperties.Zone != z {
+		t.Error("item added after spawn is not in the zone")
+	}
+}
+
+func TestSpawnEntityRefusedWithoutIDs(t *testing.T) {
+	z := NewZone("test_zone_no_ids", 1)
+
+	// Only the last ID is left, enough for the waypoint but not its child
+	z.ids.next = math.MaxUint16
+
+	waypoint := NewWaypoint("test.Waypoint")
+	waypoint.AddChild(NewGCObject("TestChild"))
+
+	z.SpawnEntity(nil, waypoint)
+
+	if len(z.Entities()) != 0 {
+		t.Fatal("entity was spawned without IDs for all of its children")
+	}
+
+	if waypoint.EntityProperties.Zone != nil {
+		t.Error("refused entity was put in the zone")
+	}
+
+	if z.FindEntityByID(math.MaxUint16) != nil {
+		t.Error("the ID given to the refused entity was not released")
+	}
+
+	if err := z.GiveID(NewGCObject("TestChild")); err == nil {
+		t.Error("expected the zone to have run out of IDs")
+	}
+}
+
+func TestSpawnEntityRefusesTakenID(t *testing.T) {
+	z := NewZone("test_zone_taken_id", 1)
+
+	first := NewWaypoint("test.Waypoint")
+	z.SpawnEntity(nil, first)
+
+	// The second entity is renumbered instead of being dropped because its ID is taken
+	second := NewWaypoint("test.Waypoint")
+	second.EntityProperties.ID = first.EntityProperties.ID
+	z.SpawnEntity(nil, second)
+
+	if second.ID() == first.ID() {
+		t.Fatal("entity kept an ID already used in the zone")
+	}
+
+	if z.FindEntityByID(uint16(first.ID())) != first || z.FindEntityByID(uint16(second.ID())) != second {
+		t.Error("both entities are not found by their own IDs")
+	}
+
+	if len(z.Entities()) != 2 {
+		t.Errorf("zone has %d entities, expected 2", len(z.Entities()))
+	}
+}
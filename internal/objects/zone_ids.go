@@ -0,0 +1,154 @@
+package objects
+
+import (
+	"RainbowRunner/internal/types/drobjecttypes"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// IDs below this are never handed out, the client treats some of the low IDs specially
+	firstEntityID = 10
+
+	// Freed IDs are kept out of use for this long so messages the client sent for the old entity can't reach the
+	// entity that reuses the ID
+	entityIDQuarantine = 30 * time.Second
+)
+
+type releasedID struct {
+	ID         uint16
+	ReleasedAt time.Time
+}
+
+// entityIDAllocator hands out the entity IDs for a single zone, the client only ever sees one zone so IDs only need to
+// be unique within it. It also indexes every object in the zone by ID, including components and children.
+type entityIDAllocator struct {
+	sync.Mutex
+
+	// The next ID that has never been handed out
+	next uint32
+
+	// Released IDs in the order they were released, the front is the first to leave quarantine. IDs that were
+	// reserved again while waiting are skipped when they reach the front.
+	released   []releasedID
+	releasedAt map[uint16]time.Time
+
+	objects map[uint16]drobjecttypes.DRObject
+}
+
+// Allocate returns false when every ID is in use or in quarantine
+func (a *entityIDAllocator) Allocate(object drobjecttypes.DRObject) (uint16, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	for a.next <= math.MaxUint16 {
+		id := uint16(a.next)
+		a.next++
+
+		if _, ok := a.objects[id]; ok {
+			continue
+		}
+
+		if _, ok := a.releasedAt[id]; ok {
+			continue
+		}
+
+		a.objects[id] = object
+		return id, true
+	}
+
+	for len(a.released) > 0 {
+		released := a.released[0]
+
+		if releasedAt, ok := a.releasedAt[released.ID]; !ok || !releasedAt.Equal(released.ReleasedAt) {
+			a.released = a.released[1:]
+			continue
+		}
+
+		if time.Since(released.ReleasedAt) < entityIDQuarantine {
+			break
+		}
+
+		a.released = a.released[1:]
+		delete(a.releasedAt, released.ID)
+		a.objects[released.ID] = object
+		return released.ID, true
+	}
+
+	return 0, false
+}
+
+// Reserve indexes an object that already has an ID, objects that were given an ID outside the zone keep it as long as
+// it isn't used by anything else in the zone
+func (a *entityIDAllocator) Reserve(id uint16, object drobjecttypes.DRObject) bool {
+	a.Lock()
+	defer a.Unlock()
+
+	if existing, ok := a.objects[id]; ok {
+		return existing == object
+	}
+
+	if id < firstEntityID {
+		return false
+	}
+
+	if releasedAt, ok := a.releasedAt[id]; ok {
+		if time.Since(releasedAt) < entityIDQuarantine {
+			return false
+		}
+
+		delete(a.releasedAt, id)
+	}
+
+	a.objects[id] = object
+	return true
+}
+
+// Release frees the ID if it is held by the object, an object that never got its ID in this zone can't free the ID of
+// whatever else has it
+func (a *entityIDAllocator) Release(id uint16, object drobjecttypes.DRObject) {
+	a.Lock()
+	defer a.Unlock()
+
+	if existing, ok := a.objects[id]; !ok || existing != object {
+		return
+	}
+
+	delete(a.objects, id)
+	a.release(id, time.Now())
+}
+
+func (a *entityIDAllocator) release(id uint16, now time.Time) {
+	a.released = append(a.released, releasedID{ID: id, ReleasedAt: now})
+	a.releasedAt[id] = now
+}
+
+func (a *entityIDAllocator) Find(id uint16) drobjecttypes.DRObject {
+	a.Lock()
+	defer a.Unlock()
+
+	return a.objects[id]
+}
+
+// ReleaseAll frees every ID, they still go through quarantine as the client may not have removed the entities yet
+func (a *entityIDAllocator) ReleaseAll() {
+	a.Lock()
+	defer a.Unlock()
+
+	now := time.Now()
+
+	for id := range a.objects {
+		a.release(id, now)
+	}
+
+	a.objects = make(map[uint16]drobjecttypes.DRObject)
+}
+
+func newEntityIDAllocator() *entityIDAllocator {
+	return &entityIDAllocator{
+		next:       firstEntityID,
+		releasedAt: make(map[uint16]time.Time),
+		objects:    make(map[uint16]drobjecttypes.DRObject),
+	}
+}
@@ -0,0 +1,416 @@
+package objects
+
+import (
+	"RainbowRunner/internal/connections"
+	"RainbowRunner/internal/global"
+	"RainbowRunner/internal/message"
+	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/types"
+	"RainbowRunner/internal/types/drobjecttypes"
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes"
+	"math"
+	"sync"
+)
+
+const (
+	// Entities are bucketed into square cells so only the cells around a player are checked
+	interestCellSize = 200
+
+	// Used when `zone_options.view_radius` isn't set
+	defaultViewRadius = 500
+
+	// Entities are only removed once they are this much further away than the view radius so units moving along the
+	// edge aren't created and removed over and over
+	interestRemoveMargin = 100
+
+	// How often the known entities of each player are refreshed
+	interestUpdateTicks = 300 / global.TickInterval
+)
+
+// zoneInterest tracks the top level entities of a zone on a spatial grid and which of them each player has been sent
+type zoneInterest struct {
+	sync.Mutex
+
+	cells     map[datatypes.Vector2]map[uint16]drobjecttypes.DRObject
+	positions map[uint16]datatypes.Vector2Float32
+
+	// Entity IDs each player has been sent a create for, keyed by connection ID
+	known map[uint16]map[uint16]bool
+}
+
+func (i *zoneInterest) place(id uint16, entity drobjecttypes.DRObject, position datatypes.Vector2Float32) {
+	i.Lock()
+	defer i.Unlock()
+
+	cell := interestCell(position)
+
+	if oldPosition, ok := i.positions[id]; ok {
+		oldCell := interestCell(oldPosition)
+
+		if oldCell != cell {
+			delete(i.cells[oldCell], id)
+
+			if len(i.cells[oldCell]) == 0 {
+				delete(i.cells, oldCell)
+			}
+		}
+	}
+
+	if _, ok := i.cells[cell]; !ok {
+		i.cells[cell] = make(map[uint16]drobjecttypes.DRObject)
+	}
+
+	i.cells[cell][id] = entity
+	i.positions[id] = position
+}
+
+func (i *zoneInterest) remove(id uint16) {
+	i.Lock()
+	defer i.Unlock()
+
+	if position, ok := i.positions[id]; ok {
+		cell := interestCell(position)
+		delete(i.cells[cell], id)
+
+		if len(i.cells[cell]) == 0 {
+			delete(i.cells, cell)
+		}
+
+		delete(i.positions, id)
+	}
+
+	for _, known := range i.known {
+		delete(known, id)
+	}
+}
+
+func (i *zoneInterest) nearby(position datatypes.Vector2Float32, radius float64) []drobjecttypes.DRObject {
+	i.Lock()
+	defer i.Unlock()
+
+	entities := make([]drobjecttypes.DRObject, 0)
+	min := interestCell(position.Sub(datatypes.Vector2Float32{X: float32(radius), Y: float32(radius)}))
+	max := interestCell(position.Add(datatypes.Vector2Float32{X: float32(radius), Y: float32(radius)}))
+
+	for x := min.X; x <= max.X; x++ {
+		for y := min.Y; y <= max.Y; y++ {
+			for id, entity := range i.cells[datatypes.Vector2{X: x, Y: y}] {
+				if i.positions[id].Distance(position) <= radius {
+					entities = append(entities, entity)
+				}
+			}
+		}
+	}
+
+	return entities
+}
+
+func (i *zoneInterest) position(id uint16) (datatypes.Vector2Float32, bool) {
+	i.Lock()
+	defer i.Unlock()
+
+	position, ok := i.positions[id]
+
+	return position, ok
+}
+
+func (i *zoneInterest) knows(playerID uint16, id uint16) bool {
+	i.Lock()
+	defer i.Unlock()
+
+	return i.known[playerID][id]
+}
+
+// markKnown returns false if the player already knew about the entity
+func (i *zoneInterest) markKnown(playerID uint16, id uint16) bool {
+	i.Lock()
+	defer i.Unlock()
+
+	if _, ok := i.known[playerID]; !ok {
+		i.known[playerID] = make(map[uint16]bool)
+	}
+
+	if i.known[playerID][id] {
+		return false
+	}
+
+	i.known[playerID][id] = true
+	return true
+}
+
+func (i *zoneInterest) forget(playerID uint16, id uint16) {
+	i.Lock()
+	defer i.Unlock()
+
+	delete(i.known[playerID], id)
+}
+
+func (i *zoneInterest) forgetPlayer(playerID uint16) {
+	i.Lock()
+	defer i.Unlock()
+
+	delete(i.known, playerID)
+}
+
+func (i *zoneInterest) knownEntities(playerID uint16) []uint16 {
+	i.Lock()
+	defer i.Unlock()
+
+	ids := make([]uint16, 0, len(i.known[playerID]))
+
+	for id := range i.known[playerID] {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func interestCell(position datatypes.Vector2Float32) datatypes.Vector2 {
+	return datatypes.Vector2{
+		X: int32(math.Floor(float64(position.X) / interestCellSize)),
+		Y: int32(math.Floor(float64(position.Y) / interestCellSize)),
+	}
+}
+
+func viewRadius() float64 {
+	if serverconfig.Config.ZoneOptions.ViewRadius > 0 {
+		return float64(serverconfig.Config.ZoneOptions.ViewRadius)
+	}
+
+	return defaultViewRadius
+}
+
+// interestPosition is the position other players see the entity at, entities without a position are sent to everyone
+func interestPosition(entity drobjecttypes.DRObject) (datatypes.Vector2Float32, bool) {
+	if player, ok := entity.(IPlayer); ok {
+		avatar, ok := player.GetPlayer().GetChildByGCNativeType("Avatar").(IAvatar)
+
+		if !ok {
+			return datatypes.Vector2Float32{}, false
+		}
+
+		entity = avatar.GetAvatar()
+	}
+
+	if unitBehavior, ok := entity.GetChildByGCNativeType("UnitBehavior").(IUnitBehavior); ok && unitBehavior != nil {
+		return unitBehavior.GetUnitBehavior().Position.ToVector2Float32(), true
+	}
+
+	if worldEntity, ok := entity.(IWorldEntity); ok {
+		return worldEntity.GetWorldEntity().WorldPosition.ToVector2Float32(), true
+	}
+
+	return datatypes.Vector2Float32{}, false
+}
+
+// interestRoot finds the entity that was spawned into the zone, components and avatars are only ever known through it
+func interestRoot(object drobjecttypes.DRObject) drobjecttypes.DRObject {
+	for {
+		gcObject, ok := object.(IGCObject)
+
+		if !ok || gcObject.GetGCObject().GCParent == nil {
+			return object
+		}
+
+		object = gcObject.GetGCObject().GCParent
+	}
+}
+
+func entityID(entity drobjecttypes.DRObject) uint16 {
+	return uint16(entity.(IRREntityPropertiesHaver).GetRREntityProperties().ID)
+}
+
+// playerPosition is the position of the player's avatar, players who haven't finished entering the zone have none
+func playerPosition(rrplayer *RRPlayer) (datatypes.Vector2Float32, bool) {
+	if rrplayer.CurrentCharacter == nil || !rrplayer.CurrentCharacter.Spawned {
+		return datatypes.Vector2Float32{}, false
+	}
+
+	return interestPosition(rrplayer.CurrentCharacter)
+}
+
+// PlayerKnows is true when the player has been sent the entity, players always know the entities they own
+func (z *Zone) PlayerKnows(rrplayer *RRPlayer, entity drobjecttypes.DRObject) bool {
+	playerID := uint16(rrplayer.Conn.GetID())
+	root := interestRoot(entity)
+
+	if root.OwnerID() == playerID {
+		return true
+	}
+
+	return z.interest.knows(playerID, entityID(root))
+}
+
+// NotifyNearbyPlayers sends an update for the entity to the players that know about it
+func (z *Zone) NotifyNearbyPlayers(entity drobjecttypes.DRObject, excludeID *uint16, opType message.OpType, f func() *byter.Byter) {
+	players := make([]*RRPlayer, 0)
+
+	for _, rrplayer := range z.Players() {
+		if excludeID != nil && int(*excludeID) == rrplayer.Conn.GetID() {
+			continue
+		}
+
+		if z.PlayerKnows(rrplayer, entity) {
+			players = append(players, rrplayer)
+		}
+	}
+
+	if len(players) == 0 {
+		return
+	}
+
+	body := f()
+
+	for _, rrplayer := range players {
+		rrplayer.MessageQueue.Enqueue(message.QueueTypeClientEntity, body, opType)
+	}
+}
+
+// SendToNearby is SendToAll for messages about a single entity
+func (z *Zone) SendToNearby(entity drobjecttypes.DRObject, body *byter.Byter) {
+	for _, rrplayer := range z.Players() {
+		if z.PlayerKnows(rrplayer, entity) {
+			connections.WriteCompressedASimple(rrplayer.Conn, body)
+		}
+	}
+}
+
+func (z *Zone) onInterestEntitySpawned(entity drobjecttypes.DRObject) {
+	id := entityID(entity)
+	position, hasPosition := interestPosition(entity)
+
+	if hasPosition {
+		z.interest.place(id, entity, position)
+	}
+
+	radius := viewRadius()
+
+	for _, rrplayer := range z.Players() {
+		if int(entity.OwnerID()) == rrplayer.Conn.GetID() {
+			continue
+		}
+
+		if hasPosition {
+			playerPos, ok := playerPosition(rrplayer)
+
+			if !ok || playerPos.Distance(position) > radius {
+				continue
+			}
+		}
+
+		z.sendCreate(rrplayer, entity)
+	}
+}
+
+func (z *Zone) onInterestEntityDespawned(entity drobjecttypes.DRObject) {
+	root := interestRoot(entity)
+	rootID := entityID(root)
+
+	z.NotifyNearbyPlayers(entity, types.Pointer(entity.OwnerID()), message.OpTypeCreateEntity, func() *byter.Byter {
+		CEWriter := NewClientEntityWriterWithByter()
+
+		CEWriter.Remove(entity)
+
+		return CEWriter.Body
+	})
+
+	if root == entity {
+		z.interest.remove(rootID)
+	}
+}
+
+// updateInterest moves every entity to its current cell then creates the entities that came into view of each player
+// and removes the ones that left it
+func (z *Zone) updateInterest() {
+	for _, entity := range z.Entities() {
+		if entity == nil {
+			continue
+		}
+
+		if position, ok := interestPosition(entity); ok {
+			z.interest.place(entityID(entity), entity, position)
+		}
+	}
+
+	for _, rrplayer := range z.Players() {
+		z.refreshPlayerInterest(rrplayer)
+	}
+}
+
+func (z *Zone) refreshPlayerInterest(rrplayer *RRPlayer) {
+	position, ok := playerPosition(rrplayer)
+
+	if !ok {
+		return
+	}
+
+	playerID := uint16(rrplayer.Conn.GetID())
+	radius := viewRadius()
+
+	for _, entity := range z.interest.nearby(position, radius) {
+		if entity.OwnerID() == playerID || z.interest.knows(playerID, entityID(entity)) {
+			continue
+		}
+
+		z.sendCreate(rrplayer, entity)
+	}
+
+	for _, id := range z.interest.knownEntities(playerID) {
+		entityPosition, ok := z.interest.position(id)
+
+		if !ok || entityPosition.Distance(position) <= radius+interestRemoveMargin {
+			continue
+		}
+
+		entity := z.FindEntityByID(id)
+
+		z.interest.forget(playerID, id)
+
+		if entity == nil {
+			continue
+		}
+
+		CEWriter := NewClientEntityWriterWithByter()
+		CEWriter.Remove(entity)
+
+		rrplayer.MessageQueue.Enqueue(message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeCreateEntity)
+	}
+}
+
+// sendNearbyEntities sends a player that just joined the zone everything they can see and every entity without a
+// position
+func (z *Zone) sendNearbyEntities(rrplayer *RRPlayer) {
+	playerID := uint16(rrplayer.Conn.GetID())
+
+	for _, entity := range z.Entities() {
+		if entity == nil || entity.OwnerID() == playerID {
+			continue
+		}
+
+		if _, ok := interestPosition(entity); !ok {
+			z.sendCreate(rrplayer, entity)
+		}
+	}
+
+	z.refreshPlayerInterest(rrplayer)
+}
+
+func (z *Zone) sendCreate(rrplayer *RRPlayer, entity drobjecttypes.DRObject) {
+	if !z.interest.markKnown(uint16(rrplayer.Conn.GetID()), entityID(entity)) {
+		return
+	}
+
+	CEWriter := NewClientEntityWriterWithByter()
+	WriteCreateExistingEntity(entity, CEWriter)
+
+	rrplayer.MessageQueue.Enqueue(message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeCreateEntity)
+}
+
+func newZoneInterest() *zoneInterest {
+	return &zoneInterest{
+		cells:     make(map[datatypes.Vector2]map[uint16]drobjecttypes.DRObject),
+		positions: make(map[uint16]datatypes.Vector2Float32),
+		known:     make(map[uint16]map[uint16]bool),
+	}
+}
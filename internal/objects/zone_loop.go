@@ -0,0 +1,154 @@
+package objects
+
+import (
+	"RainbowRunner/internal/global"
+	"RainbowRunner/internal/metrics"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"runtime/debug"
+	"time"
+)
+
+// Start runs the zone on its own goroutine, everything that changes the zone's entities or players should either run
+// on this goroutine or be queued with Enqueue
+func (z *Zone) Start() {
+	z.loopLock.Lock()
+	defer z.loopLock.Unlock()
+
+	if z.stop != nil {
+		return
+	}
+
+	z.stop = make(chan struct{})
+	z.stopped = make(chan struct{})
+
+	go z.run(z.stop, z.stopped)
+}
+
+// Stop waits for the current tick to finish, jobs still in the queue are left until the zone is started again
+func (z *Zone) Stop() {
+	z.loopLock.Lock()
+	defer z.loopLock.Unlock()
+
+	if z.stop == nil {
+		return
+	}
+
+	close(z.stop)
+	<-z.stopped
+
+	z.stop = nil
+	z.stopped = nil
+}
+
+// Close stops the zone and closes its Lua state, the zone can't be used again afterwards
+func (z *Zone) Close() {
+	z.Stop()
+
+	if z.Scripts != nil && z.Scripts.State != nil {
+		z.Scripts.State.Close()
+	}
+}
+
+func (z *Zone) IsRunning() bool {
+	z.loopLock.Lock()
+	defer z.loopLock.Unlock()
+
+	return z.stop != nil
+}
+
+// Enqueue runs the job on the zone's goroutine at the start of its next tick
+func (z *Zone) Enqueue(job func()) {
+	z.jobs.Enqueue(job)
+}
+
+func (z *Zone) run(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(global.TickInterval * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			timer := metrics.StartTick("zone:" + z.Name)
+
+			z.runJobs()
+
+			timer.Phase("jobs")
+
+			if !z.Initialised() {
+				timer.Finish()
+				continue
+			}
+
+			if err := z.safeTick(timer); err != nil {
+				log.Errorf("zone %s tick failed: %s", z.Name, err.Error())
+			}
+
+			z.flushPlayers()
+
+			timer.Phase("flush")
+			timer.Finish()
+		}
+	}
+}
+
+func (z *Zone) runJobs() {
+	for !z.jobs.IsEmpty() {
+		z.runJob(z.jobs.Dequeue())
+	}
+}
+
+// flushPlayers sends the messages queued this tick to the players this zone owns, players that are moving to another
+// zone are flushed by the new zone
+func (z *Zone) flushPlayers() {
+	for _, player := range z.Players() {
+		if player.OwnerZone() != z {
+			continue
+		}
+
+		z.flushPlayer(player)
+	}
+}
+
+func (z *Zone) flushPlayer(player *RRPlayer) {
+	defer z.recoverPanic(fmt.Sprintf("flush for player %d", player.Conn.GetID()))
+
+	player.FlushMessages()
+}
+
+func (z *Zone) runJob(job func()) {
+	defer z.recoverPanic("job")
+
+	job()
+}
+
+// safeTick stops a panic in one tick from taking down every zone, the zone carries on with the next tick
+func (z *Zone) safeTick(timer *metrics.TickTimer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.RecoveredPanics.Inc("zone")
+			log.Errorf("recovered panic in zone %s tick: %v\n%s", z.Name, r, debug.Stack())
+			err = errors.New(fmt.Sprintf("tick panicked: %v", r))
+		}
+	}()
+
+	return z.tick(timer)
+}
+
+// recoverPanic must be deferred directly so recover can see the panic
+func (z *Zone) recoverPanic(description string) {
+	r := recover()
+
+	if r == nil {
+		return
+	}
+
+	metrics.RecoveredPanics.Inc("zone")
+
+	log.Errorf("recovered panic in zone %s %s: %v\n%s", z.Name, description, r, debug.Stack())
+}
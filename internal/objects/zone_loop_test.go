@@ -0,0 +1,261 @@
+package objects
+
+import (
+	"RainbowRunner/internal/connections"
+	"RainbowRunner/internal/message"
+	"RainbowRunner/pkg/byter"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestZone starts a zone without loading its config or scripts so it can tick outside of a full server
+func newTestZone(t *testing.T, name string) *Zone {
+	z := Zones.CreateZone(name)
+	z.Scripts = &ZoneLuaScripts{}
+	z.initialised = true
+	z.Start()
+
+	t.Cleanup(func() {
+		z.Stop()
+
+		Zones.Lock()
+		delete(Zones.Zones, name)
+		Zones.Unlock()
+	})
+
+	return z
+}
+
+// newTestPlayer registers a player whose connection writes to a pipe that is read and thrown away
+func newTestPlayer(t *testing.T, id int) (*RRPlayer, *Player) {
+	server, client := net.Pipe()
+
+	go io.Copy(io.Discard, client)
+
+	conn := connections.NewRRConn(server)
+	conn.Client = &connections.RRConnClient{ID: id, Conn: conn}
+
+	go conn.StartWriter()
+
+	rrPlayer := Players.Register(conn)
+
+	character := NewPlayer(fmt.Sprintf("Tester%d", id))
+	character.EntityProperties.SetOwner(uint16(id))
+	rrPlayer.CurrentCharacter = character
+
+	t.Cleanup(func() {
+		Players.OnDisconnect(id)
+		conn.Close()
+		client.Close()
+	})
+
+	return rrPlayer, character
+}
+
+// waitForZone returns once every job queued on the zone before it has run
+func waitForZone(t *testing.T, z *Zone) {
+	done := make(chan struct{})
+
+	z.Enqueue(func() {
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("zone %s did not run its jobs", z.Name)
+	}
+}
+
+func TestZonesTickConcurrently(t *testing.T) {
+	zones := []*Zone{newTestZone(t, "test_zone_a"), newTestZone(t, "test_zone_b")}
+
+	const producers = 4
+	const jobsPerProducer = 50
+
+	// Only ever changed on the zone's goroutine, the race detector fails the test if a job runs anywhere else
+	counts := make([]int, len(zones))
+
+	wg := sync.WaitGroup{}
+
+	for zi, z := range zones {
+		zi, z := zi, z
+
+		for p := 0; p < producers; p++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < jobsPerProducer; j++ {
+					z.Enqueue(func() {
+						counts[zi]++
+						z.SpawnEntity(nil, NewWaypoint("test.Waypoint"))
+					})
+				}
+			}()
+		}
+
+		// Readers such as the metrics gauges look at the zone while it ticks
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < jobsPerProducer; j++ {
+				_ = len(z.Entities())
+				_ = len(z.Players())
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for zi, z := range zones {
+		waitForZone(t, z)
+
+		expected := producers * jobsPerProducer
+
+		if got := <-runOn(z, func() int { return counts[zi] }); got != expected {
+			t.Errorf("zone %s ran %d jobs, expected %d", z.Name, got, expected)
+		}
+
+		if got := len(z.Entities()); got != expected {
+			t.Errorf("zone %s has %d entities, expected %d", z.Name, got, expected)
+		}
+	}
+}
+
+func TestChangeZone(t *testing.T) {
+	from := newTestZone(t, "test_zone_from")
+	to := newTestZone(t, "test_zone_to")
+
+	rrPlayer, character := newTestPlayer(t, 0x7001)
+
+	character.EnterZone(from, nil)
+	waitForZone(t, from)
+
+	if rrPlayer.OwnerZone() != from || character.Zone != from {
+		t.Fatalf("player did not join %s", from.Name)
+	}
+
+	// The client keeps sending while the player moves, everything it sends is handled by whichever zone owns the
+	// player at the time
+	const jobs = 200
+	ran := 0
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for j := 0; j < jobs; j++ {
+			rrPlayer.Run(func() {
+				ran++
+				character.Spawned = true
+
+				body := byter.NewLEByter(make([]byte, 0, 8))
+				body.WriteByte(0x00)
+				rrPlayer.MessageQueue.Enqueue(message.QueueTypeClientEntity, body, message.OpTypeOther)
+
+				if ran == jobs {
+					close(done)
+				}
+			})
+		}
+	}()
+
+	rrPlayer.Run(func() {
+		character.ChangeZone(to.Name)
+	})
+
+	wg.Wait()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("only %d of %d player jobs ran", <-runOn(to, func() int { return ran }), jobs)
+	}
+
+	waitForZone(t, to)
+
+	if rrPlayer.OwnerZone() != to {
+		t.Errorf("player is owned by %s, expected %s", rrPlayer.OwnerZone().Name, to.Name)
+	}
+
+	if zone := <-runOn(to, func() *Zone { return character.Zone }); zone != to {
+		t.Errorf("character is in %s, expected %s", zone.Name, to.Name)
+	}
+
+	for _, player := range from.Players() {
+		if player == rrPlayer {
+			t.Errorf("player is still in %s", from.Name)
+		}
+	}
+
+	found := false
+
+	for _, player := range to.Players() {
+		if player == rrPlayer {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("player is not in %s", to.Name)
+	}
+}
+
+func TestAnnounceWhileZoneTicks(t *testing.T) {
+	z := newTestZone(t, "test_zone_announce")
+	rrPlayer, character := newTestPlayer(t, 0x7002)
+
+	character.EnterZone(z, nil)
+	waitForZone(t, z)
+
+	// The zone keeps spawning and despawning the character while announcements are sent from another goroutine
+	stop := make(chan struct{})
+	started := make(chan struct{})
+
+	z.Enqueue(func() {
+		close(started)
+
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				character.Spawned = !character.Spawned
+			}
+		}
+	})
+
+	<-started
+
+	for i := 0; i < 50; i++ {
+		Players.Announce("The server is shutting down.")
+	}
+
+	close(stop)
+	waitForZone(t, z)
+
+	if rrPlayer.OwnerZone() != z {
+		t.Errorf("player is not owned by %s", z.Name)
+	}
+}
+
+// runOn reads state owned by the zone on the zone's goroutine
+func runOn[T any](z *Zone, f func() T) <-chan T {
+	result := make(chan T, 1)
+
+	z.Enqueue(func() {
+		result <- f()
+	})
+
+	return result
+}
@@ -2,6 +2,7 @@ package objects
 
 import (
 	"RainbowRunner/internal/lua"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/script"
 	drobjectypes "RainbowRunner/internal/types/drobjecttypes"
 	log "github.com/sirupsen/logrus"
@@ -38,6 +39,7 @@ func (s *ZoneLuaScripts) Init(entity drobjectypes.DRObject) error {
 		err := script.Execute(s.State)
 
 		if err != nil {
+			metrics.LuaErrors.Inc()
 			log.Errorf("Error executing standalone init script for zone %s", err.Error())
 		}
 	}
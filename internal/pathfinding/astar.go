@@ -0,0 +1,234 @@
+package pathfinding
+
+import (
+	"RainbowRunner/internal/types"
+	"RainbowRunner/pkg/datatypes"
+	"container/heap"
+	"math"
+)
+
+// Searches that expand more than this many nodes are treated as unreachable
+const maxSearchNodes = 20000
+
+var neighbourOffsets = [...]datatypes.Vector2{
+	{X: 1, Y: 0}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: -1},
+	{X: 1, Y: 1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: -1, Y: -1},
+}
+
+type searchNode struct {
+	coords datatypes.Vector2
+	parent *searchNode
+	g, f   float64
+	index  int
+	closed bool
+}
+
+type openList []*searchNode
+
+func (o openList) Len() int           { return len(o) }
+func (o openList) Less(i, j int) bool { return o[i].f < o[j].f }
+
+func (o openList) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+	o[i].index = i
+	o[j].index = j
+}
+
+func (o *openList) Push(x any) {
+	node := x.(*searchNode)
+	node.index = len(*o)
+	*o = append(*o, node)
+}
+
+func (o *openList) Pop() any {
+	old := *o
+	node := old[len(old)-1]
+	*o = old[:len(old)-1]
+	node.index = -1
+	return node
+}
+
+// search is an A* search over the pathmap grid that can be continued across ticks
+type search struct {
+	pathMap  *types.PathMap
+	goal     datatypes.Vector2
+	open     openList
+	nodes    map[datatypes.Vector2]*searchNode
+	expanded int
+
+	done  bool
+	found bool
+	path  []datatypes.Vector2
+}
+
+func newSearch(pathMap *types.PathMap, start, goal datatypes.Vector2) *search {
+	s := &search{
+		pathMap: pathMap,
+		goal:    goal,
+		nodes:   make(map[datatypes.Vector2]*searchNode),
+	}
+
+	if !pathMap.IsWalkable(goal) {
+		s.done = true
+		return s
+	}
+
+	// The start node is allowed to be unwalkable so units standing on the edge of a wall can still leave it
+	startNode := &searchNode{coords: start, f: octile(start, goal)}
+	s.nodes[start] = startNode
+	heap.Push(&s.open, startNode)
+
+	return s
+}
+
+// step expands up to budget nodes and returns how many were used
+func (s *search) step(budget int) int {
+	used := 0
+
+	for used < budget && !s.done {
+		if s.open.Len() == 0 || s.expanded >= maxSearchNodes {
+			s.done = true
+			break
+		}
+
+		current := heap.Pop(&s.open).(*searchNode)
+		current.closed = true
+		s.expanded++
+		used++
+
+		if current.coords == s.goal {
+			s.done = true
+			s.found = true
+			s.path = s.buildPath(current)
+			break
+		}
+
+		for _, offset := range neighbourOffsets {
+			coords := datatypes.Vector2{X: current.coords.X + offset.X, Y: current.coords.Y + offset.Y}
+
+			if !s.pathMap.IsWalkable(coords) {
+				continue
+			}
+
+			cost := 1.0
+
+			if offset.X != 0 && offset.Y != 0 {
+				// Don't let diagonals cut through the corner of a wall
+				if !s.pathMap.IsWalkable(datatypes.Vector2{X: current.coords.X + offset.X, Y: current.coords.Y}) ||
+					!s.pathMap.IsWalkable(datatypes.Vector2{X: current.coords.X, Y: current.coords.Y + offset.Y}) {
+					continue
+				}
+
+				cost = math.Sqrt2
+			}
+
+			g := current.g + cost
+			node, ok := s.nodes[coords]
+
+			if !ok {
+				node = &searchNode{coords: coords, parent: current, g: g, f: g + octile(coords, s.goal)}
+				s.nodes[coords] = node
+				heap.Push(&s.open, node)
+				continue
+			}
+
+			if node.closed || g >= node.g {
+				continue
+			}
+
+			node.parent = current
+			node.g = g
+			node.f = g + octile(coords, s.goal)
+			heap.Fix(&s.open, node.index)
+		}
+	}
+
+	return used
+}
+
+func (s *search) buildPath(end *searchNode) []datatypes.Vector2 {
+	path := make([]datatypes.Vector2, 0)
+
+	for node := end; node != nil; node = node.parent {
+		path = append(path, node.coords)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// smoothPath removes every grid point that can be skipped with a straight walkable line, leaving only the corners
+func smoothPath(pathMap *types.PathMap, path []datatypes.Vector2) []datatypes.Vector2 {
+	if len(path) <= 2 {
+		return path
+	}
+
+	smoothed := []datatypes.Vector2{path[0]}
+	anchor := path[0]
+
+	for i := 2; i < len(path); i++ {
+		if !lineOfSight(pathMap, anchor, path[i]) {
+			anchor = path[i-1]
+			smoothed = append(smoothed, anchor)
+		}
+	}
+
+	return append(smoothed, path[len(path)-1])
+}
+
+// lineOfSight walks every grid cell the line between a and b passes through
+func lineOfSight(pathMap *types.PathMap, a, b datatypes.Vector2) bool {
+	dx := int32(math.Abs(float64(b.X - a.X)))
+	dy := int32(math.Abs(float64(b.Y - a.Y)))
+	stepX := int32(1)
+	stepY := int32(1)
+
+	if b.X < a.X {
+		stepX = -1
+	}
+
+	if b.Y < a.Y {
+		stepY = -1
+	}
+
+	x, y := a.X, a.Y
+	err := dx - dy
+
+	for x != b.X || y != b.Y {
+		e2 := err * 2
+
+		// Moving diagonally needs both of the cells either side to be clear as well
+		if e2 > -dy && e2 < dx {
+			if !pathMap.IsWalkable(datatypes.Vector2{X: x + stepX, Y: y}) ||
+				!pathMap.IsWalkable(datatypes.Vector2{X: x, Y: y + stepY}) {
+				return false
+			}
+		}
+
+		if e2 > -dy {
+			err -= dy
+			x += stepX
+		}
+
+		if e2 < dx {
+			err += dx
+			y += stepY
+		}
+
+		if !pathMap.IsWalkable(datatypes.Vector2{X: x, Y: y}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func octile(a, b datatypes.Vector2) float64 {
+	dx := math.Abs(float64(a.X - b.X))
+	dy := math.Abs(float64(a.Y - b.Y))
+
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
@@ -0,0 +1,254 @@
+package pathfinding
+
+import (
+	"RainbowRunner/internal/types"
+	"RainbowRunner/pkg/datatypes"
+	"math"
+	"testing"
+)
+
+// newTestPathMap builds a single chunk pathmap, '.' is walkable and anything else, including the cells the rows don't
+// reach, is a wall
+func newTestPathMap(rows ...string) *types.PathMap {
+	nodes := make([]types.PathNode, 16*16)
+
+	for y, row := range rows {
+		for x, c := range row {
+			nodes[x+y*16].Solid = c == '.'
+		}
+	}
+
+	return &types.PathMap{ChunkWidth: 1, ChunkHeight: 1, Nodes: [][]types.PathNode{nodes}}
+}
+
+// cell returns the grid coords of a layout cell, the grid coords are centred on the chunk
+func cell(x, y int32) datatypes.Vector2 {
+	return datatypes.Vector2{X: x - 8, Y: y - 8}
+}
+
+// A corridor that winds back and forth across the chunk so the search has to expand most of it
+var serpentine = []string{
+	"................",
+	"###############.",
+	"................",
+	".###############",
+	"................",
+	"###############.",
+	"................",
+	".###############",
+	"................",
+	"###############.",
+	"................",
+	".###############",
+	"................",
+	"###############.",
+	"................",
+}
+
+func pathCost(path []datatypes.Vector2) float64 {
+	cost := 0.0
+
+	for i := 1; i < len(path); i++ {
+		cost += math.Hypot(float64(path[i].X-path[i-1].X), float64(path[i].Y-path[i-1].Y))
+	}
+
+	return cost
+}
+
+// checkPath fails if the path leaves the walkable cells, skips a cell or cuts the corner of a wall
+func checkPath(t *testing.T, pathMap *types.PathMap, path []datatypes.Vector2) {
+	t.Helper()
+
+	for i := 1; i < len(path); i++ {
+		from, to := path[i-1], path[i]
+		dx, dy := to.X-from.X, to.Y-from.Y
+
+		if dx < -1 || dx > 1 || dy < -1 || dy > 1 || (dx == 0 && dy == 0) {
+			t.Fatalf("step %d goes from %v to %v", i, from, to)
+		}
+
+		if !pathMap.IsWalkable(to) {
+			t.Fatalf("step %d is onto a wall at %v", i, to)
+		}
+
+		if dx != 0 && dy != 0 &&
+			(!pathMap.IsWalkable(datatypes.Vector2{X: to.X, Y: from.Y}) || !pathMap.IsWalkable(datatypes.Vector2{X: from.X, Y: to.Y})) {
+			t.Fatalf("step %d from %v to %v cuts a corner", i, from, to)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       []string
+		start      datatypes.Vector2
+		goal       datatypes.Vector2
+		found      bool
+		cost       float64
+		pathLength int
+	}{
+		{
+			name:  "open",
+			rows:  []string{".....", ".....", "....."},
+			start: cell(0, 0), goal: cell(4, 2),
+			found: true, cost: 2 + 2*math.Sqrt2, pathLength: 5,
+		},
+		{
+			name:  "around a wall",
+			rows:  []string{"..#..", "..#..", "....."},
+			start: cell(0, 0), goal: cell(4, 0),
+			found: true, cost: 4 + 2*math.Sqrt2, pathLength: 7,
+		},
+		{
+			name:  "goal walled in",
+			rows:  []string{"..#..", "..#..", "..#.."},
+			start: cell(0, 0), goal: cell(4, 0),
+		},
+		{
+			name:  "goal is a wall",
+			rows:  []string{".....", "..#..", "....."},
+			start: cell(0, 0), goal: cell(2, 1),
+		},
+		{
+			name:  "goal off the map",
+			rows:  []string{"....."},
+			start: cell(0, 0), goal: cell(20, 0),
+		},
+		{
+			name:  "start on a wall",
+			rows:  []string{"#....", "....."},
+			start: cell(0, 0), goal: cell(4, 1),
+			found: true, cost: 3 + math.Sqrt2, pathLength: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pathMap := newTestPathMap(test.rows...)
+			s := newSearch(pathMap, test.start, test.goal)
+			s.step(maxSearchNodes)
+
+			if !s.done || s.found != test.found {
+				t.Fatalf("done %v found %v, expected found %v", s.done, s.found, test.found)
+			}
+
+			if !test.found {
+				return
+			}
+
+			if s.path[0] != test.start || s.path[len(s.path)-1] != test.goal {
+				t.Fatalf("path goes from %v to %v", s.path[0], s.path[len(s.path)-1])
+			}
+
+			checkPath(t, pathMap, s.path[1:])
+
+			if len(s.path) != test.pathLength || math.Abs(pathCost(s.path)-test.cost) > 1e-9 {
+				t.Errorf("path has %d points and costs %f, expected %d and %f", len(s.path), pathCost(s.path), test.pathLength, test.cost)
+			}
+		})
+	}
+}
+
+func TestSearchStepBudget(t *testing.T) {
+	pathMap := newTestPathMap(serpentine...)
+	s := newSearch(pathMap, cell(0, 0), cell(0, 14))
+
+	steps := 0
+
+	for !s.done {
+		if used := s.step(10); used > 10 {
+			t.Fatalf("step used %d nodes of a budget of 10", used)
+		}
+
+		steps++
+	}
+
+	if !s.found {
+		t.Fatal("no path through the serpentine")
+	}
+
+	// Every cell of the corridor is on the path
+	if len(s.path) != 8*16+7 {
+		t.Errorf("path has %d points, expected %d", len(s.path), 8*16+7)
+	}
+
+	if steps < len(s.path)/10 {
+		t.Errorf("search finished in %d steps, it can't have kept to the budget", steps)
+	}
+}
+
+func TestLineOfSight(t *testing.T) {
+	pathMap := newTestPathMap(
+		".....",
+		"..#..",
+		".....",
+		".....",
+	)
+
+	tests := []struct {
+		name     string
+		a, b     datatypes.Vector2
+		expected bool
+	}{
+		{"same cell", cell(0, 0), cell(0, 0), true},
+		{"straight", cell(0, 0), cell(4, 0), true},
+		{"through the wall", cell(0, 1), cell(4, 1), false},
+		{"below the wall", cell(0, 2), cell(4, 3), true},
+		{"diagonal past the corner", cell(1, 0), cell(3, 2), false},
+		{"diagonal clear", cell(0, 1), cell(2, 3), true},
+		{"off the map", cell(0, 0), cell(0, 6), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if seen := lineOfSight(pathMap, test.a, test.b); seen != test.expected {
+				t.Errorf("line of sight from %v to %v is %v, expected %v", test.a, test.b, seen, test.expected)
+			}
+
+			if seen := lineOfSight(pathMap, test.b, test.a); seen != test.expected {
+				t.Errorf("line of sight from %v to %v is %v, expected %v", test.b, test.a, seen, test.expected)
+			}
+		})
+	}
+}
+
+func TestSmoothPath(t *testing.T) {
+	pathMap := newTestPathMap(
+		"......",
+		"####..",
+		"####..",
+	)
+
+	s := newSearch(pathMap, cell(0, 0), cell(4, 2))
+	s.step(maxSearchNodes)
+
+	if !s.found {
+		t.Fatal("no path")
+	}
+
+	smoothed := smoothPath(pathMap, s.path)
+
+	// The path along the top row can only turn down once it is past the end of the wall
+	expected := []datatypes.Vector2{cell(0, 0), cell(4, 0), cell(4, 2)}
+
+	if len(smoothed) != len(expected) {
+		t.Fatalf("smoothed to %v, expected %v", smoothed, expected)
+	}
+
+	for i := range expected {
+		if smoothed[i] != expected[i] {
+			t.Fatalf("smoothed to %v, expected %v", smoothed, expected)
+		}
+	}
+
+	for i := 1; i < len(smoothed); i++ {
+		if !lineOfSight(pathMap, smoothed[i-1], smoothed[i]) {
+			t.Errorf("no line of sight between %v and %v", smoothed[i-1], smoothed[i])
+		}
+	}
+
+	if short := smoothPath(pathMap, smoothed[:2]); len(short) != 2 {
+		t.Errorf("a path of two points was smoothed to %v", short)
+	}
+}
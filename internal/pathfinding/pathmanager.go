@@ -0,0 +1,136 @@
+package pathfinding
+
+import (
+	"RainbowRunner/internal/types"
+	"RainbowRunner/pkg/datatypes"
+)
+
+// Budget mirrors PathManager::readBudget, it limits how much searching is done each tick so long paths are spread over
+// several ticks instead of stalling the zone
+type Budget struct {
+	PerUpdate uint16
+	PerPath   uint16
+}
+
+// DefaultBudget is also sent to the client in SendInterval
+var DefaultBudget = Budget{
+	PerUpdate: 100,
+	PerPath:   20,
+}
+
+// Number of nodes the server expands for each point of budget
+const nodesPerBudget = 32
+
+// Pathmap cells are 10 units wide, GridCoordsToWorldPos gives the corner of the cell
+var cellCentre = datatypes.Vector2Float32{X: 5, Y: 5}
+
+// PathRequest is polled by the requester until Done is set, Path is the smoothed list of waypoints to walk and
+// excludes the starting position
+type PathRequest struct {
+	From datatypes.Vector2Float32
+	To   datatypes.Vector2Float32
+
+	Done  bool
+	Found bool
+	Path  []datatypes.Vector2Float32
+
+	search *search
+}
+
+// PathManager runs the path requests for a zone
+type PathManager struct {
+	PathMap *types.PathMap
+	Budget  Budget
+
+	requests []*PathRequest
+}
+
+func (m *PathManager) Request(from, to datatypes.Vector2Float32) *PathRequest {
+	request := &PathRequest{
+		From: from,
+		To:   to,
+	}
+
+	start := m.PathMap.WorldPosToGridCoords(from)
+	goal := m.PathMap.WorldPosToGridCoords(to)
+
+	if start == goal {
+		request.complete(true, []datatypes.Vector2Float32{to})
+		return request
+	}
+
+	request.search = newSearch(m.PathMap, start, goal)
+	m.requests = append(m.requests, request)
+
+	return request
+}
+
+func (m *PathManager) Cancel(request *PathRequest) {
+	for i, r := range m.requests {
+		if r == request {
+			m.requests = append(m.requests[:i], m.requests[i+1:]...)
+			break
+		}
+	}
+
+	request.Done = true
+}
+
+// Update runs the oldest requests first, each request can use at most PerPath of the PerUpdate budget per tick
+func (m *PathManager) Update() {
+	budget := int(m.Budget.PerUpdate) * nodesPerBudget
+	remaining := m.requests[:0]
+
+	for _, request := range m.requests {
+		if budget > 0 && !request.Done {
+			pathBudget := int(m.Budget.PerPath) * nodesPerBudget
+
+			if pathBudget > budget {
+				pathBudget = budget
+			}
+
+			budget -= request.search.step(pathBudget)
+
+			if request.search.done {
+				request.complete(request.search.found, m.toWorldPath(request))
+			}
+		}
+
+		if !request.Done {
+			remaining = append(remaining, request)
+		}
+	}
+
+	m.requests = remaining
+}
+
+func (m *PathManager) toWorldPath(request *PathRequest) []datatypes.Vector2Float32 {
+	if !request.search.found {
+		return nil
+	}
+
+	gridPath := smoothPath(m.PathMap, request.search.path)
+	path := make([]datatypes.Vector2Float32, 0, len(gridPath))
+
+	// The first point is the cell the unit is already in and the last is replaced with the exact destination
+	for _, coords := range gridPath[1 : len(gridPath)-1] {
+		corner := m.PathMap.GridCoordsToWorldPos(coords).ToVector2Float32()
+		path = append(path, corner.Add(cellCentre))
+	}
+
+	return append(path, request.To)
+}
+
+func (r *PathRequest) complete(found bool, path []datatypes.Vector2Float32) {
+	r.Done = true
+	r.Found = found
+	r.Path = path
+	r.search = nil
+}
+
+func NewPathManager(pathMap *types.PathMap) *PathManager {
+	return &PathManager{
+		PathMap: pathMap,
+		Budget:  DefaultBudget,
+	}
+}
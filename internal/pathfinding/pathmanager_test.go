@@ -0,0 +1,172 @@
+package pathfinding
+
+import (
+	"RainbowRunner/pkg/datatypes"
+	"testing"
+)
+
+// cellPos returns the world position of the centre of a layout cell, the test pathmaps have no offset
+func cellPos(x, y int32) datatypes.Vector2Float32 {
+	coords := cell(x, y)
+
+	return datatypes.Vector2Float32{X: float32(coords.X)*10 + 5, Y: float32(coords.Y)*10 + 5}
+}
+
+// updateUntilDone updates the manager until the request is done and returns how many updates it took
+func updateUntilDone(t *testing.T, m *PathManager, request *PathRequest) int {
+	updates := 0
+
+	for !request.Done {
+		if updates > maxSearchNodes {
+			t.Fatal("request never finished")
+		}
+
+		m.Update()
+		updates++
+	}
+
+	return updates
+}
+
+func TestRequestSameCell(t *testing.T) {
+	m := NewPathManager(newTestPathMap("....."))
+	to := datatypes.Vector2Float32{X: cellPos(1, 0).X + 2, Y: cellPos(1, 0).Y}
+
+	request := m.Request(cellPos(1, 0), to)
+
+	if !request.Done || !request.Found || len(request.Path) != 1 || request.Path[0] != to {
+		t.Errorf("request in the same cell is done %v found %v with path %v", request.Done, request.Found, request.Path)
+	}
+
+	if len(m.requests) != 0 {
+		t.Error("request in the same cell was queued")
+	}
+}
+
+func TestRequestPath(t *testing.T) {
+	m := NewPathManager(newTestPathMap(
+		"......",
+		"####..",
+		"####..",
+	))
+
+	to := datatypes.Vector2Float32{X: cellPos(4, 2).X + 1, Y: cellPos(4, 2).Y + 2}
+	request := m.Request(cellPos(0, 0), to)
+
+	if updates := updateUntilDone(t, m, request); updates != 1 {
+		t.Errorf("short path took %d updates", updates)
+	}
+
+	// The starting cell is left out and the last point is the exact destination
+	expected := []datatypes.Vector2Float32{cellPos(4, 0), to}
+
+	if !request.Found || len(request.Path) != len(expected) {
+		t.Fatalf("found %v with path %v, expected %v", request.Found, request.Path, expected)
+	}
+
+	for i := range expected {
+		if request.Path[i] != expected[i] {
+			t.Fatalf("path is %v, expected %v", request.Path, expected)
+		}
+	}
+
+	if request.search != nil || len(m.requests) != 0 {
+		t.Error("finished request was kept")
+	}
+}
+
+func TestRequestNoPath(t *testing.T) {
+	m := NewPathManager(newTestPathMap(
+		"..#..",
+		"..#..",
+		"..#..",
+	))
+
+	request := m.Request(cellPos(0, 0), cellPos(4, 0))
+	updateUntilDone(t, m, request)
+
+	if request.Found || request.Path != nil {
+		t.Errorf("found %v with path %v, expected no path", request.Found, request.Path)
+	}
+
+	if len(m.requests) != 0 {
+		t.Error("finished request was kept")
+	}
+}
+
+func TestRequestOverSeveralUpdates(t *testing.T) {
+	m := NewPathManager(newTestPathMap(serpentine...))
+	m.Budget = Budget{PerUpdate: 1, PerPath: 1}
+
+	request := m.Request(cellPos(0, 0), cellPos(0, 14))
+	m.Update()
+
+	if request.Done || request.search.expanded != nodesPerBudget {
+		t.Fatalf("first update expanded %d nodes, expected %d", request.search.expanded, nodesPerBudget)
+	}
+
+	// The path is 135 cells long so it needs at least 5 updates of 32 nodes
+	if updates := updateUntilDone(t, m, request); updates < 4 {
+		t.Errorf("request finished after %d more updates", updates)
+	}
+
+	if !request.Found || request.Path[len(request.Path)-1] != cellPos(0, 14) {
+		t.Fatalf("found %v with path %v", request.Found, request.Path)
+	}
+
+	// Only the corners of the corridor are left, two for each turn
+	if len(request.Path) != 7*2+1 {
+		t.Errorf("path has %d points, expected %d", len(request.Path), 7*2+1)
+	}
+}
+
+func TestUpdateBudgetIsShared(t *testing.T) {
+	m := NewPathManager(newTestPathMap(serpentine...))
+	m.Budget = Budget{PerUpdate: 3, PerPath: 2}
+
+	first := m.Request(cellPos(0, 0), cellPos(0, 14))
+	second := m.Request(cellPos(0, 2), cellPos(0, 14))
+	third := m.Request(cellPos(0, 4), cellPos(0, 14))
+
+	m.Update()
+
+	// The oldest request uses its full per path budget and the next gets what is left of the update
+	expanded := []int{first.search.expanded, second.search.expanded, third.search.expanded}
+	expected := []int{2 * nodesPerBudget, nodesPerBudget, 0}
+
+	for i := range expected {
+		if expanded[i] != expected[i] {
+			t.Fatalf("requests expanded %v nodes, expected %v", expanded, expected)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	m := NewPathManager(newTestPathMap(serpentine...))
+	m.Budget = Budget{PerUpdate: 1, PerPath: 1}
+
+	cancelled := m.Request(cellPos(0, 0), cellPos(0, 14))
+	other := m.Request(cellPos(0, 12), cellPos(0, 14))
+
+	m.Update()
+	m.Cancel(cancelled)
+
+	if !cancelled.Done || cancelled.Found {
+		t.Fatalf("cancelled request is done %v found %v", cancelled.Done, cancelled.Found)
+	}
+
+	if len(m.requests) != 1 || m.requests[0] != other {
+		t.Fatal("cancelled request is still queued")
+	}
+
+	expanded := cancelled.search.expanded
+	updateUntilDone(t, m, other)
+
+	if cancelled.search.expanded != expanded || cancelled.Path != nil {
+		t.Error("cancelled request kept searching")
+	}
+
+	if !other.Found {
+		t.Error("the other request didn't find its path")
+	}
+}
@@ -2,6 +2,7 @@ package script
 
 import (
 	"RainbowRunner/internal/lua"
+	"RainbowRunner/internal/metrics"
 	"RainbowRunner/internal/types/drobjecttypes"
 	log "github.com/sirupsen/logrus"
 	lua2 "github.com/yuin/gopher-lua"
@@ -12,6 +13,7 @@ type IEntityScript interface {
 	Init(entity drobjecttypes.DRObject) error
 	Tick() error
 	Load() error
+	Name() string
 }
 
 type EntityScript struct {
@@ -55,6 +57,7 @@ func (e *EntityScript) Load() error {
 	err := e.luaScript.Execute(e.State)
 
 	if err != nil {
+		metrics.LuaErrors.Inc()
 		return err
 	}
 
@@ -86,6 +89,7 @@ func (e *EntityScript) Init(entity drobjecttypes.DRObject) error {
 		}, entityLua)
 
 		if err != nil {
+			metrics.LuaErrors.Inc()
 			return err
 		}
 	}
@@ -104,9 +108,22 @@ func (e *EntityScript) Tick() error {
 		Protect: true,
 	})
 
+	if err != nil {
+		metrics.LuaErrors.Inc()
+	}
+
 	return err
 }
 
+// Name is the ID of the script that was loaded, scripts without a file have no name
+func (e *EntityScript) Name() string {
+	if e == nil || e.luaScript == nil {
+		return ""
+	}
+
+	return e.luaScript.ID()
+}
+
 func (e *EntityScript) CallEventHandler(eventHandlerName string, args ...interface{}) {
 	if e == nil || e.EventHandlers == nil {
 		return
@@ -126,6 +143,7 @@ func (e *EntityScript) CallEventHandler(eventHandlerName string, args ...interfa
 		}, luaArgs...)
 
 		if err != nil {
+			metrics.LuaErrors.Inc()
 			log.Error(err)
 		}
 	} else {
@@ -3,6 +3,7 @@ package serverconfig
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"time"
 )
 
 var Config RRConfig
@@ -25,9 +26,11 @@ type LoggingOptions struct {
 }
 
 type NetworkOptions struct {
-	LoginServerPort int    `mapstructure:"login_server_port"`
-	GameServerPort  int    `mapstructure:"game_server_port"`
-	GameServerIP    string `mapstructure:"game_server_ip"`
+	LoginServerPort   int           `mapstructure:"login_server_port"`
+	GameServerPort    int           `mapstructure:"game_server_port"`
+	GameServerIP      string        `mapstructure:"game_server_ip"`
+	OutboundQueueSize int           `mapstructure:"outbound_queue_size"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
 }
 
 type WelcomeOptions struct {
@@ -36,18 +39,52 @@ type WelcomeOptions struct {
 }
 
 type ZoneOptions struct {
-	Seed          string `mapstructure:"seed"`
-	UseRandomSeed bool   `mapstructure:"use_random_seed"`
+	Seed          string  `mapstructure:"seed"`
+	UseRandomSeed bool    `mapstructure:"use_random_seed"`
+	ViewRadius    float32 `mapstructure:"view_radius"`
+}
+
+type StorageOptions struct {
+	Type string `mapstructure:"type"`
+	Path string `mapstructure:"path"`
+}
+
+type AccountOptions struct {
+	AutoRegister bool `mapstructure:"auto_register"`
+}
+
+type CaptureOptions struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+}
+
+type ShutdownOptions struct {
+	GracePeriod  time.Duration `mapstructure:"grace_period"`
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+}
+
+type CharacterOptions struct {
+	StartingLevel byte `mapstructure:"starting_level"`
+	MaxPerAccount int  `mapstructure:"max_per_account"`
+	// One word per line, names containing any of them are rejected
+	BlockedNamesFile string `mapstructure:"blocked_names_file"`
 }
 
 type RRConfig struct {
-	Network                  NetworkOptions `mapstructure:"network"`
-	SendMovementMessages     bool           `mapstructure:"send_movement_messages"`
-	Logging                  LoggingOptions `mapstructure:"logging"`
-	ReinitialiseZonesOnEnter bool           `mapstructure:"reinitialise_zones_on_enter"`
-	Welcome                  WelcomeOptions `mapstructure:"welcome"`
-	DefaultZone              string         `mapstructure:"default_zone"`
-	ZoneOptions              ZoneOptions    `mapstructure:"zone_options"`
+	Network                  NetworkOptions   `mapstructure:"network"`
+	SendMovementMessages     bool             `mapstructure:"send_movement_messages"`
+	Logging                  LoggingOptions   `mapstructure:"logging"`
+	ReinitialiseZonesOnEnter bool             `mapstructure:"reinitialise_zones_on_enter"`
+	Welcome                  WelcomeOptions   `mapstructure:"welcome"`
+	DefaultZone              string           `mapstructure:"default_zone"`
+	ZoneOptions              ZoneOptions      `mapstructure:"zone_options"`
+	Storage                  StorageOptions   `mapstructure:"storage"`
+	Accounts                 AccountOptions   `mapstructure:"accounts"`
+	Capture                  CaptureOptions   `mapstructure:"capture"`
+	Shutdown                 ShutdownOptions  `mapstructure:"shutdown"`
+	Characters               CharacterOptions `mapstructure:"characters"`
+	// GlobalKnobs overrides by field name, e.g. ExperienceMod
+	Knobs map[string]string `mapstructure:"knobs"`
 }
 
 func Load() {
@@ -56,6 +93,18 @@ func Load() {
 	viper.SetDefault("network.login_server_port", 2110)
 	viper.SetDefault("network.game_server_port", 2603)
 	viper.SetDefault("network.game_server_ip", "127.0.0.1")
+	viper.SetDefault("network.outbound_queue_size", 1024)
+	viper.SetDefault("network.write_timeout", "10s")
+	viper.SetDefault("storage.type", "file")
+	viper.SetDefault("storage.path", "./data/storage/characters.json")
+	viper.SetDefault("accounts.auto_register", false)
+	viper.SetDefault("capture.enabled", false)
+	viper.SetDefault("capture.path", "./data/captures")
+	viper.SetDefault("shutdown.grace_period", "30s")
+	viper.SetDefault("shutdown.drain_timeout", "5s")
+	viper.SetDefault("characters.starting_level", 1)
+	viper.SetDefault("characters.max_per_account", 4)
+	viper.SetDefault("characters.blocked_names_file", "./resources/blocked_names.txt")
 
 	viper.SetDefault("welcome.send_welcome_message", true)
 	viper.SetDefault("welcome.message", `Welcome to RainbowRunner!
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"RainbowRunner/internal/serverconfig"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+var Store CharacterStore
+
+func Init() {
+	store, err := newStore(serverconfig.Config.Storage)
+
+	if err != nil {
+		panic(err)
+	}
+
+	Store = store
+}
+
+func newStore(options serverconfig.StorageOptions) (CharacterStore, error) {
+	switch options.Type {
+	case "memory":
+		log.Warn("using in-memory character storage, all characters will be lost on restart")
+		return NewMemoryStore(), nil
+	case "file", "":
+		log.Infof("loading characters from %s", options.Path)
+		return NewFileStore(options.Path)
+	}
+
+	return nil, errors.New(fmt.Sprintf("unknown storage type %s", options.Type))
+}
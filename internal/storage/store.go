@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"RainbowRunner/pkg/datatypes"
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("not found")
+var ErrAlreadyExists = errors.New("already exists")
+
+// CharacterStore persists accounts and their characters between server restarts
+type CharacterStore interface {
+	GetAccount(name string) (*Account, error)
+	// CreateAccount returns ErrAlreadyExists if an account with the name exists, names are not case-sensitive
+	CreateAccount(account *Account) error
+	SaveAccount(account *Account) error
+
+	GetCharacter(id uint32) (*Character, error)
+	GetCharacters(accountName string) ([]*Character, error)
+	// GetCharacterByName finds a character on any account, names are not case-sensitive
+	GetCharacterByName(name string) (*Character, error)
+	// CreateCharacter assigns a new unique ID to the character before saving it
+	CreateCharacter(character *Character) error
+	SaveCharacter(character *Character) error
+	DeleteCharacter(id uint32) error
+
+	Close() error
+}
+
+type Account struct {
+	Name         string
+	PasswordHash string
+	Banned       bool
+	CreatedAt    time.Time
+	LastLogin    time.Time
+}
+
+type Appearance struct {
+	Face        byte
+	FaceFeature byte
+	Hair        byte
+	HairColour  byte
+	Skin        byte
+}
+
+type Character struct {
+	ID          uint32
+	AccountName string
+	Name        string
+	// Full GCType of the avatar class e.g. avatar.classes.FighterFemale
+	Class      string
+	Appearance Appearance
+	Level      byte
+	Experience uint32
+
+	Zone string
+	// Position is nil when the character should be placed at the zone start point
+	Position *datatypes.Vector3Float32
+	Heading  float32
+
+	Inventory []*ItemRecord
+	Equipment []*ItemRecord
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ItemRecord is a single item stored either in one of the character's inventories or equipped
+type ItemRecord struct {
+	GCType    string
+	ModGCType string
+	ItemType  string
+	// Slot the item can be equipped in, 0 for items that cannot be equipped
+	Slot uint32
+
+	InventoryID byte
+	X           int32
+	Y           int32
+}
+
+func (c *Character) Copy() *Character {
+	copied := *c
+
+	if c.Position != nil {
+		position := *c.Position
+		copied.Position = &position
+	}
+
+	copied.Inventory = copyItemRecords(c.Inventory)
+	copied.Equipment = copyItemRecords(c.Equipment)
+
+	return &copied
+}
+
+func copyItemRecords(records []*ItemRecord) []*ItemRecord {
+	if records == nil {
+		return nil
+	}
+
+	copied := make([]*ItemRecord, 0, len(records))
+
+	for _, record := range records {
+		item := *record
+		copied = append(copied, &item)
+	}
+
+	return copied
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"github.com/goccy/go-json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore keeps all accounts and characters in a single JSON file which is rewritten on every change
+type FileStore struct {
+	*MemoryStore
+
+	path      string
+	writeLock sync.Mutex
+}
+
+type fileStoreData struct {
+	NextCharacterID uint32
+	Accounts        map[string]*Account
+	Characters      map[uint32]*Character
+}
+
+func (s *FileStore) CreateAccount(account *Account) error {
+	if err := s.MemoryStore.CreateAccount(account); err != nil {
+		return err
+	}
+
+	return s.flush()
+}
+
+func (s *FileStore) SaveAccount(account *Account) error {
+	if err := s.MemoryStore.SaveAccount(account); err != nil {
+		return err
+	}
+
+	return s.flush()
+}
+
+func (s *FileStore) CreateCharacter(character *Character) error {
+	if err := s.MemoryStore.CreateCharacter(character); err != nil {
+		return err
+	}
+
+	return s.flush()
+}
+
+func (s *FileStore) SaveCharacter(character *Character) error {
+	if err := s.MemoryStore.SaveCharacter(character); err != nil {
+		return err
+	}
+
+	return s.flush()
+}
+
+func (s *FileStore) DeleteCharacter(id uint32) error {
+	if err := s.MemoryStore.DeleteCharacter(id); err != nil {
+		return err
+	}
+
+	return s.flush()
+}
+
+func (s *FileStore) Close() error {
+	return s.flush()
+}
+
+func (s *FileStore) load() error {
+	data, err := os.ReadFile(s.path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	stored := &fileStoreData{}
+
+	if err = json.Unmarshal(data, stored); err != nil {
+		return errors.New(fmt.Sprintf("failed to parse storage file %s: %s", s.path, err.Error()))
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if stored.Accounts != nil {
+		s.Accounts = stored.Accounts
+	}
+
+	if stored.Characters != nil {
+		s.Characters = stored.Characters
+	}
+
+	s.NextCharacterID = stored.NextCharacterID
+
+	for id := range s.Characters {
+		if id >= s.NextCharacterID {
+			s.NextCharacterID = id + 1
+		}
+	}
+
+	if s.NextCharacterID == 0 {
+		s.NextCharacterID = 1
+	}
+
+	return nil
+}
+
+// flush writes to a temporary file first so a crash mid write never leaves a truncated store behind
+func (s *FileStore) flush() error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	s.RLock()
+	data, err := json.MarshalIndent(&fileStoreData{
+		NextCharacterID: s.NextCharacterID,
+		Accounts:        s.Accounts,
+		Characters:      s.Characters,
+	}, "", "  ")
+	s.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+
+	if err = os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.path)
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	store := &FileStore{
+		MemoryStore: NewMemoryStore(),
+		path:        path,
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
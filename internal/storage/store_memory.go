@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps everything in memory and loses it on restart, it is intended for tests and throwaway servers
+type MemoryStore struct {
+	sync.RWMutex
+
+	Accounts        map[string]*Account
+	Characters      map[uint32]*Character
+	NextCharacterID uint32
+}
+
+func (s *MemoryStore) GetAccount(name string) (*Account, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	account, ok := s.Accounts[strings.ToLower(name)]
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	copied := *account
+
+	return &copied, nil
+}
+
+func (s *MemoryStore) CreateAccount(account *Account) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.Accounts[strings.ToLower(account.Name)]; ok {
+		return ErrAlreadyExists
+	}
+
+	copied := *account
+
+	if copied.CreatedAt.IsZero() {
+		copied.CreatedAt = time.Now()
+	}
+
+	s.Accounts[strings.ToLower(account.Name)] = &copied
+
+	return nil
+}
+
+func (s *MemoryStore) SaveAccount(account *Account) error {
+	s.Lock()
+	defer s.Unlock()
+
+	copied := *account
+
+	if copied.CreatedAt.IsZero() {
+		copied.CreatedAt = time.Now()
+	}
+
+	s.Accounts[strings.ToLower(account.Name)] = &copied
+
+	return nil
+}
+
+func (s *MemoryStore) GetCharacter(id uint32) (*Character, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	character, ok := s.Characters[id]
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return character.Copy(), nil
+}
+
+func (s *MemoryStore) GetCharacters(accountName string) ([]*Character, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	accountName = strings.ToLower(accountName)
+	list := make([]*Character, 0)
+
+	for _, character := range s.Characters {
+		if strings.ToLower(character.AccountName) == accountName {
+			list = append(list, character.Copy())
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+
+	return list, nil
+}
+
+func (s *MemoryStore) GetCharacterByName(name string) (*Character, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, character := range s.Characters {
+		if strings.EqualFold(character.Name, name) {
+			return character.Copy(), nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) CreateCharacter(character *Character) error {
+	s.Lock()
+	defer s.Unlock()
+
+	character.ID = s.NextCharacterID
+	s.NextCharacterID++
+
+	now := time.Now()
+	character.CreatedAt = now
+	character.UpdatedAt = now
+
+	s.Characters[character.ID] = character.Copy()
+
+	return nil
+}
+
+func (s *MemoryStore) SaveCharacter(character *Character) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.Characters[character.ID]; !ok {
+		return ErrNotFound
+	}
+
+	character.UpdatedAt = time.Now()
+	s.Characters[character.ID] = character.Copy()
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteCharacter(id uint32) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.Characters[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.Characters, id)
+
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Accounts:        make(map[string]*Account),
+		Characters:      make(map[uint32]*Character),
+		NextCharacterID: 1,
+	}
+}
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"RainbowRunner/pkg/datatypes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestCharacter(account string, name string) *Character {
+	return &Character{
+		AccountName: account,
+		Name:        name,
+		Class:       "avatar.classes.FighterFemale",
+		Level:       1,
+		Zone:        "town",
+		Position:    &datatypes.Vector3Float32{X: 1, Y: 2, Z: 3},
+		Inventory:   []*ItemRecord{{GCType: "item", InventoryID: 0x0B, X: 1, Y: 2}},
+	}
+}
+
+// testStore runs the same checks against every store so the file store behaves like the memory store it wraps
+func testStore(t *testing.T, store CharacterStore) {
+	if _, err := store.GetAccount("tester"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for a missing account, expected ErrNotFound", err)
+	}
+
+	if err := store.CreateAccount(&Account{Name: "Tester", PasswordHash: "hash"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.CreateAccount(&Account{Name: "TESTER"}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("got %v creating an account that exists, expected ErrAlreadyExists", err)
+	}
+
+	account, err := store.GetAccount("tester")
+
+	if err != nil || account.PasswordHash != "hash" || account.CreatedAt.IsZero() {
+		t.Fatalf("got account %+v, %v", account, err)
+	}
+
+	// Accounts are copied so changes aren't stored until they are saved
+	account.Banned = true
+
+	if stored, _ := store.GetAccount("tester"); stored.Banned {
+		t.Error("changing the returned account changed the stored account")
+	}
+
+	if err = store.SaveAccount(account); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, _ := store.GetAccount("tester"); !stored.Banned {
+		t.Error("saved account was not changed")
+	}
+
+	first := newTestCharacter("Tester", "Ellie")
+	second := newTestCharacter("tester", "Ella")
+
+	for _, character := range []*Character{first, second} {
+		if err = store.CreateCharacter(character); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if first.ID == 0 || first.ID == second.ID || first.CreatedAt.IsZero() {
+		t.Fatalf("characters were given IDs %d and %d", first.ID, second.ID)
+	}
+
+	characters, err := store.GetCharacters("TESTER")
+
+	if err != nil || len(characters) != 2 || characters[0].ID != first.ID || characters[1].ID != second.ID {
+		t.Fatalf("got characters %v, %v", characters, err)
+	}
+
+	found, err := store.GetCharacterByName("ELLIE")
+
+	if err != nil || found.ID != first.ID {
+		t.Errorf("got %v, %v finding a character by name", found, err)
+	}
+
+	if _, err = store.GetCharacterByName("Other"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for a missing character name, expected ErrNotFound", err)
+	}
+
+	// Characters are copied so changes aren't stored until they are saved
+	found.Position.X = 10
+	found.Inventory[0].X = 5
+
+	if stored, _ := store.GetCharacter(first.ID); stored.Position.X != 1 || stored.Inventory[0].X != 1 {
+		t.Error("changing the returned character changed the stored character")
+	}
+
+	found.Level = 2
+
+	if err = store.SaveCharacter(found); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, _ := store.GetCharacter(first.ID); stored.Level != 2 || stored.Position.X != 10 || stored.Inventory[0].X != 5 {
+		t.Error("saved character was not changed")
+	}
+
+	if err = store.SaveCharacter(&Character{ID: 1000}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v saving a character that was never created, expected ErrNotFound", err)
+	}
+
+	if err = store.DeleteCharacter(second.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.GetCharacter(second.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for a deleted character, expected ErrNotFound", err)
+	}
+
+	if err = store.DeleteCharacter(second.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v deleting a character twice, expected ErrNotFound", err)
+	}
+
+	// IDs are never reused so old references can't point at a new character
+	third := newTestCharacter("tester", "Elle")
+
+	if err = store.CreateCharacter(third); err != nil {
+		t.Fatal(err)
+	}
+
+	if third.ID <= second.ID {
+		t.Errorf("new character was given ID %d after %d was deleted", third.ID, second.ID)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage", "characters.json")
+	store, err := NewFileStore(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStore(t, store)
+
+	if err = store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The temporary file is renamed over the store so it is never left behind
+	if _, err = os.Stat(path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("temporary file was left behind: %v", err)
+	}
+
+	// Everything is loaded back after a restart
+	reloaded, err := NewFileStore(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := reloaded.GetAccount("TESTER")
+
+	if err != nil || !account.Banned {
+		t.Errorf("got account %+v, %v after reloading", account, err)
+	}
+
+	characters, err := reloaded.GetCharacters("tester")
+
+	if err != nil || len(characters) != 2 {
+		t.Fatalf("got characters %v, %v after reloading", characters, err)
+	}
+
+	ellie := characters[0]
+
+	if ellie.Name != "Ellie" || ellie.Level != 2 || ellie.Position == nil || ellie.Position.X != 10 ||
+		len(ellie.Inventory) != 1 || ellie.Inventory[0].InventoryID != 0x0B || ellie.Inventory[0].X != 5 {
+		t.Errorf("got %+v after reloading", ellie)
+	}
+
+	if _, err = reloaded.GetCharacterByName("Ella"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for a deleted character after reloading, expected ErrNotFound", err)
+	}
+
+	// The next ID carries on from before the restart
+	next := newTestCharacter("tester", "Elsa")
+
+	if err = reloaded.CreateCharacter(next); err != nil {
+		t.Fatal(err)
+	}
+
+	if next.ID <= characters[1].ID {
+		t.Errorf("character created after reloading was given ID %d", next.ID)
+	}
+}
+
+func TestFileStoreMissingFile(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "characters.json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.GetAccount("tester"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v from a new store, expected ErrNotFound", err)
+	}
+
+	if _, err = store.GetCharacter(1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v from a new store, expected ErrNotFound", err)
+	}
+}
+
+func TestFileStoreKeepsFileWhenSaveFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "characters.json")
+	store, err := NewFileStore(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.CreateAccount(&Account{Name: "tester"}); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory in the way of the temporary file makes the write fail before the store is replaced
+	if err = os.Mkdir(path+".tmp", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.CreateAccount(&Account{Name: "other"}); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+
+	after, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(after) != string(before) {
+		t.Error("the store file was changed by a failed save")
+	}
+}
+
+func TestFileStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "characters.json")
+
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("expected an error loading an invalid store file")
+	}
+}
@@ -3,6 +3,7 @@ package synchronisation
 import (
 	"RainbowRunner/internal/message"
 	"RainbowRunner/internal/objects"
+	"RainbowRunner/pkg/byter"
 	"RainbowRunner/pkg/events"
 )
 
@@ -15,20 +16,14 @@ func Init() {
 			return
 		}
 
-		players := zone.Players()
+		zone.NotifyNearbyPlayers(behavior, nil, message.OpTypeBehaviourAction, func() *byter.Byter {
+			CEWriter := objects.NewClientEntityWriterWithByter()
+			CEWriter.BeginComponentUpdate(behavior)
+			CEWriter.CreateActionComplete(event.Action)
+			CEWriter.WriteSynch(behavior)
 
-		CEWriter := objects.NewClientEntityWriterWithByter()
-		CEWriter.BeginComponentUpdate(behavior)
-		CEWriter.CreateActionComplete(event.Action)
-		CEWriter.WriteSynch(behavior)
-
-		for _, player := range players {
-			//if player.Conn.GetID() == int(behavior.OwnerID()) {
-			//	continue
-			//}
-
-			player.MessageQueue.Enqueue(message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeBehaviourAction)
-		}
+			return CEWriter.Body
+		})
 	})
 
 	events.RegisterHandler[objects.PlayerMoveEvent](onPlayerMove)
@@ -2,6 +2,7 @@ package synchronisation
 
 import (
 	"RainbowRunner/internal/actions"
+	"RainbowRunner/internal/message"
 	"RainbowRunner/internal/objects"
 	"RainbowRunner/internal/types"
 	"RainbowRunner/pkg/byter"
@@ -33,7 +34,7 @@ func synchronisePlayerMovement(zone *objects.Zone) {
 			moveTo.PosX = newPos.X
 			moveTo.PosY = newPos.Y
 
-			zone.NotifyPlayers(types.Pointer(behaviour.OwnerID()), func() *byter.Byter {
+			zone.NotifyNearbyPlayers(behaviour, types.Pointer(behaviour.OwnerID()), message.OpTypeCreateEntity, func() *byter.Byter {
 				CEWriter := objects.NewClientEntityWriterWithByter()
 				CEWriter.BeginComponentUpdate(behaviour)
 				CEWriter.CreateActionComplete(moveTo)
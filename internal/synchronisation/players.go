@@ -3,6 +3,8 @@ package synchronisation
 import (
 	"RainbowRunner/internal/message"
 	"RainbowRunner/internal/objects"
+	"RainbowRunner/internal/types"
+	"RainbowRunner/pkg/byter"
 )
 
 func onPlayerMove(event objects.PlayerMoveEvent) {
@@ -37,11 +39,13 @@ func onPlayerMove(event objects.PlayerMoveEvent) {
 	//	CEWriter.EndComponentUpdate(event.UnitBehavior)
 	//}
 
-	for _, player := range event.UnitBehavior.RREntityProperties().Zone.Players() {
-		if uint16(player.Conn.GetID()) == event.UnitBehavior.OwnerID() {
-			continue
-		}
+	zone := event.UnitBehavior.RREntityProperties().Zone
 
-		player.MessageQueue.Enqueue(message.QueueTypeClientEntity, CEWriter.Body, message.OpTypeAvatarMovementOthers)
+	if zone == nil {
+		return
 	}
+
+	zone.NotifyNearbyPlayers(event.UnitBehavior, types.Pointer(event.UnitBehavior.OwnerID()), message.OpTypeAvatarMovementOthers, func() *byter.Byter {
+		return CEWriter.Body
+	})
 }
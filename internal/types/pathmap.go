@@ -33,9 +33,10 @@ type PathNode struct {
 func (p PathMap) WorldPosToGridCoords(pos datatypes.Vector2Float32) datatypes.Vector2 {
 	offsetPos := pos.Add(p.Offset.ToVector2Float32())
 
+	// Floored so negative coordinates land in the same cell GridCoordsToWorldPos returns the corner of
 	return datatypes.Vector2{
-		X: int32((offsetPos.X - 10*p.TileWidth) / 10),
-		Y: int32((offsetPos.Y - 10*p.TileHeight) / 10),
+		X: int32(math.Floor(float64(offsetPos.X-10*p.TileWidth) / 10)),
+		Y: int32(math.Floor(float64(offsetPos.Y-10*p.TileHeight) / 10)),
 	}
 }
 
@@ -121,6 +122,10 @@ func (p PathMap) AbsoluteGridCoordsToRelative(coords datatypes.Vector2) datatype
 func (p PathMap) GetNode(coords datatypes.Vector2) *PathNode {
 	absCoords := p.GridCoordsToAbsolute(coords)
 
+	if absCoords.X < 0 || absCoords.Y < 0 || int(absCoords.X) >= p.ChunkWidth*16 || int(absCoords.Y) >= p.ChunkHeight*16 {
+		return nil
+	}
+
 	//ccX := ()
 
 	//chunkX := (p.ChunkWidth - 1) - int(absCoords.X/16)
@@ -143,7 +148,7 @@ func (p PathMap) GetNode(coords datatypes.Vector2) *PathNode {
 	remainderY := absCoords.Y % 16
 	innerIndex := int(remainderX + remainderY*16)
 
-	if innerIndex > len(nodes) {
+	if innerIndex >= len(nodes) {
 		return nil
 	}
 
@@ -152,6 +157,13 @@ func (p PathMap) GetNode(coords datatypes.Vector2) *PathNode {
 	return &node
 }
 
+// IsWalkable solid nodes are the ground units can stand on, anything else is a wall or outside the map
+func (p PathMap) IsWalkable(coords datatypes.Vector2) bool {
+	node := p.GetNode(coords)
+
+	return node != nil && node.Solid
+}
+
 func (p PathMap) HeightAtGridCoords(coords datatypes.Vector2) float32 {
 	node := p.GetNode(coords)
 
@@ -3,19 +3,24 @@ package main
 import (
 	"RainbowRunner/internal/admin"
 	"RainbowRunner/internal/api"
+	"RainbowRunner/internal/capture"
 	"RainbowRunner/internal/database"
 	"RainbowRunner/internal/game"
 	"RainbowRunner/internal/logging"
 	"RainbowRunner/internal/login"
 	"RainbowRunner/internal/lua"
+	"RainbowRunner/internal/names"
 	"RainbowRunner/internal/objects"
 	"RainbowRunner/internal/serverconfig"
+	"RainbowRunner/internal/storage"
 	"flag"
 	"github.com/pkg/profile"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
-var done = make(chan bool)
-
 var (
 	profiledEnabled = flag.Bool("profile", false, "enable profiling")
 )
@@ -35,6 +40,9 @@ func main() {
 
 	database.LoadEquipmentFixtures()
 	database.LoadConfigFiles()
+	storage.Init()
+	names.Init()
+	capture.Init()
 
 	go login.StartLoginServer()
 	go game.StartGameServer()
@@ -43,7 +51,26 @@ func main() {
 
 	objects.Init()
 
-	for !<-done {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-signals
+	log.Infof("received %s, shutting down", sig)
+
+	go func() {
+		<-signals
+		log.Warn("received a second signal, stopping immediately")
+		os.Exit(1)
+	}()
 
+	login.StopLoginServer()
+	game.Shutdown()
+
+	if err := storage.Store.Close(); err != nil {
+		log.Errorf("failed to close character storage: %s", err.Error())
 	}
+
+	capture.Close()
+
+	log.Info("shutdown complete")
 }
@@ -139,11 +139,8 @@ func (b *Byter) UInt64() uint64 {
 	var result uint64 = 0
 
 	if b.littleEndian {
-		i := b.getDataIndex(4)
-		result |= uint64(binary.LittleEndian.Uint32(b.Buffer[i:])) << 32
-
-		i = b.getDataIndex(4)
-		result |= uint64(binary.LittleEndian.Uint32(b.Buffer[i:]))
+		i := b.getDataIndex(8)
+		result = binary.LittleEndian.Uint64(b.Buffer[i:])
 	} else {
 		i := b.getDataIndex(4)
 		result |= uint64(binary.BigEndian.Uint32(b.Buffer[i:])) << 32
@@ -21,3 +21,24 @@ func DecryptDES(encrypted []byte, encryptedSize int) []byte {
 	}
 	return decrypted
 }
+
+func EncryptDES(plainText []byte, length int) []byte {
+	start := 0
+	blockSize := 8
+
+	block, err := des.NewCipher([]byte{'T', 'E', 'S', 'T', 0, 0, 0, 0})
+
+	if err != nil {
+		panic(err)
+	}
+
+	encrypted := make([]byte, length)
+
+	for ; start < length; start += blockSize {
+		end := start + blockSize
+
+		block.Encrypt(encrypted[start:], plainText[start:end])
+	}
+
+	return encrypted
+}
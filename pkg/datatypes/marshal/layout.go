@@ -0,0 +1,389 @@
+package marshal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type wireType int
+
+const (
+	wireNone wireType = iota
+	wireU8
+	wireBool
+	wireU16
+	wireI16
+	wireU24
+	wireI24
+	wireU32
+	wireI32
+	wireU64
+	wireF32
+	wireDRFloat
+	wireCString
+	wireBytes
+	wireStruct
+	wireSlice
+)
+
+var wireTypeNames = map[string]wireType{
+	"u8":      wireU8,
+	"byte":    wireU8,
+	"bool":    wireBool,
+	"u16":     wireU16,
+	"i16":     wireI16,
+	"u24":     wireU24,
+	"i24":     wireI24,
+	"u32":     wireU32,
+	"i32":     wireI32,
+	"u64":     wireU64,
+	"f32":     wireF32,
+	"drfloat": wireDRFloat,
+	"cstring": wireCString,
+	"bytes":   wireBytes,
+}
+
+type conditionOp string
+
+const (
+	conditionNonZero  conditionOp = ""
+	conditionMask     conditionOp = "&"
+	conditionEqual    conditionOp = "=="
+	conditionNotEqual conditionOp = "!="
+)
+
+// condition makes a field optional based on the value of an earlier field
+type condition struct {
+	field int
+	op    conditionOp
+	value uint64
+}
+
+type field struct {
+	index int
+	name  string
+	wire  wireType
+	cond  *condition
+
+	// Slices and byte slices are either counted by an earlier field, by a count written before them or fill the
+	// remaining data when neither is set
+	lenField int
+	prefix   wireType
+	size     int
+
+	// elem describes the elements of a slice
+	elem   *field
+	layout *layout
+}
+
+type layout struct {
+	hasHeader   bool
+	channel     byte
+	messageType byte
+	fields      []*field
+}
+
+var layouts sync.Map
+
+func getLayout(t reflect.Type) (*layout, error) {
+	if cached, ok := layouts.Load(t); ok {
+		return cached.(*layout), nil
+	}
+
+	l, err := newLayout(t)
+
+	if err != nil {
+		return nil, err
+	}
+
+	layouts.Store(t, l)
+
+	return l, nil
+}
+
+func newLayout(t reflect.Type) (*layout, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New(fmt.Sprintf("%s is not a struct", t.String()))
+	}
+
+	l := &layout{}
+	names := make(map[string]int)
+
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		tag, hasTag := structField.Tag.Lookup("dr")
+
+		if tag == "-" {
+			continue
+		}
+
+		// The blank field holds the channel and message type of the message
+		if structField.Name == "_" {
+			if hasTag {
+				if err := l.parseHeader(tag); err != nil {
+					return nil, errors.New(fmt.Sprintf("%s: %s", t.String(), err.Error()))
+				}
+			}
+
+			continue
+		}
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		f, err := newField(structField, tag, names)
+
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("%s.%s: %s", t.String(), structField.Name, err.Error()))
+		}
+
+		f.index = i
+		names[structField.Name] = i
+		l.fields = append(l.fields, f)
+	}
+
+	return l, nil
+}
+
+func (l *layout) parseHeader(tag string) error {
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		number, err := strconv.ParseUint(value, 0, 8)
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("invalid header option %s", option))
+		}
+
+		switch key {
+		case "channel":
+			l.channel = byte(number)
+		case "type":
+			l.messageType = byte(number)
+		default:
+			return errors.New(fmt.Sprintf("unknown header option %s", key))
+		}
+	}
+
+	l.hasHeader = true
+
+	return nil
+}
+
+func newField(structField reflect.StructField, tag string, names map[string]int) (*field, error) {
+	f := &field{
+		name:     structField.Name,
+		lenField: -1,
+	}
+
+	options := strings.Split(tag, ",")
+
+	if len(options) > 0 && options[0] != "" && !strings.Contains(options[0], "=") {
+		wire, ok := wireTypeNames[options[0]]
+
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("unknown wire type %s", options[0]))
+		}
+
+		f.wire = wire
+		options = options[1:]
+	}
+
+	for _, option := range options {
+		if option == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(option, "=")
+
+		var err error
+
+		switch key {
+		case "if":
+			f.cond, err = parseCondition(value, names)
+		case "len":
+			index, ok := names[value]
+
+			if !ok {
+				err = errors.New(fmt.Sprintf("len field %s must come before the slice", value))
+			}
+
+			f.lenField = index
+		case "prefix":
+			wire, ok := wireTypeNames[value]
+
+			if !ok {
+				err = errors.New(fmt.Sprintf("unknown prefix type %s", value))
+			}
+
+			f.prefix = wire
+		case "size":
+			f.size, err = strconv.Atoi(value)
+		default:
+			err = errors.New(fmt.Sprintf("unknown option %s", key))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, f.resolve(structField.Type)
+}
+
+// resolve fills in the wire type from the Go type when the tag does not give one and checks they are compatible
+func (f *field) resolve(t reflect.Type) error {
+	if t == drFloatType && f.wire == wireNone {
+		f.wire = wireI32
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		l, err := getLayout(t)
+
+		if err != nil {
+			return err
+		}
+
+		f.wire = wireStruct
+		f.layout = l
+
+		return nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && (f.wire == wireNone || f.wire == wireBytes) {
+			f.wire = wireBytes
+
+			if t.Kind() == reflect.Array {
+				f.size = t.Len()
+			}
+
+			return nil
+		}
+
+		if t.Kind() == reflect.Array {
+			return errors.New("only byte arrays are supported")
+		}
+
+		// The wire type in the tag describes the elements
+		elem := &field{name: f.name, wire: f.wire, lenField: -1}
+
+		if err := elem.resolve(t.Elem()); err != nil {
+			return err
+		}
+
+		f.wire = wireSlice
+		f.elem = elem
+
+		return nil
+	}
+
+	if f.wire == wireNone {
+		wire, ok := defaultWireType(t.Kind())
+
+		if !ok {
+			return errors.New(fmt.Sprintf("cannot encode %s without a wire type", t.String()))
+		}
+
+		f.wire = wire
+	}
+
+	if !compatible(f.wire, t.Kind()) {
+		return errors.New(fmt.Sprintf("wire type %d cannot be used with %s", f.wire, t.String()))
+	}
+
+	return nil
+}
+
+func defaultWireType(kind reflect.Kind) (wireType, bool) {
+	switch kind {
+	case reflect.Uint8:
+		return wireU8, true
+	case reflect.Bool:
+		return wireBool, true
+	case reflect.Uint16:
+		return wireU16, true
+	case reflect.Int16:
+		return wireI16, true
+	case reflect.Uint32:
+		return wireU32, true
+	case reflect.Int32:
+		return wireI32, true
+	case reflect.Uint64:
+		return wireU64, true
+	case reflect.Float32:
+		return wireF32, true
+	case reflect.String:
+		return wireCString, true
+	}
+
+	return wireNone, false
+}
+
+func compatible(wire wireType, kind reflect.Kind) bool {
+	switch wire {
+	case wireCString:
+		return kind == reflect.String
+	case wireF32, wireDRFloat:
+		return kind == reflect.Float32 || kind == reflect.Float64
+	case wireBool:
+		return kind == reflect.Bool
+	}
+
+	return isInteger(kind) || kind == reflect.Bool
+}
+
+func isInteger(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uint64
+}
+
+// parseCondition parses Field, Field&0x02, Field==1 and Field!=1
+func parseCondition(value string, names map[string]int) (*condition, error) {
+	c := &condition{op: conditionNonZero}
+	name := value
+
+	for _, op := range []conditionOp{conditionEqual, conditionNotEqual, conditionMask} {
+		if left, right, ok := strings.Cut(value, string(op)); ok {
+			number, err := strconv.ParseUint(right, 0, 64)
+
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid condition %s", value))
+			}
+
+			c.op = op
+			c.value = number
+			name = left
+
+			break
+		}
+	}
+
+	index, ok := names[name]
+
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("condition field %s must come before the field", name))
+	}
+
+	c.field = index
+
+	return c, nil
+}
+
+func (c *condition) matches(v reflect.Value) bool {
+	value := toUint64(v.Field(c.field))
+
+	switch c.op {
+	case conditionMask:
+		return value&c.value != 0
+	case conditionEqual:
+		return value == c.value
+	case conditionNotEqual:
+		return value != c.value
+	}
+
+	return value != 0
+}
@@ -0,0 +1,356 @@
+// Package marshal encodes and decodes messages described with `dr` struct tags.
+//
+// The first tag value is the wire type, when it is left out it is picked from the Go type. Structs are written
+// inline, slices are written element by element and a blank field holds the channel and message type.
+//
+//	type ExampleMessage struct {
+//		_     struct{} `dr:"channel=13,type=6"`
+//		Flags byte
+//		ID    uint32   `dr:"u24"`
+//		Name  string   `dr:"cstring,if=Flags&0x01"`
+//		Count byte
+//		Items []Item   `dr:"len=Count"`
+//		Rest  []byte
+//	}
+//
+// Wire types: u8, bool, u16, i16, u24, i24 (the top bit is the sign), u32, i32, u64, f32, drfloat (a float written as
+// int32 * 256), cstring and bytes. Options: if=Field, if=Field&mask, if=Field==value and if=Field!=value only write
+// the field when the earlier field matches, len=Field takes a slice length from an earlier field, prefix=u8 writes the
+// length before the slice and size=N is a fixed byte length. Slices without a length take up the rest of the message.
+package marshal
+
+import (
+	"RainbowRunner/pkg/byter"
+	"RainbowRunner/pkg/datatypes/drfloat"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+var drFloatType = reflect.TypeOf(drfloat.DRFloat(0))
+
+var ErrHeaderMismatch = errors.New("message channel or type does not match")
+
+// Marshal encodes the message including the channel and message type
+func Marshal(v interface{}) ([]byte, error) {
+	b := byter.NewLEByter(make([]byte, 0, 64))
+
+	if err := Encode(b, v); err != nil {
+		return nil, err
+	}
+
+	return b.Data(), nil
+}
+
+// Unmarshal decodes a whole message including the channel and message type
+func Unmarshal(data []byte, v interface{}) error {
+	return Decode(byter.NewLEByter(data), v)
+}
+
+// Encode writes the message channel and type, when the message has them, followed by every field
+func Encode(b *byter.Byter, v interface{}) error {
+	value, l, err := structValue(v)
+
+	if err != nil {
+		return err
+	}
+
+	if l.hasHeader {
+		b.WriteByte(l.channel)
+		b.WriteByte(l.messageType)
+	}
+
+	return encodeStruct(b, value, l)
+}
+
+// EncodeFields writes every field without the channel and message type
+func EncodeFields(b *byter.Byter, v interface{}) error {
+	value, l, err := structValue(v)
+
+	if err != nil {
+		return err
+	}
+
+	return encodeStruct(b, value, l)
+}
+
+// Decode reads the message channel and type, when the message has them, and then every field
+func Decode(b *byter.Byter, v interface{}) (err error) {
+	value, l, err := pointerValue(v)
+
+	if err != nil {
+		return err
+	}
+
+	defer recoverDecode(&err)
+
+	if l.hasHeader {
+		channel := b.UInt8()
+		messageType := b.UInt8()
+
+		if channel != l.channel || messageType != l.messageType {
+			return ErrHeaderMismatch
+		}
+	}
+
+	decodeStruct(b, value, l)
+
+	return nil
+}
+
+// DecodeFields reads every field, used by channel handlers where the channel and message type are already read
+func DecodeFields(b *byter.Byter, v interface{}) (err error) {
+	value, l, err := pointerValue(v)
+
+	if err != nil {
+		return err
+	}
+
+	defer recoverDecode(&err)
+
+	decodeStruct(b, value, l)
+
+	return nil
+}
+
+// Header returns the channel and message type of a message type
+func Header(v interface{}) (channel byte, messageType byte, ok bool) {
+	t := reflect.TypeOf(v)
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	l, err := getLayout(t)
+
+	if err != nil || !l.hasHeader {
+		return 0, 0, false
+	}
+
+	return l.channel, l.messageType, true
+}
+
+// The byter panics when reading past the end of the data
+func recoverDecode(err *error) {
+	if r := recover(); r != nil {
+		*err = errors.New(fmt.Sprintf("failed to decode message: %v", r))
+	}
+}
+
+func structValue(v interface{}) (reflect.Value, *layout, error) {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	l, err := getLayout(value.Type())
+
+	return value, l, err
+}
+
+func pointerValue(v interface{}) (reflect.Value, *layout, error) {
+	value := reflect.ValueOf(v)
+
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return value, nil, errors.New("decode requires a non-nil pointer")
+	}
+
+	value = value.Elem()
+	l, err := getLayout(value.Type())
+
+	return value, l, err
+}
+
+func encodeStruct(b *byter.Byter, value reflect.Value, l *layout) error {
+	for _, f := range l.fields {
+		if f.cond != nil && !f.cond.matches(value) {
+			continue
+		}
+
+		fieldValue := value.Field(f.index)
+
+		if f.lenField >= 0 && int(toUint64(value.Field(f.lenField))) != fieldValue.Len() {
+			return errors.New(fmt.Sprintf(
+				"%s has %d elements but its length field is %d",
+				f.name, fieldValue.Len(), toUint64(value.Field(f.lenField)),
+			))
+		}
+
+		if err := encodeField(b, fieldValue, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeField(b *byter.Byter, v reflect.Value, f *field) error {
+	switch f.wire {
+	case wireStruct:
+		return encodeStruct(b, v, f.layout)
+	case wireBytes:
+		data := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(data), v)
+
+		if f.size > 0 && len(data) != f.size {
+			return errors.New(fmt.Sprintf("%s must be %d bytes", f.name, f.size))
+		}
+
+		if f.prefix != wireNone {
+			writeInteger(b, f.prefix, uint64(len(data)))
+		}
+
+		b.WriteBytes(data)
+	case wireSlice:
+		if f.prefix != wireNone {
+			writeInteger(b, f.prefix, uint64(v.Len()))
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeField(b, v.Index(i), f.elem); err != nil {
+				return err
+			}
+		}
+	case wireCString:
+		b.WriteCString(v.String())
+	case wireF32:
+		b.WriteFloat32(float32(v.Float()))
+	case wireDRFloat:
+		b.WriteInt32(int32(v.Float() * 256))
+	default:
+		writeInteger(b, f.wire, toUint64(v))
+	}
+
+	return nil
+}
+
+func writeInteger(b *byter.Byter, wire wireType, value uint64) {
+	switch wire {
+	case wireU8, wireBool:
+		b.WriteByte(byte(value))
+	case wireU16, wireI16:
+		b.WriteUInt16(uint16(value))
+	case wireU24:
+		b.WriteUInt24(uint(value))
+	case wireI24:
+		b.WriteInt24(int32(value))
+	case wireU32, wireI32:
+		b.WriteUInt32(uint32(value))
+	case wireU64:
+		b.WriteUInt64(value)
+	}
+}
+
+func decodeStruct(b *byter.Byter, value reflect.Value, l *layout) {
+	for _, f := range l.fields {
+		if f.cond != nil && !f.cond.matches(value) {
+			continue
+		}
+
+		length := -1
+
+		if f.lenField >= 0 {
+			length = int(toUint64(value.Field(f.lenField)))
+		}
+
+		decodeField(b, value.Field(f.index), f, length)
+	}
+}
+
+func decodeField(b *byter.Byter, v reflect.Value, f *field, length int) {
+	if f.prefix != wireNone {
+		length = int(readInteger(b, f.prefix))
+	}
+
+	switch f.wire {
+	case wireStruct:
+		decodeStruct(b, v, f.layout)
+	case wireBytes:
+		if f.size > 0 {
+			length = f.size
+		}
+
+		if length < 0 {
+			length = len(b.Buffer) - b.I
+		}
+
+		data := b.Bytes(length)
+
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(v.Type(), length, length))
+		}
+
+		reflect.Copy(v, reflect.ValueOf(data))
+	case wireSlice:
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+
+		for i := 0; length < 0 && b.I < len(b.Buffer) || i < length; i++ {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			decodeField(b, elem, f.elem, -1)
+			v.Set(reflect.Append(v, elem))
+		}
+	case wireCString:
+		v.SetString(b.CString())
+	case wireF32:
+		v.SetFloat(float64(b.Float32()))
+	case wireDRFloat:
+		v.SetFloat(float64(b.Int32()) / 256)
+	default:
+		setInteger(v, readInteger(b, f.wire))
+	}
+}
+
+func readInteger(b *byter.Byter, wire wireType) uint64 {
+	switch wire {
+	case wireU8, wireBool:
+		return uint64(b.UInt8())
+	case wireU16:
+		return uint64(b.UInt16())
+	case wireI16:
+		return uint64(b.Int16())
+	case wireU24:
+		return uint64(b.UInt24())
+	case wireI24:
+		return uint64(b.Int24())
+	case wireU32:
+		return uint64(b.UInt32())
+	case wireI32:
+		return uint64(b.Int32())
+	case wireU64:
+		return b.UInt64()
+	}
+
+	return 0
+}
+
+func setInteger(v reflect.Value, value uint64) {
+	switch {
+	case v.Kind() == reflect.Bool:
+		v.SetBool(value != 0)
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		v.SetInt(int64(value))
+	default:
+		v.SetUint(value)
+	}
+}
+
+func toUint64(v reflect.Value) uint64 {
+	switch {
+	case v.Kind() == reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+
+		return 0
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		return uint64(v.Int())
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uintptr:
+		return v.Uint()
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		return uint64(math.Float64bits(v.Float()))
+	}
+
+	return 0
+}
@@ -0,0 +1,292 @@
+package marshal
+
+import (
+	"RainbowRunner/pkg/datatypes/drfloat"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testPosition struct {
+	X float32 `dr:"drfloat"`
+	Y float32 `dr:"drfloat"`
+	Z drfloat.DRFloat
+}
+
+type testItem struct {
+	ID   uint32 `dr:"u24"`
+	Name string
+}
+
+type testMessage struct {
+	_        struct{} `dr:"channel=13,type=6"`
+	Flags    byte
+	EntityID uint32 `dr:"u24"`
+	Offset   int32  `dr:"i24"`
+	Small    int16
+	Big      uint64
+	Enabled  bool
+	Speed    float32
+	Name     string `dr:"cstring,if=Flags&0x01"`
+	Title    string `dr:"if=Flags&0x02"`
+	Mode     byte
+	Target   uint16       `dr:"if=Mode==2"`
+	Fallback uint16       `dr:"if=Mode!=2"`
+	Position testPosition `dr:"if=Flags"`
+	Count    byte
+	Items    []testItem `dr:"len=Count"`
+	Tags     []string   `dr:"prefix=u8"`
+	Blob     []byte     `dr:"prefix=u16"`
+	Hash     [4]byte
+	Key      []byte   `dr:"size=3"`
+	Codes    []uint16 `dr:"u16,prefix=u8"`
+	Rest     []byte
+}
+
+type testHeaderOnly struct {
+	_     struct{} `dr:"channel=13,type=7"`
+	Value uint32
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		message testMessage
+	}{
+		{
+			name: "everything set",
+			message: testMessage{
+				Flags:    0x03,
+				EntityID: 0xABCDEF,
+				Offset:   -1234,
+				Small:    -2,
+				Big:      0x0102030405060708,
+				Enabled:  true,
+				Speed:    1.5,
+				Name:     "Ellie",
+				Title:    "the Brave",
+				Mode:     2,
+				Target:   0x1234,
+				Position: testPosition{X: 10.5, Y: -3.25, Z: drfloat.FromFloat32(2)},
+				Count:    2,
+				Items:    []testItem{{ID: 1, Name: "Sword"}, {ID: 0xFFFFFF, Name: ""}},
+				Tags:     []string{"a", "bc"},
+				Blob:     []byte{0x01, 0x02, 0x03},
+				Hash:     [4]byte{0xDE, 0xAD, 0xBE, 0xEF},
+				Key:      []byte{0x07, 0x08, 0x09},
+				Codes:    []uint16{0xFFFF, 0x0001},
+				Rest:     []byte{0x10, 0x20},
+			},
+		},
+		{
+			name: "conditions off",
+			message: testMessage{
+				Flags:    0x00,
+				EntityID: 1,
+				Offset:   0x7FFFFF,
+				Mode:     1,
+				Fallback: 0x4321,
+				Items:    []testItem{},
+				Tags:     []string{},
+				Blob:     []byte{},
+				Key:      []byte{0x00, 0x00, 0x00},
+				Codes:    []uint16{},
+				Rest:     []byte{},
+			},
+		},
+		{
+			name: "only one mask bit",
+			message: testMessage{
+				Flags:    0x02,
+				Offset:   -0x7FFFFF,
+				Title:    "Mask",
+				Mode:     2,
+				Target:   7,
+				Position: testPosition{X: -0.5},
+				Items:    []testItem{},
+				Tags:     []string{""},
+				Blob:     []byte{},
+				Key:      []byte{0x01, 0x02, 0x03},
+				Codes:    []uint16{},
+				Rest:     []byte{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := Marshal(&test.message)
+
+			if err != nil {
+				t.Fatalf("marshal failed: %s", err.Error())
+			}
+
+			if data[0] != 13 || data[1] != 6 {
+				t.Fatalf("header is %x %x", data[0], data[1])
+			}
+
+			decoded := testMessage{}
+
+			if err = Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("unmarshal failed: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(decoded, test.message) {
+				t.Errorf("round trip changed the message\n got %+v\nwant %+v", decoded, test.message)
+			}
+
+			again, err := Marshal(decoded)
+
+			if err != nil {
+				t.Fatalf("marshal of decoded message failed: %s", err.Error())
+			}
+
+			if !bytes.Equal(again, data) {
+				t.Errorf("encoding differs after a round trip\n got %x\nwant %x", again, data)
+			}
+		})
+	}
+}
+
+func TestEncodingLayout(t *testing.T) {
+	message := struct {
+		ID    uint32  `dr:"u24"`
+		Delta int32   `dr:"i24"`
+		X     float32 `dr:"drfloat"`
+		Name  string
+		Flags byte
+		Skip  uint16 `dr:"if=Flags&0x01"`
+		Data  []byte `dr:"prefix=u8"`
+	}{
+		ID:    0x030201,
+		Delta: -1,
+		X:     1,
+		Name:  "ab",
+		Flags: 0x02,
+		Skip:  0xFFFF,
+		Data:  []byte{0x09},
+	}
+
+	data, err := Marshal(message)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{
+		0x01, 0x02, 0x03, // u24
+		0x01, 0x00, 0x80, // i24, the top bit is the sign
+		0x00, 0x01, 0x00, 0x00, // drfloat 1 * 256
+		'a', 'b', 0x00, // cstring
+		0x02,       // Flags, Skip is left out
+		0x01, 0x09, // prefixed bytes
+	}
+
+	if !bytes.Equal(data, expected) {
+		t.Errorf("got %x, expected %x", data, expected)
+	}
+}
+
+func TestHeaderMismatch(t *testing.T) {
+	data, err := Marshal(testHeaderOnly{Value: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Unmarshal(data, &testMessage{}); !errors.Is(err, ErrHeaderMismatch) {
+		t.Errorf("expected ErrHeaderMismatch, got %v", err)
+	}
+
+	channel, messageType, ok := Header(&testHeaderOnly{})
+
+	if !ok || channel != 13 || messageType != 7 {
+		t.Errorf("header is %d %d %v", channel, messageType, ok)
+	}
+}
+
+func TestLengthFieldMismatch(t *testing.T) {
+	message := testMessage{Count: 3, Items: []testItem{{ID: 1}}, Key: []byte{1, 2, 3}}
+
+	if _, err := Marshal(message); err == nil {
+		t.Error("expected an error when the slice does not match its length field")
+	}
+
+	message = testMessage{Key: []byte{1}}
+
+	if _, err := Marshal(message); err == nil {
+		t.Error("expected an error when a fixed size field is the wrong size")
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	message := testMessage{
+		Flags:    0x03,
+		EntityID: 5,
+		Name:     "Ellie",
+		Title:    "Title",
+		Mode:     2,
+		Target:   1,
+		Count:    1,
+		Items:    []testItem{{ID: 2, Name: "Shield"}},
+		Tags:     []string{"tag"},
+		Blob:     []byte{1, 2, 3, 4},
+		Key:      []byte{1, 2, 3},
+		Codes:    []uint16{1},
+	}
+
+	data, err := Marshal(message)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rest takes whatever is left so only cutting into the fields before it can fail
+	restStart := len(data)
+
+	for length := 0; length < restStart; length++ {
+		decoded := testMessage{}
+
+		if err := Unmarshal(data[:length], &decoded); err == nil {
+			t.Errorf("decoding %d of %d bytes did not fail", length, len(data))
+		}
+	}
+}
+
+func TestInvalidLayouts(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"unknown wire type", &struct {
+			A uint32 `dr:"u128"`
+		}{}},
+		{"condition before field", &struct {
+			A byte `dr:"if=B"`
+			B byte
+		}{}},
+		{"len before field", &struct {
+			A []byte `dr:"len=B"`
+			B byte
+		}{}},
+		{"cstring on a number", &struct {
+			A uint32 `dr:"cstring"`
+		}{}},
+		{"unsupported type", &struct {
+			A map[string]int
+		}{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Marshal(test.value); err == nil {
+				t.Error("expected marshal to fail")
+			}
+
+			if err := Unmarshal([]byte{0x00, 0x00, 0x00, 0x00}, test.value); err == nil {
+				t.Error("expected unmarshal to fail")
+			}
+		})
+	}
+}